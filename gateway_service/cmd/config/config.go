@@ -0,0 +1,396 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+var logLevels = map[string]slog.Level{
+	"DEBUG": slog.LevelDebug,
+	"INFO":  slog.LevelInfo,
+	"WARN":  slog.LevelWarn,
+	"ERROR": slog.LevelError,
+}
+
+type JwtConfig struct {
+	Secret     string        `yaml:"secret"`
+	SecretFile string        `yaml:"secret_file"`
+	ExpiresIn  time.Duration `yaml:"expires_in"`
+}
+
+type UserServiceConfig struct {
+	Host            string        `yaml:"host"`
+	Port            int           `yaml:"port"`
+	Timeout         time.Duration `yaml:"timeout"`
+	AuthTimeout     time.Duration `yaml:"auth_timeout"`
+	RetryAttempts   int           `yaml:"retry_attempts"`
+	BreakerFailures int           `yaml:"breaker_failures"`
+	BreakerCooldown time.Duration `yaml:"breaker_cooldown"`
+	// TLSServerName is checked against the user service certificate instead
+	// of Host.
+	TLSServerName string `yaml:"tls_server_name"`
+}
+
+type S3Config struct {
+	Endpoint      string `yaml:"endpoint"`
+	Region        string `yaml:"region"`
+	Bucket        string `yaml:"bucket"`
+	AccessKey     string `yaml:"access_key"`
+	SecretKey     string `yaml:"secret_key"`
+	SecretKeyFile string `yaml:"secret_key_file"`
+}
+
+type MediaConfig struct {
+	Storage   string   `yaml:"storage"`
+	LocalPath string   `yaml:"local_path"`
+	S3        S3Config `yaml:"s3"`
+}
+
+type TracingConfig struct {
+	Exporter string `yaml:"exporter"`
+	Endpoint string `yaml:"endpoint"`
+	Insecure bool   `yaml:"insecure"`
+}
+
+type RateLimitPolicy struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+}
+
+type RedisConfig struct {
+	Addr         string `yaml:"addr"`
+	Password     string `yaml:"password"`
+	PasswordFile string `yaml:"password_file"`
+	DB           int    `yaml:"db"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool            `yaml:"enabled"`
+	Store   string          `yaml:"store"`
+	Redis   RedisConfig     `yaml:"redis"`
+	Auth    RateLimitPolicy `yaml:"auth"`
+	Media   RateLimitPolicy `yaml:"media"`
+	Default RateLimitPolicy `yaml:"default"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins"`
+	AllowCredentials bool          `yaml:"allow_credentials"`
+	AllowedHeaders   []string      `yaml:"allowed_headers"`
+	MaxAge           time.Duration `yaml:"max_age"`
+}
+
+type IdempotencyConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Store   string        `yaml:"store"`
+	Redis   RedisConfig   `yaml:"redis"`
+	TTL     time.Duration `yaml:"ttl"`
+	LockTTL time.Duration `yaml:"lock_ttl"`
+}
+
+type GraphQLConfig struct {
+	MaxDepth      int `yaml:"max_depth"`
+	MaxComplexity int `yaml:"max_complexity"`
+}
+
+type DeprecationConfig struct {
+	Deprecation time.Time `yaml:"deprecation"`
+	Sunset      time.Time `yaml:"sunset"`
+}
+
+type UnversionedConfig struct {
+	Enabled           bool `yaml:"enabled"`
+	DeprecationConfig `yaml:",inline"`
+}
+
+type APIConfig struct {
+	// Unversioned serves the paths from before /v1 as its aliases.
+	Unversioned UnversionedConfig `yaml:"unversioned"`
+	V1          DeprecationConfig `yaml:"v1"`
+}
+
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	CAFile   string `yaml:"ca_file"`
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+type Config struct {
+	AppName             string            `yaml:"app_name"`
+	AppVersion          string            `yaml:"app_version"`
+	LogLevel            string            `yaml:"log_level"`
+	LogFormat           string            `yaml:"log_format"`
+	LogSampleInitial    int               `yaml:"log_sample_initial"`
+	LogSampleThereafter int               `yaml:"log_sample_thereafter"`
+	Port                int               `yaml:"port"`
+	TrustedProxies      []string          `yaml:"trusted_proxies"`
+	ShutdownTimeout     time.Duration     `yaml:"shutdown_timeout"`
+	JWT                 JwtConfig         `yaml:"jwt"`
+	UserService         UserServiceConfig `yaml:"user_service"`
+	Media               MediaConfig       `yaml:"media"`
+	Tracing             TracingConfig     `yaml:"tracing"`
+	TLS                 TLSConfig         `yaml:"tls"`
+	RateLimit           RateLimitConfig   `yaml:"rate_limit"`
+	CORS                CORSConfig        `yaml:"cors"`
+	GraphQL             GraphQLConfig     `yaml:"graphql"`
+	Idempotency         IdempotencyConfig `yaml:"idempotency"`
+	API                 APIConfig         `yaml:"api"`
+}
+
+func (c Config) Level() slog.Level {
+	return logLevels[strings.ToUpper(c.LogLevel)]
+}
+
+// Load builds the config from defaults, then the YAML or TOML file given by
+// -config or CONFIG_FILE, then environment variables, then flags.
+func Load(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("http_service", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	port := fs.Int("port", 0, "port to listen on")
+	logLevel := fs.String("log-level", "", "one of DEBUG, INFO, WARN, ERROR")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	cfg := &Config{
+		AppName:             "gateway-service",
+		LogLevel:            "INFO",
+		LogFormat:           "json",
+		LogSampleInitial:    100,
+		LogSampleThereafter: 100,
+		Port:                8081,
+		ShutdownTimeout:     15 * time.Second,
+		JWT: JwtConfig{
+			ExpiresIn: 6 * time.Hour,
+		},
+		UserService: UserServiceConfig{
+			Port:            50051,
+			Timeout:         5 * time.Second,
+			AuthTimeout:     10 * time.Second,
+			RetryAttempts:   3,
+			BreakerFailures: 5,
+			BreakerCooldown: 10 * time.Second,
+		},
+		Media: MediaConfig{
+			Storage:   "local",
+			LocalPath: "./data",
+		},
+		Tracing: TracingConfig{
+			Exporter: "none",
+			Endpoint: "localhost:4317",
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Store:   "memory",
+			Redis: RedisConfig{
+				Addr: "localhost:6379",
+			},
+			Auth:    RateLimitPolicy{Requests: 10, Per: time.Minute},
+			Media:   RateLimitPolicy{Requests: 20, Per: time.Minute},
+			Default: RateLimitPolicy{Requests: 120, Per: time.Minute},
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+			AllowedHeaders: []string{"Accept", "Authorization", "Cache-Control", "Content-Type", "Idempotency-Key", "If-Match", "If-None-Match", "X-CSRF-Token", "X-Requested-With", "X-Request-ID"},
+			MaxAge:         10 * time.Minute,
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:      6,
+			MaxComplexity: 1000,
+		},
+		Idempotency: IdempotencyConfig{
+			Enabled: true,
+			Store:   "memory",
+			Redis: RedisConfig{
+				Addr: "localhost:6379",
+			},
+			TTL:     24 * time.Hour,
+			LockTTL: time.Minute,
+		},
+		API: APIConfig{
+			Unversioned: UnversionedConfig{Enabled: true},
+		},
+	}
+	l := &loader{}
+	if *configFile != "" {
+		l.file(*configFile, cfg)
+	}
+	l.string(&cfg.AppName, "APP_NAME")
+	l.string(&cfg.AppVersion, "APP_VERSION")
+	l.string(&cfg.LogLevel, "LOG_LEVEL")
+	l.string(&cfg.LogFormat, "LOG_FORMAT")
+	l.int(&cfg.LogSampleInitial, "LOG_SAMPLE_INITIAL")
+	l.int(&cfg.LogSampleThereafter, "LOG_SAMPLE_THEREAFTER")
+	l.int(&cfg.Port, "PORT")
+	l.list(&cfg.TrustedProxies, "TRUSTED_PROXIES")
+	l.duration(&cfg.ShutdownTimeout, "SHUTDOWN_TIMEOUT")
+	l.string(&cfg.JWT.Secret, "JWT_SECRET")
+	l.string(&cfg.JWT.SecretFile, "JWT_SECRET_FILE")
+	l.duration(&cfg.JWT.ExpiresIn, "JWT_EXPIRES_AT")
+	l.string(&cfg.UserService.Host, "USER_SERVICE_HOST")
+	l.int(&cfg.UserService.Port, "USER_SERVICE_PORT")
+	l.duration(&cfg.UserService.Timeout, "USER_SERVICE_TIMEOUT")
+	l.duration(&cfg.UserService.AuthTimeout, "USER_SERVICE_AUTH_TIMEOUT")
+	l.int(&cfg.UserService.RetryAttempts, "USER_SERVICE_RETRY_ATTEMPTS")
+	l.int(&cfg.UserService.BreakerFailures, "USER_SERVICE_BREAKER_FAILURES")
+	l.duration(&cfg.UserService.BreakerCooldown, "USER_SERVICE_BREAKER_COOLDOWN")
+	l.string(&cfg.UserService.TLSServerName, "USER_SERVICE_TLS_SERVER_NAME")
+	l.string(&cfg.Media.Storage, "MEDIA_STORAGE")
+	l.string(&cfg.Media.LocalPath, "MEDIA_LOCAL_PATH")
+	l.string(&cfg.Media.S3.Endpoint, "S3_ENDPOINT")
+	l.string(&cfg.Media.S3.Region, "S3_REGION")
+	l.string(&cfg.Media.S3.Bucket, "S3_BUCKET")
+	l.string(&cfg.Media.S3.AccessKey, "S3_ACCESS_KEY")
+	l.string(&cfg.Media.S3.SecretKey, "S3_SECRET_KEY")
+	l.string(&cfg.Media.S3.SecretKeyFile, "S3_SECRET_KEY_FILE")
+	l.string(&cfg.Tracing.Exporter, "TRACING_EXPORTER")
+	l.string(&cfg.Tracing.Endpoint, "TRACING_ENDPOINT")
+	l.bool(&cfg.Tracing.Insecure, "TRACING_INSECURE")
+	l.string(&cfg.TLS.CertFile, "TLS_CERT_FILE")
+	l.string(&cfg.TLS.KeyFile, "TLS_KEY_FILE")
+	l.string(&cfg.TLS.CAFile, "TLS_CA_FILE")
+	l.bool(&cfg.RateLimit.Enabled, "RATE_LIMIT_ENABLED")
+	l.string(&cfg.RateLimit.Store, "RATE_LIMIT_STORE")
+	l.string(&cfg.RateLimit.Redis.Addr, "RATE_LIMIT_REDIS_ADDR")
+	l.string(&cfg.RateLimit.Redis.Password, "RATE_LIMIT_REDIS_PASSWORD")
+	l.string(&cfg.RateLimit.Redis.PasswordFile, "RATE_LIMIT_REDIS_PASSWORD_FILE")
+	l.int(&cfg.RateLimit.Redis.DB, "RATE_LIMIT_REDIS_DB")
+	l.int(&cfg.RateLimit.Auth.Requests, "RATE_LIMIT_AUTH_REQUESTS")
+	l.duration(&cfg.RateLimit.Auth.Per, "RATE_LIMIT_AUTH_PER")
+	l.int(&cfg.RateLimit.Media.Requests, "RATE_LIMIT_MEDIA_REQUESTS")
+	l.duration(&cfg.RateLimit.Media.Per, "RATE_LIMIT_MEDIA_PER")
+	l.int(&cfg.RateLimit.Default.Requests, "RATE_LIMIT_DEFAULT_REQUESTS")
+	l.duration(&cfg.RateLimit.Default.Per, "RATE_LIMIT_DEFAULT_PER")
+	l.list(&cfg.CORS.AllowedOrigins, "CORS_ALLOWED_ORIGINS")
+	l.bool(&cfg.CORS.AllowCredentials, "CORS_ALLOW_CREDENTIALS")
+	l.list(&cfg.CORS.AllowedHeaders, "CORS_ALLOWED_HEADERS")
+	l.duration(&cfg.CORS.MaxAge, "CORS_MAX_AGE")
+	l.int(&cfg.GraphQL.MaxDepth, "GRAPHQL_MAX_DEPTH")
+	l.int(&cfg.GraphQL.MaxComplexity, "GRAPHQL_MAX_COMPLEXITY")
+	l.bool(&cfg.Idempotency.Enabled, "IDEMPOTENCY_ENABLED")
+	l.string(&cfg.Idempotency.Store, "IDEMPOTENCY_STORE")
+	l.string(&cfg.Idempotency.Redis.Addr, "IDEMPOTENCY_REDIS_ADDR")
+	l.string(&cfg.Idempotency.Redis.Password, "IDEMPOTENCY_REDIS_PASSWORD")
+	l.string(&cfg.Idempotency.Redis.PasswordFile, "IDEMPOTENCY_REDIS_PASSWORD_FILE")
+	l.int(&cfg.Idempotency.Redis.DB, "IDEMPOTENCY_REDIS_DB")
+	l.duration(&cfg.Idempotency.TTL, "IDEMPOTENCY_TTL")
+	l.duration(&cfg.Idempotency.LockTTL, "IDEMPOTENCY_LOCK_TTL")
+	l.bool(&cfg.API.Unversioned.Enabled, "API_UNVERSIONED_ENABLED")
+	l.time(&cfg.API.Unversioned.Deprecation, "API_UNVERSIONED_DEPRECATION")
+	l.time(&cfg.API.Unversioned.Sunset, "API_UNVERSIONED_SUNSET")
+	l.time(&cfg.API.V1.Deprecation, "API_V1_DEPRECATION")
+	l.time(&cfg.API.V1.Sunset, "API_V1_SUNSET")
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			cfg.Port = *port
+		case "log-level":
+			cfg.LogLevel = *logLevel
+		}
+	})
+	l.secret(&cfg.JWT.Secret, cfg.JWT.SecretFile, "JWT_SECRET")
+	l.secret(&cfg.RateLimit.Redis.Password, cfg.RateLimit.Redis.PasswordFile, "RATE_LIMIT_REDIS_PASSWORD")
+	l.secret(&cfg.Idempotency.Redis.Password, cfg.Idempotency.Redis.PasswordFile, "IDEMPOTENCY_REDIS_PASSWORD")
+	if _, ok := logLevels[strings.ToUpper(cfg.LogLevel)]; !ok {
+		l.fail("LOG_LEVEL must be one of DEBUG, INFO, WARN, ERROR")
+	}
+	if cfg.LogFormat != "json" && cfg.LogFormat != "text" {
+		l.fail("LOG_FORMAT must be one of json, text")
+	}
+	if cfg.LogSampleInitial < 0 || cfg.LogSampleThereafter < 0 {
+		l.fail("LOG_SAMPLE_INITIAL and LOG_SAMPLE_THEREAFTER must not be negative")
+	}
+	l.port(cfg.Port, "PORT")
+	if cfg.ShutdownTimeout <= 0 {
+		l.fail("SHUTDOWN_TIMEOUT must be positive")
+	}
+	l.required(cfg.JWT.Secret, "JWT_SECRET")
+	if cfg.JWT.ExpiresIn <= 0 {
+		l.fail("JWT_EXPIRES_AT must be positive")
+	}
+	l.required(cfg.UserService.Host, "USER_SERVICE_HOST")
+	l.port(cfg.UserService.Port, "USER_SERVICE_PORT")
+	if cfg.UserService.Timeout <= 0 || cfg.UserService.AuthTimeout <= 0 {
+		l.fail("USER_SERVICE_TIMEOUT and USER_SERVICE_AUTH_TIMEOUT must be positive")
+	}
+	if cfg.UserService.RetryAttempts < 1 || cfg.UserService.RetryAttempts > 5 {
+		l.fail("USER_SERVICE_RETRY_ATTEMPTS must be between 1 and 5")
+	}
+	if cfg.UserService.BreakerFailures < 1 {
+		l.fail("USER_SERVICE_BREAKER_FAILURES must be positive")
+	}
+	if cfg.UserService.BreakerCooldown <= 0 {
+		l.fail("USER_SERVICE_BREAKER_COOLDOWN must be positive")
+	}
+	switch cfg.Media.Storage {
+	case "local":
+		l.required(cfg.Media.LocalPath, "MEDIA_LOCAL_PATH")
+	case "s3":
+		l.secret(&cfg.Media.S3.SecretKey, cfg.Media.S3.SecretKeyFile, "S3_SECRET_KEY")
+		l.required(cfg.Media.S3.Endpoint, "S3_ENDPOINT")
+		l.required(cfg.Media.S3.Region, "S3_REGION")
+		l.required(cfg.Media.S3.Bucket, "S3_BUCKET")
+		l.required(cfg.Media.S3.AccessKey, "S3_ACCESS_KEY")
+		l.required(cfg.Media.S3.SecretKey, "S3_SECRET_KEY")
+	default:
+		l.fail("MEDIA_STORAGE must be one of local, s3")
+	}
+	switch cfg.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		l.required(cfg.Tracing.Endpoint, "TRACING_ENDPOINT")
+	default:
+		l.fail("TRACING_EXPORTER must be one of none, stdout, otlp")
+	}
+	if (cfg.TLS.CertFile != "") != (cfg.TLS.KeyFile != "") || (cfg.TLS.CertFile != "") != (cfg.TLS.CAFile != "") {
+		l.fail("TLS_CERT_FILE, TLS_KEY_FILE and TLS_CA_FILE must be set together")
+	}
+	switch cfg.RateLimit.Store {
+	case "memory":
+	case "redis":
+		l.required(cfg.RateLimit.Redis.Addr, "RATE_LIMIT_REDIS_ADDR")
+	default:
+		l.fail("RATE_LIMIT_STORE must be one of memory, redis")
+	}
+	l.rateLimit(cfg.RateLimit.Auth, "RATE_LIMIT_AUTH")
+	l.rateLimit(cfg.RateLimit.Media, "RATE_LIMIT_MEDIA")
+	l.rateLimit(cfg.RateLimit.Default, "RATE_LIMIT_DEFAULT")
+	for _, origin := range cfg.CORS.AllowedOrigins {
+		if _, err := path.Match(origin, ""); err != nil {
+			l.fail("CORS_ALLOWED_ORIGINS has a bad pattern %q", origin)
+		}
+		if origin == "*" && cfg.CORS.AllowCredentials {
+			l.fail("CORS_ALLOW_CREDENTIALS cannot be used with * in CORS_ALLOWED_ORIGINS")
+		}
+	}
+	if cfg.CORS.MaxAge < 0 {
+		l.fail("CORS_MAX_AGE must not be negative")
+	}
+	if cfg.GraphQL.MaxDepth < 1 || cfg.GraphQL.MaxComplexity < 1 {
+		l.fail("GRAPHQL_MAX_DEPTH and GRAPHQL_MAX_COMPLEXITY must be positive")
+	}
+	switch cfg.Idempotency.Store {
+	case "memory":
+	case "redis":
+		l.required(cfg.Idempotency.Redis.Addr, "IDEMPOTENCY_REDIS_ADDR")
+	default:
+		l.fail("IDEMPOTENCY_STORE must be one of memory, redis")
+	}
+	if cfg.Idempotency.TTL <= 0 || cfg.Idempotency.LockTTL <= 0 {
+		l.fail("IDEMPOTENCY_TTL and IDEMPOTENCY_LOCK_TTL must be positive")
+	}
+	l.deprecation(cfg.API.Unversioned.DeprecationConfig, "API_UNVERSIONED")
+	l.deprecation(cfg.API.V1, "API_V1")
+	if err := l.err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
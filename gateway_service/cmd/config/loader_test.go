@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoaderFile(t *testing.T) {
+	want := &Config{
+		AppName:         "gateway",
+		Port:            8081,
+		TrustedProxies:  []string{"10.0.0.0/8"},
+		ShutdownTimeout: 15 * time.Second,
+		UserService:     UserServiceConfig{Host: "users", Timeout: 2 * time.Second},
+		API: APIConfig{
+			Unversioned: UnversionedConfig{
+				Enabled:           true,
+				DeprecationConfig: DeprecationConfig{Sunset: time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		bad     bool
+	}{
+		{
+			name: "yaml",
+			file: "config.yaml",
+			content: `
+app_name: gateway
+port: 8081
+trusted_proxies: [10.0.0.0/8]
+shutdown_timeout: 15s
+user_service:
+  host: users
+  timeout: 2s
+api:
+  unversioned:
+    enabled: true
+    sunset: 2027-01-31T00:00:00Z
+`,
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			content: `
+app_name = "gateway"
+port = 8081
+trusted_proxies = ["10.0.0.0/8"]
+shutdown_timeout = "15s"
+
+[user_service]
+host = "users"
+timeout = "2s"
+
+[api.unversioned]
+enabled = true
+sunset = 2027-01-31T00:00:00Z
+`,
+		},
+		{
+			name:    "yaml named toml",
+			file:    "config.TOML",
+			content: `app_name: gateway`,
+			bad:     true,
+		},
+		{
+			name:    "bad duration in toml",
+			file:    "config.toml",
+			content: `shutdown_timeout = 15`,
+			bad:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			l := &loader{}
+			got := &Config{}
+			l.file(path, got)
+			if tt.bad {
+				if l.err() == nil {
+					t.Fatal("no error")
+				}
+				return
+			}
+			if err := l.err(); err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,461 @@
+package docs
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// undocumented are the routes Check leaves out of the spec: the scrape
+// endpoint and the docs themselves.
+var undocumented = map[string]bool{
+	"/metrics":      true,
+	"/openapi.json": true,
+	"/docs":         true,
+}
+
+// pathParams describes the path params that are not positive integer IDs.
+var pathParams = map[string]map[string]interface{}{
+	"mediaID":  {"type": "string", "pattern": "^[0-9a-f]{64}$"},
+	"itemType": {"type": "string", "enum": []string{"user", "message"}},
+}
+
+var responseNames = map[int]string{
+	http.StatusBadRequest:            "BadRequest",
+	http.StatusUnauthorized:          "Unauthorized",
+	http.StatusForbidden:             "Forbidden",
+	http.StatusNotFound:              "NotFound",
+	http.StatusConflict:              "Conflict",
+	http.StatusPreconditionFailed:    "PreconditionFailed",
+	http.StatusRequestEntityTooLarge: "PayloadTooLarge",
+	http.StatusUnsupportedMediaType:  "UnsupportedMediaType",
+	http.StatusUnprocessableEntity:   "UnprocessableEntity",
+	http.StatusTooManyRequests:       "TooManyRequests",
+	http.StatusInternalServerError:   "InternalServerError",
+	http.StatusServiceUnavailable:    "ServiceUnavailable",
+	http.StatusGatewayTimeout:        "GatewayTimeout",
+}
+
+type object = map[string]interface{}
+
+// Spec renders the OpenAPI 3 document of the routes in operations.
+func Spec(version string) ([]byte, error) {
+	s := &schemas{components: object{}}
+	s.components["StandardResponse"] = object{
+		"type": "object",
+		"properties": object{
+			"code":       object{"type": "integer"},
+			"message":    object{"type": "string"},
+			"data":       object{"nullable": true},
+			"request_id": object{"type": "string", "description": "Only set on errors"},
+		},
+		"required": []string{"code", "message", "data"},
+	}
+	responses := object{}
+	for status, name := range responseNames {
+		response := object{
+			"description": http.StatusText(status),
+			"content": object{
+				"application/json": object{"schema": object{"$ref": "#/components/schemas/StandardResponse"}},
+			},
+		}
+		if status == http.StatusTooManyRequests {
+			response["headers"] = object{
+				"Retry-After": object{"schema": object{"type": "integer"}, "description": "Seconds until a request may succeed"},
+			}
+		}
+		responses[name] = response
+	}
+	paths := object{}
+	for _, op := range operations {
+		openAPIPath, params := convertPath(op.path)
+		pathItem, ok := paths[openAPIPath].(object)
+		if !ok {
+			pathItem = object{}
+			paths[openAPIPath] = pathItem
+		}
+		pathItem[strings.ToLower(op.method)] = s.operation(op, params)
+	}
+	spec := object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":       "Social media gateway",
+			"version":     version,
+			"description": "Paths without the " + V1Prefix + " prefix are deprecated aliases of " + V1Prefix + ", answered with Deprecation and Sunset headers until they are removed.",
+		},
+		"paths": paths,
+		"components": object{
+			"schemas":   s.components,
+			"responses": responses,
+			"securitySchemes": object{
+				"bearerAuth": object{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+	}
+	return json.Marshal(spec)
+}
+
+// convertPath turns a gin path into an OpenAPI one and returns its params.
+func convertPath(ginPath string) (string, []string) {
+	segments := strings.Split(ginPath, "/")
+	params := []string{}
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+type schemas struct {
+	components object
+}
+
+func (s *schemas) operation(op operation, params []string) object {
+	statuses := append([]int{http.StatusInternalServerError}, op.errors...)
+	parameters := []object{}
+	for _, name := range params {
+		schema, ok := pathParams[name]
+		if !ok {
+			schema = object{"type": "integer", "minimum": 1}
+		}
+		parameters = append(parameters, object{"name": name, "in": "path", "required": true, "schema": schema})
+	}
+	for _, query := range op.query {
+		parameters = append(parameters, s.queryParams(reflect.TypeOf(query))...)
+	}
+	// The Idempotency middleware answers a reused key with 409 while the first
+	// request runs and with 422 when the requests differ, and a body over
+	// MaxJSONBody with 413. It ignores the key on logins and uploads.
+	if op.method == http.MethodPost && op.path != "/login" && !op.upload {
+		parameters = append(parameters, object{
+			"name":        "Idempotency-Key",
+			"in":          "header",
+			"description": "Retries with the same key and request get the first response again",
+			"schema":      object{"type": "string", "maxLength": 255},
+		})
+		statuses = append(statuses, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusRequestEntityTooLarge)
+	}
+	if op.etag {
+		parameters = append(parameters, object{
+			"name":        "If-None-Match",
+			"in":          "header",
+			"description": "ETags of a copy the client has, answered with 304 when one is current",
+			"schema":      object{"type": "string"},
+		})
+	}
+	if op.ifMatch {
+		parameters = append(parameters, object{
+			"name":        "If-Match",
+			"in":          "header",
+			"description": "ETags of copies the client read, answered with 412 when none is current",
+			"schema":      object{"type": "string"},
+		})
+		statuses = append(statuses, http.StatusPreconditionFailed)
+	}
+	operation := object{
+		"tags":        []string{op.tag},
+		"summary":     op.summary,
+		"operationId": operationID(op),
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+		statuses = append(statuses, http.StatusBadRequest)
+	}
+	if op.body != nil {
+		operation["requestBody"] = object{
+			"required": true,
+			"content":  object{"application/json": object{"schema": s.of(reflect.TypeOf(op.body))}},
+		}
+		statuses = append(statuses, http.StatusBadRequest)
+	}
+	if op.upload {
+		operation["requestBody"] = object{
+			"required": true,
+			"content": object{"multipart/form-data": object{"schema": object{
+				"type":       "object",
+				"properties": object{"file": object{"type": "string", "format": "binary"}},
+				"required":   []string{"file"},
+			}}},
+		}
+		statuses = append(statuses, http.StatusBadRequest)
+	}
+	if op.auth {
+		operation["security"] = []object{{"bearerAuth": []string{}}}
+		statuses = append(statuses, http.StatusUnauthorized)
+	}
+	// Probes are registered ahead of the rate limiter.
+	if op.tag != "health" {
+		statuses = append(statuses, http.StatusTooManyRequests)
+	}
+	responses := object{}
+	success := object{"description": http.StatusText(op.status)}
+	switch {
+	case op.produces != "":
+		schema := object{"type": "string", "format": "binary"}
+		if op.data != nil {
+			schema = s.of(reflect.TypeOf(op.data))
+		}
+		success["content"] = object{op.produces: object{"schema": schema}}
+	case op.status != http.StatusSwitchingProtocols:
+		data := object{"nullable": true}
+		if op.data != nil {
+			data = s.of(reflect.TypeOf(op.data))
+		}
+		success["content"] = object{"application/json": object{"schema": object{
+			"allOf": []object{
+				{"$ref": "#/components/schemas/StandardResponse"},
+				{"type": "object", "properties": object{"data": data}},
+			},
+		}}}
+	}
+	if op.etag {
+		success["headers"] = object{
+			"ETag": object{"schema": object{"type": "string"}, "description": "Changes whenever the resource does"},
+		}
+		responses[strconv.Itoa(http.StatusNotModified)] = object{"description": http.StatusText(http.StatusNotModified)}
+	}
+	responses[strconv.Itoa(op.status)] = success
+	for _, status := range statuses {
+		responses[strconv.Itoa(status)] = object{"$ref": "#/components/responses/" + responseNames[status]}
+	}
+	operation["responses"] = responses
+	return operation
+}
+
+func operationID(op operation) string {
+	id := strings.ToLower(op.method)
+	for _, segment := range strings.Split(op.path, "/") {
+		segment = strings.TrimPrefix(segment, ":")
+		for _, word := range strings.Split(segment, "-") {
+			if word != "" {
+				id += strings.ToUpper(word[:1]) + word[1:]
+			}
+		}
+	}
+	return id
+}
+
+func (s *schemas) queryParams(t reflect.Type) []object {
+	params := []object{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("form")
+		if name == "" || name == "-" {
+			continue
+		}
+		schema := s.of(field.Type)
+		required := applyValidation(schema, field.Tag.Get("validate"))
+		params = append(params, object{"name": name, "in": "query", "required": required, "schema": schema})
+	}
+	return params
+}
+
+// of returns the schema of t, adding structs to the components under their
+// package and type name, such as req.LoginDto or user.FindByIDResp.
+func (s *schemas) of(t reflect.Type) object {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return s.of(t.Elem())
+	case reflect.Struct:
+		name := path.Base(t.PkgPath()) + "." + t.Name()
+		if _, ok := s.components[name]; !ok {
+			// Claim the name first so self-referencing types terminate.
+			s.components[name] = object{}
+			s.components[name] = s.object(t)
+		}
+		return object{"$ref": "#/components/schemas/" + name}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return object{"type": "string", "format": "byte"}
+		}
+		return object{"type": "array", "items": s.of(t.Elem())}
+	case reflect.Map:
+		return object{"type": "object", "additionalProperties": s.of(t.Elem())}
+	case reflect.String:
+		return object{"type": "string"}
+	case reflect.Bool:
+		return object{"type": "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return object{"type": "integer", "format": "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return object{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return object{"type": "number"}
+	default:
+		return object{}
+	}
+}
+
+func (s *schemas) object(t reflect.Type) object {
+	properties := object{}
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema := s.of(field.Type)
+		if applyValidation(schema, field.Tag.Get("validate")) {
+			required = append(required, name)
+		}
+		properties[name] = schema
+	}
+	schema := object{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// applyValidation carries the validator tag rules that have an OpenAPI
+// counterpart over to schema, and reports whether the field is required.
+// Rules after dive apply to the items of an array.
+func applyValidation(schema object, tag string) bool {
+	if _, isRef := schema["$ref"]; isRef || tag == "" {
+		return false
+	}
+	required := false
+	diving := false
+	target := schema
+	for _, rule := range strings.Split(tag, ",") {
+		name, value, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = !diving
+		case "dive":
+			items, ok := schema["items"].(object)
+			if !ok {
+				return required
+			}
+			diving = true
+			target = items
+		case "email":
+			target["format"] = "email"
+		case "hexadecimal":
+			target["pattern"] = "^[0-9a-fA-F]+$"
+		case "oneof":
+			target["enum"] = strings.Fields(value)
+		case "unique":
+			target["uniqueItems"] = true
+		case "min", "max", "len", "gt":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			applyBound(target, name, n)
+		}
+	}
+	return required
+}
+
+func applyBound(schema object, rule string, n int) {
+	var lower, upper string
+	switch schema["type"] {
+	case "string":
+		lower, upper = "minLength", "maxLength"
+	case "array":
+		lower, upper = "minItems", "maxItems"
+	case "integer", "number":
+		lower, upper = "minimum", "maximum"
+	default:
+		return
+	}
+	switch rule {
+	case "min":
+		schema[lower] = n
+	case "max":
+		schema[upper] = n
+	case "len":
+		schema[lower] = n
+		schema[upper] = n
+	case "gt":
+		if lower == "minimum" {
+			schema["minimum"] = n
+			schema["exclusiveMinimum"] = true
+		} else {
+			schema[lower] = n + 1
+		}
+	}
+}
+
+// Check compares operations with the routes the router serves, so a route
+// added or removed without updating the spec is caught. Unversioned aliases
+// of v1 routes are left out of the spec on purpose.
+func Check(routes gin.RoutesInfo) error {
+	documented := map[string]bool{}
+	aliases := map[string]bool{}
+	for _, op := range operations {
+		documented[op.method+" "+op.path] = true
+		if strings.HasPrefix(op.path, V1Prefix+"/") {
+			aliases[op.method+" "+strings.TrimPrefix(op.path, V1Prefix)] = true
+		}
+	}
+	problems := []string{}
+	for _, route := range routes {
+		if route.Method == http.MethodOptions || undocumented[route.Path] {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		if aliases[key] {
+			continue
+		}
+		if !documented[key] {
+			problems = append(problems, "undocumented route "+key)
+		}
+		delete(documented, key)
+	}
+	for key := range documented {
+		problems = append(problems, "documented route not served "+key)
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return errors.New(strings.Join(problems, "; "))
+}
+
+func ServeSpec(spec []byte) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "application/json", spec)
+	}
+}
+
+const uiPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Social media gateway API</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@4.18.3/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@4.18.3/swagger-ui-bundle.js" crossorigin></script>
+<script>
+window.onload = () => {
+	window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+};
+</script>
+</body>
+</html>
+`
+
+// ServeUI serves Swagger UI, loaded from a CDN, pointed at /openapi.json.
+func ServeUI(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(uiPage))
+}
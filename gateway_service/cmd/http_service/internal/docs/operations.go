@@ -0,0 +1,331 @@
+package docs
+
+import (
+	"gatewayservice/internal/dto/req"
+	"gatewayservice/internal/dto/resp"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+
+	bookmarkPb "github.com/ideaspaper/social-media-proto/bookmark"
+	messagePb "github.com/ideaspaper/social-media-proto/message"
+	moderationPb "github.com/ideaspaper/social-media-proto/moderation"
+	userPb "github.com/ideaspaper/social-media-proto/user"
+)
+
+// operation describes one route of router.New. Request and response schemas
+// are generated from the same DTO and proto types the handlers use.
+type operation struct {
+	method  string
+	path    string
+	tag     string
+	summary string
+	auth    bool
+	// query holds the structs the handler binds with ShouldBindQuery.
+	query []interface{}
+	body  interface{}
+	// upload is set when the body is a multipart form with a file field.
+	upload bool
+	status int
+	// data is what the handler puts in StandardResponse.Data.
+	data interface{}
+	// produces replaces the JSON envelope for handlers that stream a file or
+	// events, or that answer in a format of their own described by data.
+	produces string
+	// errors lists the statuses the handler can answer with besides the ones
+	// every route shares.
+	errors []int
+	// etag is set when the response carries an ETag and If-None-Match is
+	// answered with 304, ifMatch when If-Match is honored.
+	etag    bool
+	ifMatch bool
+}
+
+// streamQuery documents the query params of the realtime routes, which
+// browsers use because EventSource and WebSocket cannot set headers.
+type streamQuery struct {
+	AccessToken string `form:"access_token"`
+	LastEventID string `form:"last_event_id"`
+}
+
+// idsQuery documents the ids of GET /users, which the transcoder also
+// accepts comma separated.
+type idsQuery struct {
+	IDs []int64 `form:"ids" validate:"required,min=1,max=100,dive,gt=0"`
+}
+
+var upstream = []int{http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+func withUpstream(statuses ...int) []int {
+	return append(statuses, upstream...)
+}
+
+// V1Prefix is where router.New serves the routes of v1Operations.
+const V1Prefix = "/v1"
+
+var operations = append(healthOperations, versioned(V1Prefix, v1Operations)...)
+
+// healthOperations are the probes, which are not versioned.
+var healthOperations = []operation{
+	{
+		method: http.MethodGet, path: "/healthz", tag: "health",
+		summary: "Liveness probe",
+		status:  http.StatusOK, data: resp.HealthDto{},
+		errors: []int{http.StatusServiceUnavailable},
+	},
+	{
+		method: http.MethodGet, path: "/readyz", tag: "health",
+		summary: "Readiness probe, fails while the user service is unreachable",
+		status:  http.StatusOK, data: resp.HealthDto{},
+		errors: []int{http.StatusServiceUnavailable},
+	},
+}
+
+// versioned returns ops with their paths under prefix.
+func versioned(prefix string, ops []operation) []operation {
+	result := make([]operation, 0, len(ops))
+	for _, op := range ops {
+		op.path = prefix + op.path
+		result = append(result, op)
+	}
+	return result
+}
+
+// v1Operations have paths relative to V1Prefix.
+var v1Operations = []operation{
+	{
+		method: http.MethodPost, path: "/register", tag: "users",
+		summary: "Register a user",
+		body:    req.UserDto{},
+		status:  http.StatusCreated, data: userPb.RegisterResp{},
+		errors: withUpstream(http.StatusConflict),
+	},
+	{
+		method: http.MethodPost, path: "/login", tag: "users",
+		summary: "Exchange credentials for an access token",
+		body:    req.LoginDto{},
+		status:  http.StatusOK, data: resp.LoginDto{},
+		errors: withUpstream(http.StatusUnauthorized, http.StatusForbidden),
+	},
+	{
+		method: http.MethodGet, path: "/users", tag: "users",
+		summary: "Find users in the order of their IDs, listing the IDs with no user",
+		query:   []interface{}{idsQuery{}},
+		status:  http.StatusOK, data: userPb.FindByIDsResp{},
+		errors: upstream,
+	},
+	{
+		method: http.MethodGet, path: "/users/:id", tag: "users",
+		summary: "Find a user",
+		status:  http.StatusOK, data: userPb.FindByIDResp{},
+		errors: withUpstream(http.StatusNotFound),
+		etag:   true,
+	},
+	{
+		method: http.MethodPost, path: "/users/:id/softdelete", tag: "users", auth: true,
+		summary: "Soft delete a user, only the user or a moderator may",
+		status:  http.StatusOK, data: userPb.DeleteByIDResp{},
+		errors:  withUpstream(http.StatusForbidden, http.StatusNotFound),
+		ifMatch: true,
+	},
+	{
+		method: http.MethodDelete, path: "/users/:id", tag: "users", auth: true,
+		summary: "Delete a user permanently, only the user or a moderator may",
+		status:  http.StatusOK, data: userPb.DeletePermanentlyByIDResp{},
+		errors:  withUpstream(http.StatusForbidden, http.StatusNotFound),
+		ifMatch: true,
+	},
+	{
+		method: http.MethodPost, path: "/conversations", tag: "messages", auth: true,
+		summary: "Start a conversation",
+		body:    req.ConversationDto{},
+		status:  http.StatusCreated, data: messagePb.CreateConversationResp{},
+		errors: withUpstream(http.StatusForbidden, http.StatusNotFound),
+	},
+	{
+		method: http.MethodGet, path: "/conversations", tag: "messages", auth: true,
+		summary: "List the caller's conversations",
+		query:   []interface{}{req.PageDto{}},
+		status:  http.StatusOK, data: messagePb.FindConversationsResp{},
+		errors: upstream,
+	},
+	{
+		method: http.MethodGet, path: "/conversations/unread", tag: "messages", auth: true,
+		summary: "Count unread messages",
+		status:  http.StatusOK, data: messagePb.CountUnreadResp{},
+		errors: upstream,
+	},
+	{
+		method: http.MethodGet, path: "/conversations/:conversationID", tag: "messages", auth: true,
+		summary: "Find a conversation",
+		status:  http.StatusOK, data: messagePb.FindConversationByIDResp{},
+		errors: withUpstream(http.StatusNotFound),
+	},
+	{
+		method: http.MethodGet, path: "/conversations/:conversationID/messages", tag: "messages", auth: true,
+		summary: "List messages, newest first",
+		query:   []interface{}{req.PageDto{}},
+		status:  http.StatusOK, data: messagePb.FindMessagesResp{},
+		errors: withUpstream(http.StatusNotFound),
+	},
+	{
+		method: http.MethodPost, path: "/conversations/:conversationID/messages", tag: "messages", auth: true,
+		summary: "Send a message, attaching only media the sender uploaded",
+		body:    req.MessageDto{},
+		status:  http.StatusCreated, data: messagePb.SendMessageResp{},
+		errors: withUpstream(http.StatusForbidden, http.StatusNotFound),
+	},
+	{
+		method: http.MethodPost, path: "/conversations/:conversationID/read", tag: "messages", auth: true,
+		summary: "Mark messages as read",
+		body:    req.MarkReadDto{},
+		status:  http.StatusOK, data: messagePb.MarkReadResp{},
+		errors: withUpstream(http.StatusNotFound),
+	},
+	{
+		method: http.MethodPut, path: "/settings/message-permission", tag: "messages", auth: true,
+		summary: "Choose who may message the caller",
+		body:    req.MessagePermissionDto{},
+		status:  http.StatusOK, data: messagePb.UpdateMessagePermissionResp{},
+		errors: upstream,
+	},
+	{
+		method: http.MethodGet, path: "/users/:id/bookmark-collections", tag: "bookmarks", auth: true,
+		summary: "List a user's public bookmark collections",
+		query:   []interface{}{req.PageDto{}},
+		status:  http.StatusOK, data: bookmarkPb.FindCollectionsResp{},
+		errors: upstream,
+	},
+	{
+		method: http.MethodPost, path: "/bookmarks", tag: "bookmarks", auth: true,
+		summary: "Bookmark a user or message",
+		body:    req.BookmarkDto{},
+		status:  http.StatusCreated, data: bookmarkPb.AddBookmarkResp{},
+		errors: withUpstream(http.StatusNotFound, http.StatusConflict),
+	},
+	{
+		method: http.MethodGet, path: "/bookmarks", tag: "bookmarks", auth: true,
+		summary: "List the caller's bookmarks",
+		query:   []interface{}{req.PageDto{}},
+		status:  http.StatusOK, data: bookmarkPb.FindBookmarksResp{},
+		errors: upstream,
+	},
+	{
+		method: http.MethodDelete, path: "/bookmarks/:itemType/:itemID", tag: "bookmarks", auth: true,
+		summary: "Remove a bookmark",
+		status:  http.StatusOK, data: bookmarkPb.RemoveBookmarkResp{},
+		errors: withUpstream(http.StatusNotFound),
+	},
+	{
+		method: http.MethodPost, path: "/bookmark-collections", tag: "bookmarks", auth: true,
+		summary: "Create a bookmark collection",
+		body:    req.CollectionDto{},
+		status:  http.StatusCreated, data: bookmarkPb.CreateCollectionResp{},
+		errors: withUpstream(http.StatusConflict),
+	},
+	{
+		method: http.MethodGet, path: "/bookmark-collections", tag: "bookmarks", auth: true,
+		summary: "List the caller's bookmark collections",
+		query:   []interface{}{req.PageDto{}},
+		status:  http.StatusOK, data: bookmarkPb.FindCollectionsResp{},
+		errors: upstream,
+	},
+	{
+		method: http.MethodPatch, path: "/bookmark-collections/:collectionID", tag: "bookmarks", auth: true,
+		summary: "Rename a bookmark collection or change its visibility",
+		body:    req.UpdateCollectionDto{},
+		status:  http.StatusOK, data: bookmarkPb.UpdateCollectionResp{},
+		errors: withUpstream(http.StatusNotFound, http.StatusConflict),
+	},
+	{
+		method: http.MethodDelete, path: "/bookmark-collections/:collectionID", tag: "bookmarks", auth: true,
+		summary: "Delete a bookmark collection",
+		status:  http.StatusOK, data: bookmarkPb.DeleteCollectionResp{},
+		errors: withUpstream(http.StatusNotFound),
+	},
+	{
+		method: http.MethodGet, path: "/bookmark-collections/:collectionID/bookmarks", tag: "bookmarks", auth: true,
+		summary: "List the bookmarks in a collection",
+		query:   []interface{}{req.PageDto{}},
+		status:  http.StatusOK, data: bookmarkPb.FindCollectionBookmarksResp{},
+		errors: withUpstream(http.StatusNotFound),
+	},
+	{
+		method: http.MethodPost, path: "/reports", tag: "moderation", auth: true,
+		summary: "Report a user or message",
+		body:    req.ReportDto{},
+		status:  http.StatusCreated, data: moderationPb.CreateReportResp{},
+		errors: withUpstream(http.StatusNotFound, http.StatusConflict),
+	},
+	{
+		method: http.MethodGet, path: "/moderation/reports", tag: "moderation", auth: true,
+		summary: "List reports, moderators only",
+		query:   []interface{}{req.ReportFilterDto{}, req.PageDto{}},
+		status:  http.StatusOK, data: moderationPb.FindReportsResp{},
+		errors: withUpstream(http.StatusForbidden),
+	},
+	{
+		method: http.MethodGet, path: "/moderation/reports/:reportID", tag: "moderation", auth: true,
+		summary: "Find a report, moderators only",
+		status:  http.StatusOK, data: moderationPb.FindReportByIDResp{},
+		errors: withUpstream(http.StatusForbidden, http.StatusNotFound),
+	},
+	{
+		method: http.MethodPost, path: "/moderation/reports/:reportID/actions", tag: "moderation", auth: true,
+		summary: "Act on a report, moderators only",
+		body:    req.ModerationActionDto{},
+		status:  http.StatusCreated, data: moderationPb.TakeActionResp{},
+		errors: withUpstream(http.StatusForbidden, http.StatusNotFound, http.StatusConflict),
+	},
+	{
+		method: http.MethodGet, path: "/moderation/actions", tag: "moderation", auth: true,
+		summary: "List moderation actions, moderators only",
+		query:   []interface{}{req.PageDto{}},
+		status:  http.StatusOK, data: moderationPb.FindActionsResp{},
+		errors: withUpstream(http.StatusForbidden),
+	},
+	{
+		method: http.MethodPost, path: "/graphql", tag: "graphql",
+		summary: "Run a GraphQL query or mutation on users, the token is optional",
+		body:    req.GraphQLDto{},
+		status:  http.StatusOK, produces: "application/json", data: graphql.Result{},
+		errors: []int{http.StatusUnauthorized},
+	},
+	{
+		method: http.MethodPost, path: "/media", tag: "media", auth: true,
+		summary: "Upload an image",
+		upload:  true,
+		status:  http.StatusCreated, data: resp.MediaDto{},
+		errors: []int{http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType},
+	},
+	{
+		method: http.MethodGet, path: "/media/:mediaID", tag: "media",
+		summary: "Find an image's metadata",
+		status:  http.StatusOK, data: resp.MediaDto{},
+		errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodGet, path: "/media/:mediaID/content", tag: "media",
+		summary: "Download an image",
+		status:  http.StatusOK, produces: "image/*",
+		errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodGet, path: "/media/:mediaID/thumbnail", tag: "media",
+		summary: "Download an image's thumbnail",
+		status:  http.StatusOK, produces: "image/*",
+		errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodGet, path: "/realtime/ws", tag: "realtime", auth: true,
+		summary: "Stream events over a WebSocket",
+		query:   []interface{}{streamQuery{}},
+		status:  http.StatusSwitchingProtocols,
+	},
+	{
+		method: http.MethodGet, path: "/realtime/sse", tag: "realtime", auth: true,
+		summary: "Stream events as server-sent events",
+		query:   []interface{}{streamQuery{}},
+		status:  http.StatusOK, produces: "text/event-stream",
+	},
+}
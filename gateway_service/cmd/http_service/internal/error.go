@@ -7,19 +7,33 @@ import (
 type errKind int
 
 var (
-	ErrCors           = Error{kind: cors}
-	ErrNoRoute        = Error{kind: noRoute}
-	ErrBadParams      = Error{kind: badParams}
-	ErrFailToValidate = Error{kind: failToValidate}
-	ErrUnknown        = Error{kind: unknown}
+	ErrNoRoute         = Error{kind: noRoute}
+	ErrBadParams       = Error{kind: badParams}
+	ErrFailToValidate  = Error{kind: failToValidate}
+	ErrUnauthorized    = Error{kind: unauthorized}
+	ErrForbidden       = Error{kind: forbidden}
+	ErrTooManyRequests = Error{kind: tooManyRequests}
+	// ErrIdempotencyKeyReused is an Idempotency-Key sent again with another
+	// request, ErrIdempotencyInProgress one whose first request is running.
+	ErrIdempotencyKeyReused  = Error{kind: idempotencyKeyReused}
+	ErrIdempotencyInProgress = Error{kind: idempotencyInProgress}
+	ErrPreconditionFailed    = Error{kind: preconditionFailed}
+	ErrRequestTooLarge       = Error{kind: requestTooLarge}
+	ErrUnknown               = Error{kind: unknown}
 )
 
 const (
 	_ errKind = iota
-	cors
 	noRoute
 	badParams
 	failToValidate
+	unauthorized
+	forbidden
+	tooManyRequests
+	idempotencyKeyReused
+	idempotencyInProgress
+	preconditionFailed
+	requestTooLarge
 	unknown
 )
 
@@ -30,14 +44,26 @@ type Error struct {
 
 func (e *Error) Error() string {
 	switch e.kind {
-	case cors:
-		return "CORS error"
 	case noRoute:
 		return "No route"
 	case badParams:
 		return "Bad params"
 	case failToValidate:
 		return fmt.Sprintf("%v", e.err)
+	case unauthorized:
+		return "Unauthorized"
+	case forbidden:
+		return "Forbidden"
+	case tooManyRequests:
+		return "Too many requests"
+	case idempotencyKeyReused:
+		return "Idempotency key reused"
+	case idempotencyInProgress:
+		return "Idempotency key in progress"
+	case preconditionFailed:
+		return fmt.Sprintf("Precondition failed %v", e.err)
+	case requestTooLarge:
+		return fmt.Sprintf("Request too large %v", e.err)
 	default:
 		return fmt.Sprintf("Unknown error %v", e.err)
 	}
@@ -0,0 +1,353 @@
+package handler
+
+import (
+	"gatewayservice/cmd/http_service/internal"
+	handlerUtil "gatewayservice/cmd/http_service/internal/util"
+	"gatewayservice/internal/dto/req"
+	"gatewayservice/internal/logging"
+	internalUtil "gatewayservice/internal/util"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+)
+
+func (h Handler) AddBookmark(ctx *gin.Context) {
+	const scope = "bookmarkHandler#AddBookmark"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	bookmarkDto := req.BookmarkDto{}
+	ctx.ShouldBind(&bookmarkDto)
+	response, err := h.bookmarkServiceUsecase.AddBookmark(ctx, userID, &bookmarkDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Added a bookmark",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusCreated,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusCreated,
+			Message: http.StatusText(http.StatusCreated),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) RemoveBookmark(ctx *gin.Context) {
+	const scope = "bookmarkHandler#RemoveBookmark"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	itemID, err := strconv.Atoi(ctx.Param("itemID"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad itemID request param",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	response, err := h.bookmarkServiceUsecase.RemoveBookmark(ctx, userID, ctx.Param("itemType"), itemID)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Removed a bookmark",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) FindBookmarks(ctx *gin.Context) {
+	const scope = "bookmarkHandler#FindBookmarks"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	pageDto := req.PageDto{}
+	if err := ctx.ShouldBindQuery(&pageDto); err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad page query params",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	response, err := h.bookmarkServiceUsecase.FindBookmarks(ctx, userID, &pageDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found bookmarks",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) CreateBookmarkCollection(ctx *gin.Context) {
+	const scope = "bookmarkHandler#CreateBookmarkCollection"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	collectionDto := req.CollectionDto{}
+	ctx.ShouldBind(&collectionDto)
+	response, err := h.bookmarkServiceUsecase.CreateCollection(ctx, userID, &collectionDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Created a bookmark collection",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusCreated,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusCreated,
+			Message: http.StatusText(http.StatusCreated),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) FindBookmarkCollections(ctx *gin.Context) {
+	const scope = "bookmarkHandler#FindBookmarkCollections"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	pageDto := req.PageDto{}
+	if err := ctx.ShouldBindQuery(&pageDto); err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad page query params",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	response, err := h.bookmarkServiceUsecase.FindCollections(ctx, userID, userID, &pageDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found bookmark collections",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) FindUserBookmarkCollections(ctx *gin.Context) {
+	const scope = "bookmarkHandler#FindUserBookmarkCollections"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	ownerID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad userID request param",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	pageDto := req.PageDto{}
+	if err := ctx.ShouldBindQuery(&pageDto); err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad page query params",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	response, err := h.bookmarkServiceUsecase.FindCollections(ctx, userID, ownerID, &pageDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found bookmark collections of a user",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) UpdateBookmarkCollection(ctx *gin.Context) {
+	const scope = "bookmarkHandler#UpdateBookmarkCollection"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	collectionID, err := strconv.Atoi(ctx.Param("collectionID"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad collectionID request param",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	updateCollectionDto := req.UpdateCollectionDto{}
+	ctx.ShouldBind(&updateCollectionDto)
+	response, err := h.bookmarkServiceUsecase.UpdateCollection(ctx, userID, collectionID, &updateCollectionDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Updated a bookmark collection",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) DeleteBookmarkCollection(ctx *gin.Context) {
+	const scope = "bookmarkHandler#DeleteBookmarkCollection"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	collectionID, err := strconv.Atoi(ctx.Param("collectionID"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad collectionID request param",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	response, err := h.bookmarkServiceUsecase.DeleteCollection(ctx, userID, collectionID)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Deleted a bookmark collection",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) FindCollectionBookmarks(ctx *gin.Context) {
+	const scope = "bookmarkHandler#FindCollectionBookmarks"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	collectionID, err := strconv.Atoi(ctx.Param("collectionID"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad collectionID request param",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	pageDto := req.PageDto{}
+	if err := ctx.ShouldBindQuery(&pageDto); err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad page query params",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	response, err := h.bookmarkServiceUsecase.FindCollectionBookmarks(ctx, userID, collectionID, &pageDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found bookmarks of a collection",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"gatewayservice/internal/dto/req"
+	"gatewayservice/internal/logging"
+	"gatewayservice/internal/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+)
+
+// GraphQL answers with the result of the query as is rather than in a
+// StandardResponse, since that is the shape GraphQL clients expect.
+func (h Handler) GraphQL(ctx *gin.Context) {
+	const scope = "graphqlHandler#GraphQL"
+	graphqlDto := req.GraphQLDto{}
+	ctx.ShouldBind(&graphqlDto)
+	// The login and register mutations are rate limited by client IP.
+	ctx.Set(util.ClientIP, ctx.ClientIP())
+	result, err := h.graphqlUsecase.Execute(ctx, &graphqlDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Executed a GraphQL request",
+		slog.String("scope", scope),
+		slog.Int("errors", len(result.Errors)),
+	)
+	ctx.JSON(http.StatusOK, result)
+}
@@ -3,17 +3,34 @@ package handler
 import (
 	"gatewayservice/internal/usecase"
 
+	"github.com/gorilla/websocket"
 	"golang.org/x/exp/slog"
 )
 
 type Handler struct {
-	logger             *slog.Logger
-	userServiceUsecase usecase.IUserServiceUsecase
+	logger                   *slog.Logger
+	userServiceUsecase       usecase.IUserServiceUsecase
+	realtimeUsecase          usecase.IRealtimeUsecase
+	messageServiceUsecase    usecase.IMessageServiceUsecase
+	mediaUsecase             usecase.IMediaUsecase
+	bookmarkServiceUsecase   usecase.IBookmarkServiceUsecase
+	moderationServiceUsecase usecase.IModerationServiceUsecase
+	healthUsecase            usecase.IHealthUsecase
+	graphqlUsecase           usecase.IGraphQLUsecase
+	upgrader                 websocket.Upgrader
 }
 
-func New(logger *slog.Logger, userServiceUsecase usecase.IUserServiceUsecase) *Handler {
+func New(logger *slog.Logger, userServiceUsecase usecase.IUserServiceUsecase, realtimeUsecase usecase.IRealtimeUsecase, messageServiceUsecase usecase.IMessageServiceUsecase, mediaUsecase usecase.IMediaUsecase, bookmarkServiceUsecase usecase.IBookmarkServiceUsecase, moderationServiceUsecase usecase.IModerationServiceUsecase, healthUsecase usecase.IHealthUsecase, graphqlUsecase usecase.IGraphQLUsecase, allowsOrigin func(origin string) bool) *Handler {
 	return &Handler{
-		logger:             logger,
-		userServiceUsecase: userServiceUsecase,
+		logger:                   logger,
+		userServiceUsecase:       userServiceUsecase,
+		realtimeUsecase:          realtimeUsecase,
+		messageServiceUsecase:    messageServiceUsecase,
+		mediaUsecase:             mediaUsecase,
+		bookmarkServiceUsecase:   bookmarkServiceUsecase,
+		moderationServiceUsecase: moderationServiceUsecase,
+		healthUsecase:            healthUsecase,
+		graphqlUsecase:           graphqlUsecase,
+		upgrader:                 newUpgrader(allowsOrigin),
 	}
 }
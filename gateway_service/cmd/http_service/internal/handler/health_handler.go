@@ -0,0 +1,33 @@
+package handler
+
+import (
+	handlerUtil "gatewayservice/cmd/http_service/internal/util"
+	"gatewayservice/internal/dto/resp"
+	"gatewayservice/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h Handler) Healthz(ctx *gin.Context) {
+	writeHealth(ctx, h.healthUsecase.Liveness(ctx))
+}
+
+func (h Handler) Readyz(ctx *gin.Context) {
+	writeHealth(ctx, h.healthUsecase.Readiness(ctx))
+}
+
+func writeHealth(ctx *gin.Context, healthDto *resp.HealthDto) {
+	code := http.StatusOK
+	if healthDto.Status != usecase.HealthStatusUp {
+		code = http.StatusServiceUnavailable
+	}
+	ctx.JSON(
+		code,
+		&handlerUtil.StandardResponse{
+			Code:    code,
+			Message: http.StatusText(code),
+			Data:    healthDto,
+		},
+	)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gatewayservice/cmd/http_service/internal"
+	handlerUtil "gatewayservice/cmd/http_service/internal/util"
+	"gatewayservice/internal/logging"
+	"gatewayservice/internal/usecase"
+	internalUtil "gatewayservice/internal/util"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+)
+
+// maxUploadBody leaves room for the multipart envelope around the file.
+const maxUploadBody = 11 << 20
+
+func (h Handler) UploadMedia(ctx *gin.Context) {
+	const scope = "mediaHandler#UploadMedia"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxUploadBody)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad file form field",
+			err,
+			slog.String("scope", scope),
+		)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctx.Error(fmt.Errorf("%s: %w", scope, usecase.ErrMediaTooLarge.SetError(err)))
+			return
+		}
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Failed to open uploaded file",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	defer file.Close()
+	response, err := h.mediaUsecase.Upload(ctx, userID, file)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Uploaded media",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusCreated,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusCreated,
+			Message: http.StatusText(http.StatusCreated),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) FindMediaByID(ctx *gin.Context) {
+	const scope = "mediaHandler#FindMediaByID"
+	response, err := h.mediaUsecase.FindByID(ctx, ctx.Param("mediaID"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) ServeMediaContent(ctx *gin.Context) {
+	h.serveMedia(ctx, "mediaHandler#ServeMediaContent", usecase.MediaVariantOriginal)
+}
+
+func (h Handler) ServeMediaThumbnail(ctx *gin.Context) {
+	h.serveMedia(ctx, "mediaHandler#ServeMediaThumbnail", usecase.MediaVariantThumbnail)
+}
+
+// serveMedia streams a stored variant. Media IDs are content hashes, so the
+// response never changes and can be cached forever.
+func (h Handler) serveMedia(ctx *gin.Context, scope string, variant string) {
+	mediaID := ctx.Param("mediaID")
+	etag := strconv.Quote(mediaID + "-" + variant)
+	if ctx.GetHeader("If-None-Match") == etag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	r, media, err := h.mediaUsecase.Open(ctx, mediaID, variant)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	defer r.Close()
+	ctx.Header("Content-Type", media.ContentType)
+	ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
+	ctx.Header("ETag", etag)
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.Status(http.StatusOK)
+	if _, err := io.Copy(ctx.Writer, r); err != nil {
+		logging.FromContext(ctx, h.logger).Warn(
+			"Failed to stream media",
+			slog.String("scope", scope),
+			slog.Any("error", err),
+		)
+	}
+}
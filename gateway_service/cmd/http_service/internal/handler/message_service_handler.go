@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"gatewayservice/cmd/http_service/internal"
+	handlerUtil "gatewayservice/cmd/http_service/internal/util"
+	"gatewayservice/internal/dto/req"
+	"gatewayservice/internal/logging"
+	internalUtil "gatewayservice/internal/util"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+)
+
+func (h Handler) CreateConversation(ctx *gin.Context) {
+	const scope = "messageHandler#CreateConversation"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	conversationDto := req.ConversationDto{}
+	ctx.ShouldBind(&conversationDto)
+	response, err := h.messageServiceUsecase.CreateConversation(ctx, userID, &conversationDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Created a conversation",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusCreated,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusCreated,
+			Message: http.StatusText(http.StatusCreated),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) FindConversations(ctx *gin.Context) {
+	const scope = "messageHandler#FindConversations"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	pageDto := req.PageDto{}
+	if err := ctx.ShouldBindQuery(&pageDto); err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad page query params",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	response, err := h.messageServiceUsecase.FindConversations(ctx, userID, &pageDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found conversations",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) FindConversationByID(ctx *gin.Context) {
+	const scope = "messageHandler#FindConversationByID"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	conversationID, err := strconv.Atoi(ctx.Param("conversationID"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad conversationID request param",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	response, err := h.messageServiceUsecase.FindConversationByID(ctx, userID, conversationID)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found a conversation by its ID",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) SendMessage(ctx *gin.Context) {
+	const scope = "messageHandler#SendMessage"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	conversationID, err := strconv.Atoi(ctx.Param("conversationID"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad conversationID request param",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	messageDto := req.MessageDto{}
+	ctx.ShouldBind(&messageDto)
+	response, err := h.messageServiceUsecase.SendMessage(ctx, userID, conversationID, &messageDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Sent a message",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusCreated,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusCreated,
+			Message: http.StatusText(http.StatusCreated),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) FindMessages(ctx *gin.Context) {
+	const scope = "messageHandler#FindMessages"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	conversationID, err := strconv.Atoi(ctx.Param("conversationID"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad conversationID request param",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	pageDto := req.PageDto{}
+	if err := ctx.ShouldBindQuery(&pageDto); err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad page query params",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	response, err := h.messageServiceUsecase.FindMessages(ctx, userID, conversationID, &pageDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found messages of a conversation",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) MarkConversationRead(ctx *gin.Context) {
+	const scope = "messageHandler#MarkConversationRead"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	conversationID, err := strconv.Atoi(ctx.Param("conversationID"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad conversationID request param",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	markReadDto := req.MarkReadDto{}
+	ctx.ShouldBind(&markReadDto)
+	response, err := h.messageServiceUsecase.MarkRead(ctx, userID, conversationID, &markReadDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Marked a conversation as read",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) CountUnreadMessages(ctx *gin.Context) {
+	const scope = "messageHandler#CountUnreadMessages"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	response, err := h.messageServiceUsecase.CountUnread(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) UpdateMessagePermission(ctx *gin.Context) {
+	const scope = "messageHandler#UpdateMessagePermission"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	messagePermissionDto := req.MessagePermissionDto{}
+	ctx.ShouldBind(&messagePermissionDto)
+	response, err := h.messageServiceUsecase.UpdateMessagePermission(ctx, userID, &messagePermissionDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Updated message permission",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"gatewayservice/cmd/http_service/internal"
+	handlerUtil "gatewayservice/cmd/http_service/internal/util"
+	"gatewayservice/internal/dto/req"
+	"gatewayservice/internal/logging"
+	internalUtil "gatewayservice/internal/util"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+)
+
+func (h Handler) CreateReport(ctx *gin.Context) {
+	const scope = "moderationHandler#CreateReport"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	reportDto := req.ReportDto{}
+	ctx.ShouldBind(&reportDto)
+	response, err := h.moderationServiceUsecase.CreateReport(ctx, userID, &reportDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Created a report",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusCreated,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusCreated,
+			Message: http.StatusText(http.StatusCreated),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) FindReports(ctx *gin.Context) {
+	const scope = "moderationHandler#FindReports"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	reportFilterDto := req.ReportFilterDto{}
+	pageDto := req.PageDto{}
+	if err := ctx.ShouldBindQuery(&reportFilterDto); err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad report filter query params",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	if err := ctx.ShouldBindQuery(&pageDto); err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad page query params",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	response, err := h.moderationServiceUsecase.FindReports(ctx, userID, &reportFilterDto, &pageDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found reports",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) FindReportByID(ctx *gin.Context) {
+	const scope = "moderationHandler#FindReportByID"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	reportID, err := strconv.Atoi(ctx.Param("reportID"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad reportID request param",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	response, err := h.moderationServiceUsecase.FindReportByID(ctx, userID, reportID)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found a report by its ID",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) TakeModerationAction(ctx *gin.Context) {
+	const scope = "moderationHandler#TakeModerationAction"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	reportID, err := strconv.Atoi(ctx.Param("reportID"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad reportID request param",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	moderationActionDto := req.ModerationActionDto{}
+	ctx.ShouldBind(&moderationActionDto)
+	response, err := h.moderationServiceUsecase.TakeAction(ctx, userID, reportID, &moderationActionDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Took a moderation action",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusCreated,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusCreated,
+			Message: http.StatusText(http.StatusCreated),
+			Data:    response,
+		},
+	)
+}
+
+func (h Handler) FindModerationActions(ctx *gin.Context) {
+	const scope = "moderationHandler#FindModerationActions"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	pageDto := req.PageDto{}
+	if err := ctx.ShouldBindQuery(&pageDto); err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Bad page query params",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(&internal.ErrBadParams)
+		return
+	}
+	response, err := h.moderationServiceUsecase.FindActions(ctx, userID, &pageDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found moderation actions",
+		slog.String("scope", scope),
+	)
+	ctx.JSON(
+		http.StatusOK,
+		&handlerUtil.StandardResponse{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+			Data:    response,
+		},
+	)
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"gatewayservice/internal/logging"
+	"gatewayservice/internal/pubsub"
+	internalUtil "gatewayservice/internal/util"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"golang.org/x/exp/slog"
+)
+
+const (
+	realtimeWriteWait      = 10 * time.Second
+	realtimePongWait       = 60 * time.Second
+	realtimePingPeriod     = 50 * time.Second
+	realtimeSSEHeartbeat   = 15 * time.Second
+	realtimeSSERetryMillis = 3000
+)
+
+// newUpgrader accepts connections from the origins allowsOrigin allows, from
+// the gateway's own origin, and from clients that send no Origin, which are
+// not browsers.
+func newUpgrader(allowsOrigin func(origin string) bool) websocket.Upgrader {
+	return websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+			if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+				return true
+			}
+			return allowsOrigin != nil && allowsOrigin(origin)
+		},
+	}
+}
+
+// lastEventID reads the resume point from the standard SSE header, or from
+// the last_event_id query param for WebSocket clients.
+func lastEventID(ctx *gin.Context) string {
+	if id := ctx.GetHeader("Last-Event-ID"); id != "" {
+		return id
+	}
+	return ctx.Query("last_event_id")
+}
+
+func (h Handler) StreamEventsWebSocket(ctx *gin.Context) {
+	const scope = "realtimeHandler#StreamEventsWebSocket"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	subscription, err := h.realtimeUsecase.Subscribe(ctx, userID, lastEventID(ctx))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	defer subscription.Close()
+	conn, err := h.upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Failed to upgrade connection",
+			err,
+			slog.String("scope", scope),
+		)
+		return
+	}
+	defer conn.Close()
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(realtimePongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(realtimePongWait))
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+	ticker := time.NewTicker(realtimePingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			logging.FromContext(ctx, h.logger).Info(
+				"Client closed the connection",
+				slog.String("scope", scope),
+			)
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				logging.FromContext(ctx, h.logger).Warn(
+					"Subscription ended",
+					slog.String("scope", scope),
+					slog.Any("reason", subscription.Err()),
+				)
+				conn.WriteControl(
+					websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "resume with last_event_id"),
+					time.Now().Add(realtimeWriteWait),
+				)
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(realtimeWriteWait))
+			if err := conn.WriteJSON(event); err != nil {
+				logging.FromContext(ctx, h.logger).Error(
+					"Failed to write event",
+					err,
+					slog.String("scope", scope),
+				)
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(realtimeWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h Handler) StreamEventsSSE(ctx *gin.Context) {
+	const scope = "realtimeHandler#StreamEventsSSE"
+	userID := ctx.Value(internalUtil.UserID).(int)
+	subscription, err := h.realtimeUsecase.Subscribe(ctx, userID, lastEventID(ctx))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Error(err)
+		return
+	}
+	defer subscription.Close()
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	fmt.Fprintf(ctx.Writer, "retry: %d\n\n", realtimeSSERetryMillis)
+	ctx.Writer.Flush()
+	ticker := time.NewTicker(realtimeSSEHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			logging.FromContext(ctx, h.logger).Info(
+				"Client closed the connection",
+				slog.String("scope", scope),
+			)
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				logging.FromContext(ctx, h.logger).Warn(
+					"Subscription ended",
+					slog.String("scope", scope),
+					slog.Any("reason", subscription.Err()),
+				)
+				return
+			}
+			if err := writeSSEEvent(ctx.Writer, event); err != nil {
+				logging.FromContext(ctx, h.logger).Error(
+					"Failed to write event",
+					err,
+					slog.String("scope", scope),
+				)
+				return
+			}
+			ctx.Writer.Flush()
+		case <-ticker.C:
+			if _, err := fmt.Fprint(ctx.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			ctx.Writer.Flush()
+		}
+	}
+}
+
+func writeSSEEvent(w gin.ResponseWriter, event *pubsub.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
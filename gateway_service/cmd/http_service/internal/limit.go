@@ -0,0 +1,6 @@
+package internal
+
+// MaxJSONBody bounds the request bodies the gateway reads whole before a
+// handler runs: the Idempotency middleware hashes them and the transcoder
+// binds them.
+const MaxJSONBody = 1 << 20
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gatewayservice/internal/metrics"
+	"gatewayservice/internal/util"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+)
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwt := util.NewJwt("secret", "test", time.Hour)
+	token, err := jwt.GenerateSigned(1, "user@example.com", "user")
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	m := New(slog.New(slog.NewTextHandler(io.Discard)), jwt, metrics.New(), nil, nil, CORSPolicy{}, nil, IdempotencyPolicy{})
+	r := gin.New()
+	r.Use(m.ErrorHandler)
+	ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+	r.GET("/conversations", m.Authenticate, ok)
+	r.GET("/graphql", m.OptionalAuthenticate, ok)
+	r.GET("/realtime/sse", m.AuthenticateStream, ok)
+	tests := []struct {
+		name   string
+		target string
+		header string
+		status int
+	}{
+		{name: "header", target: "/conversations", header: "Bearer " + token, status: http.StatusOK},
+		{name: "no token", target: "/conversations", status: http.StatusUnauthorized},
+		{name: "bad token", target: "/conversations", header: "Bearer nope", status: http.StatusUnauthorized},
+		// Query tokens end up in access logs, so only streams take them.
+		{name: "query token", target: "/conversations?access_token=" + token, status: http.StatusUnauthorized},
+		{name: "optional without token", target: "/graphql", status: http.StatusOK},
+		{name: "optional ignores query token", target: "/graphql?access_token=nope", status: http.StatusOK},
+		{name: "optional bad token", target: "/graphql", header: "Bearer nope", status: http.StatusUnauthorized},
+		{name: "stream header", target: "/realtime/sse", header: "Bearer " + token, status: http.StatusOK},
+		{name: "stream query token", target: "/realtime/sse?access_token=" + token, status: http.StatusOK},
+		{name: "stream bad query token", target: "/realtime/sse?access_token=nope", status: http.StatusUnauthorized},
+		{name: "stream no token", target: "/realtime/sse", status: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				request.Header.Set("Authorization", tt.header)
+			}
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, request)
+			if recorder.Code != tt.status {
+				t.Fatalf("got %d, want %d", recorder.Code, tt.status)
+			}
+		})
+	}
+}
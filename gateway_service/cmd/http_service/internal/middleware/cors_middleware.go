@@ -1,20 +1,94 @@
 package middleware
 
 import (
-	"gatewayservice/cmd/http_service/internal"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+var corsExposedHeaders = strings.Join([]string{
+	"X-Request-ID",
+	"ETag",
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+	"RateLimit-Policy",
+	"Retry-After",
+	"Idempotent-Replayed",
+	"Deprecation",
+	"Sunset",
+	"Link",
+}, ", ")
+
+type CORSPolicy struct {
+	// AllowedOrigins are exact origins or path.Match patterns such as
+	// https://*.example.com. A lone * allows every origin.
+	AllowedOrigins   []string
+	AllowCredentials bool
+	AllowedHeaders   []string
+	MaxAge           time.Duration
+}
+
+// Allows reports whether origin is one of the allowed origins. The WebSocket
+// upgrade uses it too, since CORS does not apply there.
+func (p CORSPolicy) Allows(origin string) bool {
+	for _, pattern := range p.AllowedOrigins {
+		if pattern == "*" || pattern == origin {
+			return true
+		}
+		if matched, _ := path.Match(pattern, origin); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// allowOrigin sets the CORS headers every response to origin needs and
+// reports whether origin is allowed.
+func (m Middleware) allowOrigin(ctx *gin.Context, origin string) bool {
+	ctx.Writer.Header().Add("Vary", "Origin")
+	if origin == "" || !m.cors.Allows(origin) {
+		return false
+	}
+	// The origin is echoed rather than sent as * when credentials are
+	// allowed, since browsers refuse * on credentialed requests.
+	if m.cors.AllowCredentials {
+		ctx.Header("Access-Control-Allow-Origin", origin)
+		ctx.Header("Access-Control-Allow-Credentials", "true")
+	} else if len(m.cors.AllowedOrigins) == 1 && m.cors.AllowedOrigins[0] == "*" {
+		ctx.Header("Access-Control-Allow-Origin", "*")
+	} else {
+		ctx.Header("Access-Control-Allow-Origin", origin)
+	}
+	return true
+}
+
 func (m Middleware) CORSMiddleware(ctx *gin.Context) {
-	ctx.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-	ctx.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-	ctx.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-	ctx.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT")
-	if ctx.Request.Method == "OPTIONS" {
-		ctx.Error(&internal.ErrCors)
-		ctx.Abort()
-		return
+	if ctx.Request.Method != http.MethodOptions && m.allowOrigin(ctx, ctx.GetHeader("Origin")) {
+		ctx.Header("Access-Control-Expose-Headers", corsExposedHeaders)
 	}
 	ctx.Next()
 }
+
+// Preflight answers OPTIONS on a path with the methods the path serves. A
+// preflight from an origin that is not allowed still gets a 204, just without
+// the headers, and the browser blocks the actual request.
+func (m Middleware) Preflight(methods []string) gin.HandlerFunc {
+	allowedMethods := strings.Join(methods, ", ")
+	allowedHeaders := strings.Join(m.cors.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(m.cors.MaxAge.Seconds()))
+	return func(ctx *gin.Context) {
+		ctx.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		ctx.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+		if m.allowOrigin(ctx, ctx.GetHeader("Origin")) && ctx.GetHeader("Access-Control-Request-Method") != "" {
+			ctx.Header("Access-Control-Allow-Methods", allowedMethods)
+			ctx.Header("Access-Control-Allow-Headers", allowedHeaders)
+			ctx.Header("Access-Control-Max-Age", maxAge)
+		}
+		ctx.AbortWithStatus(http.StatusNoContent)
+	}
+}
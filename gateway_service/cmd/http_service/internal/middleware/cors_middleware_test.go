@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gatewayservice/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+)
+
+func TestCORSPolicyAllows(t *testing.T) {
+	policy := CORSPolicy{AllowedOrigins: []string{"https://app.example.com", "https://*.example.org"}}
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{origin: "https://app.example.com", want: true},
+		{origin: "http://app.example.com"},
+		{origin: "https://app.example.com:8443"},
+		{origin: "https://app.example.com.evil.com"},
+		{origin: "https://evil.com"},
+		{origin: "https://a.example.org", want: true},
+		{origin: "https://a.b.example.org", want: true},
+		// The pattern needs a subdomain.
+		{origin: "https://example.org"},
+		{origin: "https://example.org.evil.com"},
+		{origin: "https://evilexample.org"},
+		{origin: "null"},
+		{origin: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			if got := policy.Allows(tt.origin); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if !(CORSPolicy{AllowedOrigins: []string{"*"}}).Allows("https://anything.example") {
+		t.Fatal("* does not allow every origin")
+	}
+	if (CORSPolicy{}).Allows("https://app.example.com") {
+		t.Fatal("an empty policy allows an origin")
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name        string
+		policy      CORSPolicy
+		method      string
+		origin      string
+		allowOrigin string
+		credentials string
+		methods     string
+	}{
+		{
+			name:        "listed origin",
+			policy:      CORSPolicy{AllowedOrigins: []string{"https://app.example.com"}},
+			method:      http.MethodGet,
+			origin:      "https://app.example.com",
+			allowOrigin: "https://app.example.com",
+		},
+		{
+			name:   "other origin",
+			policy: CORSPolicy{AllowedOrigins: []string{"https://app.example.com"}},
+			method: http.MethodGet,
+			origin: "https://evil.com",
+		},
+		{
+			name:        "wildcard",
+			policy:      CORSPolicy{AllowedOrigins: []string{"*"}},
+			method:      http.MethodGet,
+			origin:      "https://evil.com",
+			allowOrigin: "*",
+		},
+		{
+			// Browsers refuse * on credentialed requests.
+			name:        "wildcard with credentials",
+			policy:      CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			method:      http.MethodGet,
+			origin:      "https://app.example.com",
+			allowOrigin: "https://app.example.com",
+			credentials: "true",
+		},
+		{
+			name:   "no origin",
+			policy: CORSPolicy{AllowedOrigins: []string{"*"}},
+			method: http.MethodGet,
+		},
+		{
+			name:        "preflight",
+			policy:      CORSPolicy{AllowedOrigins: []string{"https://app.example.com"}},
+			method:      http.MethodOptions,
+			origin:      "https://app.example.com",
+			allowOrigin: "https://app.example.com",
+			methods:     "GET, POST",
+		},
+		{
+			name:   "preflight from another origin",
+			policy: CORSPolicy{AllowedOrigins: []string{"https://app.example.com"}},
+			method: http.MethodOptions,
+			origin: "https://evil.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.policy.AllowedHeaders = []string{"Authorization"}
+			tt.policy.MaxAge = time.Minute
+			m := New(slog.New(slog.NewTextHandler(io.Discard)), nil, metrics.New(), nil, nil, tt.policy, nil, IdempotencyPolicy{})
+			r := gin.New()
+			r.Use(m.CORSMiddleware)
+			ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+			r.GET("/", ok)
+			r.POST("/", ok)
+			r.OPTIONS("/", m.Preflight([]string{http.MethodGet, http.MethodPost}))
+			request := httptest.NewRequest(tt.method, "/", nil)
+			if tt.origin != "" {
+				request.Header.Set("Origin", tt.origin)
+			}
+			if tt.method == http.MethodOptions {
+				request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, request)
+			header := recorder.Header()
+			if got := header.Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Fatalf("got allowed origin %q, want %q", got, tt.allowOrigin)
+			}
+			if got := header.Get("Access-Control-Allow-Credentials"); got != tt.credentials {
+				t.Fatalf("got credentials %q, want %q", got, tt.credentials)
+			}
+			if got := header.Get("Access-Control-Allow-Methods"); got != tt.methods {
+				t.Fatalf("got methods %q, want %q", got, tt.methods)
+			}
+			// Caches must key responses by origin, allowed or not.
+			varies := false
+			for _, value := range header.Values("Vary") {
+				varies = varies || value == "Origin"
+			}
+			if !varies {
+				t.Fatalf("got Vary %v, want Origin", header.Values("Vary"))
+			}
+			if tt.method == http.MethodOptions && recorder.Code != http.StatusNoContent {
+				t.Fatalf("preflight got %d", recorder.Code)
+			}
+		})
+	}
+}
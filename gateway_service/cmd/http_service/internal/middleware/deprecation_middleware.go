@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeprecationPolicy struct {
+	// Deprecation is when the routes were deprecated, and Sunset when they
+	// are expected to stop answering. Zero times send no header.
+	Deprecation time.Time
+	Sunset      time.Time
+	// Successor replaces Prefix at the start of the request path to link to
+	// the same route in the version that supersedes them.
+	Prefix    string
+	Successor string
+}
+
+// Deprecated announces the policy on every response with the Deprecation
+// (RFC 9745) and Sunset (RFC 8594) headers. It answers the request as usual.
+func (m Middleware) Deprecated(policy DeprecationPolicy) gin.HandlerFunc {
+	deprecation := ""
+	if !policy.Deprecation.IsZero() {
+		deprecation = "@" + strconv.FormatInt(policy.Deprecation.Unix(), 10)
+	}
+	sunset := ""
+	if !policy.Sunset.IsZero() {
+		sunset = policy.Sunset.UTC().Format(http.TimeFormat)
+	}
+	return func(ctx *gin.Context) {
+		if deprecation != "" {
+			ctx.Header("Deprecation", deprecation)
+		}
+		if sunset != "" {
+			ctx.Header("Sunset", sunset)
+		}
+		if policy.Successor != "" {
+			successor := policy.Successor + strings.TrimPrefix(ctx.Request.URL.Path, policy.Prefix)
+			ctx.Writer.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+		}
+		ctx.Next()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"gatewayservice/cmd/http_service/internal"
 	"gatewayservice/internal/dto/resp"
 	"gatewayservice/internal/usecase"
+	"gatewayservice/internal/util"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,12 +14,16 @@ import (
 )
 
 var grpcToHttp = map[codes.Code]int{
-	codes.InvalidArgument:  http.StatusBadRequest,
-	codes.Unauthenticated:  http.StatusUnauthorized,
-	codes.PermissionDenied: http.StatusForbidden,
-	codes.NotFound:         http.StatusNotFound,
-	codes.AlreadyExists:    http.StatusConflict,
-	codes.Unknown:          http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.FailedPrecondition: http.StatusConflict,
+	codes.Aborted:            http.StatusPreconditionFailed,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
 }
 
 func (m Middleware) ErrorHandler(ctx *gin.Context) {
@@ -26,6 +31,12 @@ func (m Middleware) ErrorHandler(ctx *gin.Context) {
 	if len(ctx.Errors) == 0 {
 		return
 	}
+	m.writeError(ctx)
+}
+
+// writeError answers with the first error of ctx. Middleware that runs ahead
+// of ErrorHandler calls it directly.
+func (m Middleware) writeError(ctx *gin.Context) {
 	code := http.StatusInternalServerError
 	body := &resp.StandardDto{
 		Code:    code,
@@ -47,6 +58,55 @@ func (m Middleware) ErrorHandler(ctx *gin.Context) {
 			Message: "Oops... nothing here",
 			Data:    nil,
 		}
+	} else if errors.Is(firstErr, &internal.ErrUnauthorized) {
+		code = http.StatusUnauthorized
+		body = &resp.StandardDto{
+			Code:    code,
+			Message: "Invalid or missing access token",
+			Data:    nil,
+		}
+	} else if errors.Is(firstErr, &internal.ErrForbidden) {
+		code = http.StatusForbidden
+		body = &resp.StandardDto{
+			Code:    code,
+			Message: "You are not allowed to access this resource",
+			Data:    nil,
+		}
+	} else if errors.Is(firstErr, &internal.ErrTooManyRequests) {
+		code = http.StatusTooManyRequests
+		body = &resp.StandardDto{
+			Code:    code,
+			Message: "Too many requests, slow down",
+			Data:    nil,
+		}
+	} else if errors.Is(firstErr, &internal.ErrIdempotencyKeyReused) {
+		code = http.StatusUnprocessableEntity
+		body = &resp.StandardDto{
+			Code:    code,
+			Message: "Idempotency-Key was already used with a different request",
+			Data:    nil,
+		}
+	} else if errors.Is(firstErr, &internal.ErrIdempotencyInProgress) {
+		code = http.StatusConflict
+		body = &resp.StandardDto{
+			Code:    code,
+			Message: "A request with this Idempotency-Key is still in progress",
+			Data:    nil,
+		}
+	} else if errors.Is(firstErr, &internal.ErrPreconditionFailed) {
+		code = http.StatusPreconditionFailed
+		body = &resp.StandardDto{
+			Code:    code,
+			Message: "If-Match does not match the current ETag",
+			Data:    nil,
+		}
+	} else if errors.Is(firstErr, &internal.ErrRequestTooLarge) {
+		code = http.StatusRequestEntityTooLarge
+		body = &resp.StandardDto{
+			Code:    code,
+			Message: "Request body is too large",
+			Data:    nil,
+		}
 	} else if errors.Is(firstErr, &usecase.ErrFailToValidate) {
 		code = http.StatusBadRequest
 		body = &resp.StandardDto{
@@ -54,18 +114,46 @@ func (m Middleware) ErrorHandler(ctx *gin.Context) {
 			Message: errors.Unwrap(firstErr).Error(),
 			Data:    nil,
 		}
+	} else if errors.Is(firstErr, &usecase.ErrMediaNotFound) {
+		code = http.StatusNotFound
+		body = &resp.StandardDto{
+			Code:    code,
+			Message: "Media not found",
+			Data:    nil,
+		}
+	} else if errors.Is(firstErr, &usecase.ErrMediaTooLarge) {
+		code = http.StatusRequestEntityTooLarge
+		body = &resp.StandardDto{
+			Code:    code,
+			Message: errors.Unwrap(firstErr).Error(),
+			Data:    nil,
+		}
+	} else if errors.Is(firstErr, &usecase.ErrBadMedia) {
+		code = http.StatusUnsupportedMediaType
+		body = &resp.StandardDto{
+			Code:    code,
+			Message: errors.Unwrap(firstErr).Error(),
+			Data:    nil,
+		}
 	} else if errors.Is(firstErr, &usecase.ErrClientService) {
 		clientServiceError := errors.Unwrap(firstErr)
 		grpcServiceError := errors.Unwrap(clientServiceError)
 		grpcStatus, ok := status.FromError(grpcServiceError)
-		if ok {
-			code = grpcToHttp[grpcStatus.Code()]
+		if httpCode, known := grpcToHttp[grpcStatus.Code()]; ok && known {
+			code = httpCode
+			message := grpcStatus.Message()
+			// These carry transport details about the user service, not
+			// anything the client can act on.
+			if code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout {
+				message = http.StatusText(code)
+			}
 			body = &resp.StandardDto{
 				Code:    code,
-				Message: grpcStatus.Message(),
+				Message: message,
 				Data:    nil,
 			}
 		}
 	}
+	body.RequestID = ctx.GetString(util.RequestID)
 	ctx.AbortWithStatusJSON(code, body)
 }
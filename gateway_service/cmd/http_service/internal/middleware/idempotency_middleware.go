@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gatewayservice/cmd/http_service/internal"
+	"gatewayservice/internal/idempotency"
+	"gatewayservice/internal/logging"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotencyKey    = 255
+	// maxRecordedBody caps the responses kept for replay.
+	maxRecordedBody = 1 << 20
+)
+
+// replayedHeaders are the response headers kept with a response. The others
+// belong to the request answering, such as its ID and rate limit.
+var replayedHeaders = []string{"Content-Type", "Location"}
+
+type IdempotencyPolicy struct {
+	// TTL is how long a response is replayed.
+	TTL time.Duration
+	// LockTTL is how long a request holds its key at most, in case the
+	// gateway dies before storing the response.
+	LockTTL time.Duration
+}
+
+// recorder keeps a copy of the body written through it.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.body.Len() <= maxRecordedBody {
+		r.body.Write(b)
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *recorder) WriteString(s string) (int, error) {
+	if r.body.Len() <= maxRecordedBody {
+		r.body.WriteString(s)
+	}
+	return r.ResponseWriter.WriteString(s)
+}
+
+// Idempotency answers a POST that repeats the Idempotency-Key and request of
+// an earlier one with the earlier response, for the policy TTL. Keys belong
+// to the user of a valid token. Anonymous requests share keys, which is safe
+// since a replay needs the same body, password included.
+//
+// It runs ahead of ErrorHandler so the responses it keeps include errors.
+// Responses to retry are not kept: 5xx, and 401 and 429 which come before
+// the request is handled.
+//
+// Logins and uploads ignore the key. A login answers with a token, which a
+// replay could hand out after it expired or the user was suspended. An
+// upload is a multipart form, whose boundary changes on every attempt, and
+// its media are already deduplicated by content.
+func (m Middleware) Idempotency(ctx *gin.Context) {
+	const scope = "middleware#Idempotency"
+	key := ctx.GetHeader(idempotencyKeyHeader)
+	if m.idempotencyStore == nil || ctx.Request.Method != http.MethodPost || key == "" ||
+		strings.HasSuffix(ctx.FullPath(), "/login") || isMultipart(ctx.GetHeader("Content-Type")) {
+		ctx.Next()
+		return
+	}
+	if len(key) > maxIdempotencyKey {
+		ctx.Error(internal.ErrBadParams.SetError(fmt.Errorf("%s longer than %d", idempotencyKeyHeader, maxIdempotencyKey)))
+		m.writeError(ctx)
+		return
+	}
+	owner := "anonymous"
+	if claims, err := m.jwt.ParseSigned(signedToken(ctx)); err == nil {
+		owner = "user:" + strconv.Itoa(claims.ID)
+	}
+	storeKey := owner + ":" + key
+	fingerprint, err := fingerprintOf(ctx.Writer, ctx.Request)
+	if err != nil {
+		logging.FromContext(ctx, m.logger).Error(
+			"Failed to read the request body",
+			err,
+			slog.String("scope", scope),
+		)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctx.Error(internal.ErrRequestTooLarge.SetError(err))
+		} else {
+			ctx.Error(internal.ErrBadParams.SetError(err))
+		}
+		m.writeError(ctx)
+		return
+	}
+	record, claimed, err := m.idempotencyStore.Begin(ctx, storeKey, fingerprint, m.idempotency.LockTTL)
+	if err != nil {
+		// Like the rate limiter, an outage of the store lets requests through.
+		logging.FromContext(ctx, m.logger).Error(
+			"Failed to claim the idempotency key",
+			err,
+			slog.String("scope", scope),
+		)
+		ctx.Next()
+		return
+	}
+	if !claimed {
+		switch {
+		case record.Fingerprint != fingerprint:
+			logging.FromContext(ctx, m.logger).Warn(
+				"Idempotency key reused with a different request",
+				slog.String("scope", scope),
+			)
+			ctx.Error(&internal.ErrIdempotencyKeyReused)
+			m.writeError(ctx)
+		case record.Response == nil:
+			ctx.Header("Retry-After", "1")
+			ctx.Error(&internal.ErrIdempotencyInProgress)
+			m.writeError(ctx)
+		default:
+			logging.FromContext(ctx, m.logger).Info(
+				"Replayed a response",
+				slog.String("scope", scope),
+			)
+			for name, value := range record.Response.Header {
+				ctx.Header(name, value)
+			}
+			ctx.Header("Idempotent-Replayed", "true")
+			ctx.Writer.WriteHeader(record.Response.Status)
+			ctx.Writer.Write(record.Response.Body)
+			ctx.Abort()
+		}
+		return
+	}
+	// The request context is done once the client goes away, but the key
+	// still has to be settled.
+	storeCtx := context.Background()
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		if err := m.idempotencyStore.Release(storeCtx, storeKey); err != nil {
+			logging.FromContext(ctx, m.logger).Error(
+				"Failed to release the idempotency key",
+				err,
+				slog.String("scope", scope),
+			)
+		}
+	}()
+	w := &recorder{ResponseWriter: ctx.Writer}
+	ctx.Writer = w
+	ctx.Next()
+	status := w.Status()
+	if status >= http.StatusInternalServerError || status == http.StatusUnauthorized || status == http.StatusTooManyRequests || w.body.Len() > maxRecordedBody {
+		return
+	}
+	header := map[string]string{}
+	for _, name := range replayedHeaders {
+		if value := w.Header().Get(name); value != "" {
+			header[name] = value
+		}
+	}
+	err = m.idempotencyStore.Complete(storeCtx, storeKey, &idempotency.Record{
+		Fingerprint: fingerprint,
+		Response: &idempotency.Response{
+			Status: status,
+			Header: header,
+			Body:   w.body.Bytes(),
+		},
+	}, m.idempotency.TTL)
+	if err != nil {
+		logging.FromContext(ctx, m.logger).Error(
+			"Failed to keep the response",
+			err,
+			slog.String("scope", scope),
+		)
+		return
+	}
+	completed = true
+}
+
+// fingerprintOf hashes the method, URL and whole body of request, leaving
+// the body to be read again. Bodies over MaxJSONBody fail with
+// *http.MaxBytesError before they are buffered.
+func fingerprintOf(writer http.ResponseWriter, request *http.Request) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", request.Method, request.URL.RequestURI())
+	body, err := io.ReadAll(http.MaxBytesReader(writer, request.Body, internal.MaxJSONBody))
+	if err != nil {
+		return "", err
+	}
+	hash.Write(body)
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func isMultipart(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && strings.HasPrefix(mediaType, "multipart/")
+}
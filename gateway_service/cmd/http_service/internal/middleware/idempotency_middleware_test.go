@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gatewayservice/cmd/http_service/internal"
+	"gatewayservice/internal/idempotency/memory"
+	"gatewayservice/internal/metrics"
+	"gatewayservice/internal/util"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+)
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	type request struct {
+		path   string
+		key    string
+		body   string
+		status int
+		// handled is how many times the handler has run after the request.
+		handled  int
+		replayed bool
+	}
+	tests := []struct {
+		name     string
+		requests []request
+	}{
+		{
+			name: "replayed",
+			requests: []request{
+				{path: "/posts", key: "a", body: `{"n":1}`, status: http.StatusOK, handled: 1},
+				{path: "/posts", key: "a", body: `{"n":1}`, status: http.StatusOK, handled: 1, replayed: true},
+			},
+		},
+		{
+			name: "key reused with another body",
+			requests: []request{
+				{path: "/posts", key: "a", body: `{"n":1}`, status: http.StatusOK, handled: 1},
+				{path: "/posts", key: "a", body: `{"n":2}`, status: http.StatusUnprocessableEntity, handled: 1},
+			},
+		},
+		{
+			name: "no key",
+			requests: []request{
+				{path: "/posts", body: `{"n":1}`, status: http.StatusOK, handled: 1},
+				{path: "/posts", body: `{"n":1}`, status: http.StatusOK, handled: 2},
+			},
+		},
+		{
+			name: "logins ignore the key",
+			requests: []request{
+				{path: "/login", key: "a", body: `{"n":1}`, status: http.StatusOK, handled: 1},
+				{path: "/login", key: "a", body: `{"n":1}`, status: http.StatusOK, handled: 2},
+			},
+		},
+		{
+			name: "key too long",
+			requests: []request{
+				{path: "/posts", key: strings.Repeat("a", maxIdempotencyKey+1), body: `{"n":1}`, status: http.StatusBadRequest},
+			},
+		},
+		{
+			name: "body at the limit",
+			requests: []request{
+				{path: "/posts", key: "a", body: strings.Repeat(" ", internal.MaxJSONBody), status: http.StatusOK, handled: 1},
+			},
+		},
+		{
+			name: "body over the limit",
+			requests: []request{
+				{path: "/posts", key: "a", body: strings.Repeat(" ", internal.MaxJSONBody+1), status: http.StatusRequestEntityTooLarge},
+				{path: "/posts", key: "a", body: `{"n":1}`, status: http.StatusOK, handled: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := IdempotencyPolicy{TTL: time.Minute, LockTTL: time.Minute}
+			m := New(slog.New(slog.NewTextHandler(io.Discard)), util.NewJwt("secret", "test", time.Hour), metrics.New(), nil, nil, CORSPolicy{}, memory.NewStore(), policy)
+			handled := 0
+			handler := func(ctx *gin.Context) {
+				body, err := io.ReadAll(ctx.Request.Body)
+				if err != nil {
+					t.Errorf("handler read the body: %v", err)
+				}
+				handled++
+				ctx.String(http.StatusOK, strconv.Itoa(handled)+":"+strconv.Itoa(len(body)))
+			}
+			r := gin.New()
+			r.Use(m.Idempotency, m.ErrorHandler)
+			r.POST("/posts", handler)
+			r.POST("/login", handler)
+			first := ""
+			for i, req := range tt.requests {
+				request := httptest.NewRequest(http.MethodPost, req.path, strings.NewReader(req.body))
+				if req.key != "" {
+					request.Header.Set(idempotencyKeyHeader, req.key)
+				}
+				recorder := httptest.NewRecorder()
+				r.ServeHTTP(recorder, request)
+				if recorder.Code != req.status {
+					t.Fatalf("request %d: got %d, want %d: %s", i, recorder.Code, req.status, recorder.Body)
+				}
+				if handled != req.handled {
+					t.Fatalf("request %d: handler ran %d times, want %d", i, handled, req.handled)
+				}
+				if replayed := recorder.Header().Get("Idempotent-Replayed") == "true"; replayed != req.replayed {
+					t.Fatalf("request %d: got replayed %v, want %v", i, replayed, req.replayed)
+				}
+				if req.replayed && recorder.Body.String() != first {
+					t.Fatalf("request %d: got %q, want the first response %q", i, recorder.Body, first)
+				}
+				if i == 0 {
+					first = recorder.Body.String()
+				}
+			}
+		})
+	}
+}
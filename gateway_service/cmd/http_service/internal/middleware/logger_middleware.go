@@ -2,7 +2,10 @@ package middleware
 
 import (
 	"bytes"
-	"gatewayservice/internal/util"
+	"gatewayservice/internal/logging"
+	"mime"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -22,13 +25,13 @@ func (w bodyLogWriter) Write(b []byte) (int, error) {
 func (m Middleware) Logger(ctx *gin.Context) {
 	start := time.Now()
 	const scope = "middleware#Logger"
-	ctx.Writer = &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: ctx.Writer}
+	if !isStreamingRequest(ctx) {
+		ctx.Writer = &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: ctx.Writer}
+	}
 	ctx.Next()
-	requestID := ctx.Value(util.RequestID).(string)
 	stop := time.Now()
-	m.logger.Info(
+	logging.FromContext(ctx, m.logger).Info(
 		"Handle user request",
-		slog.Any("request_id", requestID),
 		slog.String("scope", scope),
 		slog.String("ip", ctx.ClientIP()),
 		slog.String("method", ctx.Request.Method),
@@ -37,3 +40,23 @@ func (m Middleware) Logger(ctx *gin.Context) {
 		slog.String("latency", stop.Sub(start).String()),
 	)
 }
+
+// isStreamingRequest reports WebSocket and SSE requests, whose long-lived
+// bodies must not be buffered, and media downloads, whose bodies are large.
+func isStreamingRequest(ctx *gin.Context) bool {
+	return ctx.IsWebsocket() ||
+		strings.HasSuffix(ctx.FullPath(), "/realtime/sse") ||
+		acceptsEventStream(ctx.GetHeader("Accept")) ||
+		(ctx.Request.Method == http.MethodGet && strings.HasPrefix(strings.TrimPrefix(ctx.Request.URL.Path, "/v1"), "/media/"))
+}
+
+// acceptsEventStream reports whether an Accept header such as
+// "text/event-stream; charset=utf-8, */*" names the SSE media type.
+func acceptsEventStream(accept string) bool {
+	for _, item := range strings.Split(accept, ",") {
+		if mediaType, _, err := mime.ParseMediaType(item); err == nil && mediaType == "text/event-stream" {
+			return true
+		}
+	}
+	return false
+}
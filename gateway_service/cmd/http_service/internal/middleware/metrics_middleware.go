@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (m Middleware) Metrics(ctx *gin.Context) {
+	start := time.Now()
+	// The route template keeps the label set bounded; unknown paths share one
+	// value.
+	route := ctx.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	streaming := isStreamingRequest(ctx)
+	m.metrics.InFlight.Inc()
+	defer m.metrics.InFlight.Dec()
+	ctx.Next()
+	code := strconv.Itoa(ctx.Writer.Status())
+	m.metrics.RequestsTotal.WithLabelValues(ctx.Request.Method, route, code).Inc()
+	if !streaming {
+		m.metrics.RequestDuration.WithLabelValues(ctx.Request.Method, route, code).Observe(time.Since(start).Seconds())
+	}
+}
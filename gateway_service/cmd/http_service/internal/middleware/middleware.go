@@ -1,13 +1,36 @@
 package middleware
 
-import "golang.org/x/exp/slog"
+import (
+	"gatewayservice/internal/idempotency"
+	"gatewayservice/internal/metrics"
+	"gatewayservice/internal/ratelimit"
+	"gatewayservice/internal/util"
+
+	"golang.org/x/exp/slog"
+)
 
 type Middleware struct {
-	logger *slog.Logger
+	logger           *slog.Logger
+	jwt              *util.Jwt
+	metrics          *metrics.Metrics
+	rateLimitStore   ratelimit.IStore
+	rateLimits       map[string]ratelimit.Limit
+	cors             CORSPolicy
+	idempotencyStore idempotency.IStore
+	idempotency      IdempotencyPolicy
 }
 
-func New(logger *slog.Logger) *Middleware {
+// New takes a nil rateLimitStore to turn rate limiting off, and likewise a
+// nil idempotencyStore for idempotency keys.
+func New(logger *slog.Logger, jwt *util.Jwt, metrics *metrics.Metrics, rateLimitStore ratelimit.IStore, rateLimits map[string]ratelimit.Limit, cors CORSPolicy, idempotencyStore idempotency.IStore, idempotencyPolicy IdempotencyPolicy) *Middleware {
 	return &Middleware{
-		logger: logger,
+		logger:           logger,
+		jwt:              jwt,
+		metrics:          metrics,
+		rateLimitStore:   rateLimitStore,
+		rateLimits:       rateLimits,
+		cors:             cors,
+		idempotencyStore: idempotencyStore,
+		idempotency:      idempotencyPolicy,
 	}
 }
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"fmt"
+	"gatewayservice/cmd/http_service/internal"
+	"gatewayservice/internal/logging"
+	"gatewayservice/internal/ratelimit"
+	"gatewayservice/internal/util"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+)
+
+// RateLimit takes a token from the caller's bucket under policy. Callers are
+// told apart by user ID when it runs after Authenticate and by client IP
+// otherwise. When the store fails the request is let through, so an outage
+// of the store does not take the API down with it.
+func (m Middleware) RateLimit(policy string) gin.HandlerFunc {
+	limit, ok := m.rateLimits[policy]
+	if !ok || m.rateLimitStore == nil {
+		return func(ctx *gin.Context) {
+			ctx.Next()
+		}
+	}
+	return func(ctx *gin.Context) {
+		const scope = "middleware#RateLimit"
+		key := ratelimit.IPKey(policy, ctx.ClientIP())
+		if userID, ok := ctx.Get(util.UserID); ok {
+			key = ratelimit.UserKey(policy, userID.(int))
+		}
+		result, err := m.rateLimitStore.Take(ctx, key, limit)
+		if err != nil {
+			logging.FromContext(ctx, m.logger).Error(
+				"Failed to rate limit",
+				err,
+				slog.String("scope", scope),
+				slog.String("policy", policy),
+			)
+			ctx.Next()
+			return
+		}
+		ctx.Header("RateLimit-Limit", strconv.Itoa(limit.Requests))
+		ctx.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.Header("RateLimit-Reset", seconds(result.Reset))
+		ctx.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%s", limit.Requests, seconds(limit.Per)))
+		if !result.Allowed {
+			logging.FromContext(ctx, m.logger).Warn(
+				"Rate limit exceeded",
+				slog.String("scope", scope),
+				slog.String("policy", policy),
+			)
+			ctx.Header("Retry-After", seconds(result.RetryAfter))
+			ctx.Error(&internal.ErrTooManyRequests)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// seconds rounds up, so a client waiting that long is never early.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
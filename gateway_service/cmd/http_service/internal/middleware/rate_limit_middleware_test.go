@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gatewayservice/internal/metrics"
+	"gatewayservice/internal/ratelimit"
+	"gatewayservice/internal/ratelimit/memory"
+	"gatewayservice/internal/util"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwt := util.NewJwt("secret", "test", time.Hour)
+	token := func(userID int) string {
+		signed, err := jwt.GenerateSigned(userID, "user@example.com", "user")
+		if err != nil {
+			t.Fatalf("sign token: %v", err)
+		}
+		return "Bearer " + signed
+	}
+	type request struct {
+		ip            string
+		authorization string
+		status        int
+		remaining     string
+		retryAfter    string
+	}
+	tests := []struct {
+		name     string
+		requests []request
+	}{
+		{
+			name: "per IP",
+			requests: []request{
+				{ip: "192.0.2.1", status: http.StatusOK, remaining: "1"},
+				{ip: "192.0.2.1", status: http.StatusOK, remaining: "0"},
+				{ip: "192.0.2.1", status: http.StatusTooManyRequests, remaining: "0", retryAfter: "30"},
+				{ip: "192.0.2.2", status: http.StatusOK, remaining: "1"},
+			},
+		},
+		{
+			name: "per user whatever the IP",
+			requests: []request{
+				{ip: "192.0.2.1", authorization: token(1), status: http.StatusOK, remaining: "1"},
+				{ip: "192.0.2.2", authorization: token(1), status: http.StatusOK, remaining: "0"},
+				{ip: "192.0.2.3", authorization: token(1), status: http.StatusTooManyRequests, remaining: "0", retryAfter: "30"},
+				{ip: "192.0.2.3", authorization: token(2), status: http.StatusOK, remaining: "1"},
+				// Signed in or not, callers from one IP do not share a bucket.
+				{ip: "192.0.2.3", status: http.StatusOK, remaining: "1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := map[string]ratelimit.Limit{ratelimit.PolicyDefault: {Requests: 2, Per: time.Minute}}
+			m := New(slog.New(slog.NewTextHandler(io.Discard)), jwt, metrics.New(), memory.NewStore(), limits, CORSPolicy{}, nil, IdempotencyPolicy{})
+			r := gin.New()
+			r.Use(m.ErrorHandler)
+			r.GET("/", m.OptionalAuthenticate, m.RateLimit(ratelimit.PolicyDefault), func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+			for i, req := range tt.requests {
+				request := httptest.NewRequest(http.MethodGet, "/", nil)
+				request.RemoteAddr = req.ip + ":1234"
+				if req.authorization != "" {
+					request.Header.Set("Authorization", req.authorization)
+				}
+				recorder := httptest.NewRecorder()
+				r.ServeHTTP(recorder, request)
+				header := recorder.Header()
+				if recorder.Code != req.status || header.Get("RateLimit-Remaining") != req.remaining || header.Get("Retry-After") != req.retryAfter {
+					t.Fatalf("request %d got %d, remaining %q, retry after %q", i, recorder.Code, header.Get("RateLimit-Remaining"), header.Get("Retry-After"))
+				}
+				if header.Get("RateLimit-Limit") != "2" || header.Get("RateLimit-Policy") != "2;w=60" {
+					t.Fatalf("request %d got limit %q, policy %q", i, header.Get("RateLimit-Limit"), header.Get("RateLimit-Policy"))
+				}
+			}
+		})
+	}
+}
+
+// Without a store, or for a policy that is not configured, nothing is
+// limited.
+func TestRateLimitOff(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limits := map[string]ratelimit.Limit{ratelimit.PolicyDefault: {Requests: 1, Per: time.Minute}}
+	tests := []struct {
+		name   string
+		store  ratelimit.IStore
+		policy string
+	}{
+		{name: "no store", store: nil, policy: ratelimit.PolicyDefault},
+		{name: "no such policy", store: memory.NewStore(), policy: ratelimit.PolicyMedia},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(slog.New(slog.NewTextHandler(io.Discard)), nil, metrics.New(), tt.store, limits, CORSPolicy{}, nil, IdempotencyPolicy{})
+			r := gin.New()
+			r.GET("/", m.RateLimit(tt.policy), func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+			for i := 0; i < 3; i++ {
+				recorder := httptest.NewRecorder()
+				r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+				if recorder.Code != http.StatusOK || recorder.Header().Get("RateLimit-Limit") != "" {
+					t.Fatalf("request %d got %d, limit %q", i, recorder.Code, recorder.Header().Get("RateLimit-Limit"))
+				}
+			}
+		})
+	}
+}
@@ -1,14 +1,28 @@
 package middleware
 
 import (
+	"gatewayservice/internal/logging"
 	"gatewayservice/internal/util"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
 )
 
+const requestIDHeader = "X-Request-ID"
+
+// RequestID keeps the ID an upstream proxy sent in X-Request-ID, or generates
+// one, and echoes it in the response. It also stores the request-scoped
+// logger, which later middleware and every layer below the handlers read with
+// logging.FromContext.
 func (m Middleware) RequestID(ctx *gin.Context) {
-	requestID := uuid.New().String()
+	requestID := util.RequestIDOrNew(ctx.GetHeader(requestIDHeader))
 	ctx.Set(util.RequestID, requestID)
+	ctx.Header(requestIDHeader, requestID)
+	logger := m.logger.With(slog.String("request_id", requestID))
+	if spanContext := trace.SpanContextFromContext(ctx.Request.Context()); spanContext.IsValid() {
+		logger = logger.With(slog.String("trace_id", spanContext.TraceID().String()))
+	}
+	ctx.Request = ctx.Request.WithContext(logging.NewContext(ctx.Request.Context(), logger))
 	ctx.Next()
 }
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("gatewayservice/cmd/http_service")
+
+// Tracing starts the server span of a request, continuing the caller's trace
+// when it sends a traceparent header. Handlers reach the span through the
+// request context, which gin falls back to.
+func (m Middleware) Tracing(ctx *gin.Context) {
+	route := ctx.FullPath()
+	spanName := ctx.Request.Method + " " + route
+	if route == "" {
+		spanName = ctx.Request.Method + " unmatched"
+	}
+	reqCtx := otel.GetTextMapPropagator().Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+	reqCtx, span := tracer.Start(
+		reqCtx,
+		spanName,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPMethod(ctx.Request.Method),
+			semconv.HTTPRoute(route),
+			semconv.HTTPTarget(ctx.Request.URL.Path),
+		),
+	)
+	defer span.End()
+	ctx.Request = ctx.Request.WithContext(reqCtx)
+	ctx.Next()
+	status := ctx.Writer.Status()
+	span.SetAttributes(semconv.HTTPStatusCode(status))
+	for _, err := range ctx.Errors {
+		span.RecordError(err.Err)
+	}
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+}
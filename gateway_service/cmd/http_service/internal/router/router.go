@@ -1,20 +1,64 @@
 package router
 
 import (
+	"gatewayservice/cmd/http_service/internal/docs"
 	"gatewayservice/cmd/http_service/internal/handler"
 	"gatewayservice/cmd/http_service/internal/middleware"
+	"gatewayservice/cmd/http_service/internal/transcoder"
+	"gatewayservice/internal/ratelimit"
+	"net/http"
+	"sort"
 
 	"github.com/gin-gonic/gin"
 )
 
-func New(h *handler.Handler, m *middleware.Middleware) *gin.Engine {
+// Versions holds the deprecation policy of each API version.
+type Versions struct {
+	V1 middleware.DeprecationPolicy
+	// Unversioned is the policy of the paths from before versioning, served
+	// as aliases of v1 while it is set.
+	Unversioned *middleware.DeprecationPolicy
+}
+
+func New(h *handler.Handler, m *middleware.Middleware, t *transcoder.Transcoder, metricsHandler http.Handler, spec []byte, versions Versions) (*gin.Engine, error) {
 	r := gin.New()
-	r.Use(m.Logger, m.ErrorHandler, m.CORSMiddleware, m.RequestID)
-	r.POST("/register", h.RegisterUser)
-	r.POST("/login", h.LoginUser)
-	r.GET("/users/:userID", h.FindUserByID)
-	r.POST("/users/:userID/softdelete", h.DeleteUserByID)
-	r.DELETE("/users/:userID", h.DeleteUserPermanentlyByID)
+	r.ContextWithFallback = true
+	// Probes and scrapes are registered before the middleware so they are
+	// neither logged nor counted.
+	r.GET("/healthz", h.Healthz)
+	r.GET("/readyz", h.Readyz)
+	r.GET("/metrics", gin.WrapH(metricsHandler))
+	// Idempotency goes between CORSMiddleware, whose headers replays need,
+	// and ErrorHandler, whose responses it keeps.
+	r.Use(m.Tracing, m.RequestID, m.Metrics, m.Logger, m.CORSMiddleware, m.Idempotency, m.ErrorHandler)
+	limit := m.RateLimit(ratelimit.PolicyDefault)
+	r.GET("/openapi.json", limit, docs.ServeSpec(spec))
+	r.GET("/docs", limit, docs.ServeUI)
+	v1 := r.Group(docs.V1Prefix, m.Deprecated(versions.V1))
+	if err := registerV1(v1, h, m, t); err != nil {
+		return nil, err
+	}
+	if versions.Unversioned != nil {
+		policy := *versions.Unversioned
+		policy.Prefix, policy.Successor = "", docs.V1Prefix
+		if err := registerV1(r.Group("", m.Deprecated(policy)), h, m, t); err != nil {
+			return nil, err
+		}
+	}
+	// Browsers preflight cross-origin requests, so every path answers OPTIONS
+	// with the methods registered for it above.
+	methods := map[string][]string{}
+	paths := []string{}
+	for _, route := range r.Routes() {
+		if _, ok := methods[route.Path]; !ok {
+			paths = append(paths, route.Path)
+		}
+		methods[route.Path] = append(methods[route.Path], route.Method)
+	}
+	for _, path := range paths {
+		sort.Strings(methods[path])
+		r.OPTIONS(path, m.Preflight(methods[path]))
+	}
 	r.NoRoute(h.NoRoute)
-	return r
+	return r, nil
 }
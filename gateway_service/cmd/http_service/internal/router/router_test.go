@@ -0,0 +1,528 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gatewayservice/cmd/http_service/internal/docs"
+	"gatewayservice/cmd/http_service/internal/handler"
+	"gatewayservice/cmd/http_service/internal/middleware"
+	"gatewayservice/cmd/http_service/internal/transcoder"
+	"gatewayservice/internal/dto/req"
+	"gatewayservice/internal/dto/resp"
+	idempotencyMemory "gatewayservice/internal/idempotency/memory"
+	"gatewayservice/internal/metrics"
+	"gatewayservice/internal/pubsub/memory"
+	"gatewayservice/internal/ratelimit"
+	rateLimitMemory "gatewayservice/internal/ratelimit/memory"
+	"gatewayservice/internal/usecase"
+	"gatewayservice/internal/util"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	bookmarkPb "github.com/ideaspaper/social-media-proto/bookmark"
+	messagePb "github.com/ideaspaper/social-media-proto/message"
+	moderationPb "github.com/ideaspaper/social-media-proto/moderation"
+	userPb "github.com/ideaspaper/social-media-proto/user"
+)
+
+// TestRoutesMatchSpec sends a request to every operation of the spec through
+// the router, with usecases and a user service that answer with every field
+// set, and validates the request and response against the spec. A handler
+// that answers with another status, envelope or DTO than its operation
+// documents fails here.
+func TestRoutesMatchSpec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	spec, err := docs.Spec("test")
+	if err != nil {
+		t.Fatalf("generate spec: %v", err)
+	}
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Fatalf("invalid spec: %v", err)
+	}
+	forbidUndocumentedProperties(doc)
+	specRouter, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatalf("route spec: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard))
+	jwt := util.NewJwt("secret", "test", time.Hour)
+	token, err := jwt.GenerateSigned(1, "moderator@example.com", "moderator")
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	limit := ratelimit.Limit{Requests: 1000, Per: time.Minute}
+	m := middleware.New(
+		logger,
+		jwt,
+		metrics.New(),
+		rateLimitMemory.NewStore(),
+		map[string]ratelimit.Limit{ratelimit.PolicyAuth: limit, ratelimit.PolicyMedia: limit, ratelimit.PolicyDefault: limit},
+		middleware.CORSPolicy{},
+		idempotencyMemory.NewStore(),
+		middleware.IdempotencyPolicy{TTL: time.Hour, LockTTL: time.Minute},
+	)
+	realtimeUsecase := usecase.NewRealtimeUsecase(logger, memory.NewPubSub(logger, 16, time.Minute, 16))
+	fake := fakeUsecase{}
+	h := handler.New(logger, fake, realtimeUsecase, fake, fake, fake, fake, fake, fake, nil)
+	engine, err := New(h, m, transcoder.New(logger, fakeConn{}), http.NotFoundHandler(), spec, Versions{Unversioned: &middleware.DeprecationPolicy{}})
+	if err != nil {
+		t.Fatalf("build router: %v", err)
+	}
+	if err := docs.Check(engine.Routes()); err != nil {
+		t.Fatalf("routes drifted from the spec: %v", err)
+	}
+	server := httptest.NewServer(engine)
+	defer server.Close()
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for method, op := range doc.Paths[path].Operations() {
+			op := op
+			t.Run(method+" "+path, func(t *testing.T) {
+				checkOperation(t, server.URL, specRouter, method, path, op, token)
+			})
+		}
+	}
+}
+
+func checkOperation(t *testing.T, serverURL string, specRouter routers.Router, method string, path string, op *openapi3.Operation, token string) {
+	target := path
+	query := url.Values{}
+	header := http.Header{}
+	for _, ref := range op.Parameters {
+		param := ref.Value
+		value := example(param.Schema.Value)
+		switch param.In {
+		case openapi3.ParameterInPath:
+			target = strings.ReplaceAll(target, "{"+param.Name+"}", fmt.Sprint(value))
+		case openapi3.ParameterInQuery:
+			if !param.Required {
+				continue
+			}
+			if items, ok := value.([]interface{}); ok {
+				for _, item := range items {
+					query.Add(param.Name, fmt.Sprint(item))
+				}
+			} else {
+				query.Set(param.Name, fmt.Sprint(value))
+			}
+		}
+	}
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	body := []byte{}
+	if op.RequestBody != nil {
+		content := op.RequestBody.Value.Content
+		if mediaType := content.Get("application/json"); mediaType != nil {
+			body, _ = json.Marshal(example(mediaType.Schema.Value))
+			header.Set("Content-Type", "application/json")
+		} else if content.Get("multipart/form-data") != nil {
+			buf := &bytes.Buffer{}
+			writer := multipart.NewWriter(buf)
+			part, _ := writer.CreateFormFile("file", "image.png")
+			part.Write([]byte("\x89PNG\r\n\x1a\n"))
+			writer.Close()
+			body = buf.Bytes()
+			header.Set("Content-Type", writer.FormDataContentType())
+		}
+	}
+	if op.Security != nil && len(*op.Security) > 0 {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	if op.Responses.Get(http.StatusSwitchingProtocols) != nil {
+		header.Set("Connection", "Upgrade")
+		header.Set("Upgrade", "websocket")
+		header.Set("Sec-WebSocket-Version", "13")
+		header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	}
+
+	validationRequest := httptest.NewRequest(method, target, bytes.NewReader(body))
+	validationRequest.Header = header.Clone()
+	route, pathParams, err := specRouter.FindRoute(validationRequest)
+	if err != nil {
+		t.Fatalf("find %s %s in the spec: %v", method, target, err)
+	}
+	input := &openapi3filter.RequestValidationInput{
+		Request:    validationRequest,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+	}
+	if err := openapi3filter.ValidateRequest(context.Background(), input); err != nil {
+		t.Fatalf("request does not match the spec: %v", err)
+	}
+
+	request, err := http.NewRequest(method, serverURL+target, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	request.Header = header
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("send request: %v", err)
+	}
+	defer response.Body.Close()
+	// Streams and files are only checked up to their headers.
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	isJSON := mediaType == "application/json"
+	responseBody := []byte{}
+	if isJSON {
+		if responseBody, err = io.ReadAll(response.Body); err != nil {
+			t.Fatalf("read response: %v", err)
+		}
+	}
+	if want := successStatus(op); response.StatusCode != want {
+		t.Fatalf("got status %d, the spec documents %d: %s", response.StatusCode, want, responseBody)
+	}
+	err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 response.StatusCode,
+		Header:                 response.Header,
+		Body:                   io.NopCloser(bytes.NewReader(responseBody)),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			ExcludeResponseBody:   !isJSON,
+		},
+	})
+	if err != nil {
+		t.Fatalf("response does not match the spec: %v\n%s", err, responseBody)
+	}
+}
+
+// forbidUndocumentedProperties makes the component schemas reject fields
+// they do not list, so a handler answering with another DTO than the one its
+// operation documents is caught. The spec itself leaves them open so clients
+// tolerate new fields.
+func forbidUndocumentedProperties(doc *openapi3.T) {
+	for _, ref := range doc.Components.Schemas {
+		schema := ref.Value
+		if schema.Type == openapi3.TypeObject && len(schema.Properties) > 0 && schema.AdditionalProperties.Has == nil && schema.AdditionalProperties.Schema == nil {
+			schema.AdditionalProperties.Has = openapi3.BoolPtr(false)
+		}
+	}
+}
+
+func successStatus(op *openapi3.Operation) int {
+	for code := range op.Responses {
+		if status, err := strconv.Atoi(code); err == nil && status < http.StatusMultipleChoices && status != http.StatusNotModified {
+			return status
+		}
+	}
+	return 0
+}
+
+// example returns a value that satisfies schema.
+func example(schema *openapi3.Schema) interface{} {
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+	switch schema.Type {
+	case openapi3.TypeObject:
+		result := map[string]interface{}{}
+		for name, property := range schema.Properties {
+			result[name] = example(property.Value)
+		}
+		return result
+	case openapi3.TypeArray:
+		result := []interface{}{}
+		for n := uint64(0); n < schema.MinItems || n == 0; n++ {
+			result = append(result, example(schema.Items.Value))
+		}
+		return result
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		if schema.Min == nil {
+			return 1
+		}
+		if schema.ExclusiveMin {
+			return int(*schema.Min) + 1
+		}
+		return int(*schema.Min)
+	case openapi3.TypeBoolean:
+		return true
+	case openapi3.TypeString:
+		if schema.Format == "email" {
+			return "user@example.com"
+		}
+		length := int(schema.MinLength)
+		if length == 0 {
+			length = 8
+		}
+		if schema.Pattern == "" {
+			return strings.Repeat("a", length)
+		}
+		pattern := regexp.MustCompile(schema.Pattern)
+		for _, candidate := range []string{strings.Repeat("a", length), strings.Repeat("a", 64), strings.Repeat("1", length)} {
+			if pattern.MatchString(candidate) {
+				return candidate
+			}
+		}
+		return ""
+	default:
+		return nil
+	}
+}
+
+// fill sets every field of m, a single item in lists and maps, so the
+// response covers the whole schema.
+func fill[M proto.Message](m M) M {
+	fillMessage(m.ProtoReflect(), 0)
+	return m
+}
+
+func fillMessage(m protoreflect.Message, depth int) {
+	if depth > 5 {
+		return
+	}
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		switch {
+		case field.IsList():
+			list := m.Mutable(field).List()
+			if field.Message() != nil {
+				item := list.NewElement()
+				fillMessage(item.Message(), depth+1)
+				list.Append(item)
+			} else {
+				list.Append(scalar(field))
+			}
+		case field.IsMap():
+			entries := m.Mutable(field).Map()
+			key := scalar(field.MapKey()).MapKey()
+			if field.MapValue().Message() != nil {
+				fillMessage(entries.Mutable(key).Message(), depth+1)
+			} else {
+				entries.Set(key, scalar(field.MapValue()))
+			}
+		case field.Message() != nil:
+			fillMessage(m.Mutable(field).Message(), depth+1)
+		default:
+			m.Set(field, scalar(field))
+		}
+	}
+}
+
+func scalar(field protoreflect.FieldDescriptor) protoreflect.Value {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(true)
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		return protoreflect.ValueOfEnum(values.Get(values.Len() - 1).Number())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(1)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(1)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(1)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(1)
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(1)
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(1)
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte("bytes"))
+	default:
+		return protoreflect.ValueOfString("text")
+	}
+}
+
+// fakeConn answers every RPC the transcoder makes.
+type fakeConn struct{}
+
+func (fakeConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	fillMessage(reply.(proto.Message).ProtoReflect(), 0)
+	return nil
+}
+
+func (fakeConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, errors.New("streams are not supported")
+}
+
+// fakeUsecase stands in for every usecase the handlers call.
+type fakeUsecase struct{}
+
+func (fakeUsecase) FindUsersByIDs(ctx context.Context, userIDs []int64) ([]*userPb.UserResp, []error) {
+	users := make([]*userPb.UserResp, len(userIDs))
+	for i := range userIDs {
+		users[i] = fill(&userPb.UserResp{})
+	}
+	return users, make([]error, len(userIDs))
+}
+
+func (fakeUsecase) DeleteUserByID(ctx context.Context, userID int) (*userPb.DeleteByIDResp, error) {
+	return fill(&userPb.DeleteByIDResp{}), nil
+}
+
+func (fakeUsecase) DeleteUserPermanentlyByID(ctx context.Context, userID int) (*userPb.DeletePermanentlyByIDResp, error) {
+	return fill(&userPb.DeletePermanentlyByIDResp{}), nil
+}
+
+func (fakeUsecase) Register(ctx context.Context, registerDto *req.UserDto) (*userPb.RegisterResp, error) {
+	return fill(&userPb.RegisterResp{}), nil
+}
+
+func (fakeUsecase) Login(ctx context.Context, loginDto *req.LoginDto) (*resp.LoginDto, error) {
+	return &resp.LoginDto{Token: "token"}, nil
+}
+
+func (fakeUsecase) CreateConversation(ctx context.Context, userID int, conversationDto *req.ConversationDto) (*messagePb.CreateConversationResp, error) {
+	return fill(&messagePb.CreateConversationResp{}), nil
+}
+
+func (fakeUsecase) FindConversations(ctx context.Context, userID int, pageDto *req.PageDto) (*messagePb.FindConversationsResp, error) {
+	return fill(&messagePb.FindConversationsResp{}), nil
+}
+
+func (fakeUsecase) FindConversationByID(ctx context.Context, userID int, conversationID int) (*messagePb.FindConversationByIDResp, error) {
+	return fill(&messagePb.FindConversationByIDResp{}), nil
+}
+
+func (fakeUsecase) SendMessage(ctx context.Context, userID int, conversationID int, messageDto *req.MessageDto) (*messagePb.SendMessageResp, error) {
+	return fill(&messagePb.SendMessageResp{}), nil
+}
+
+func (fakeUsecase) FindMessages(ctx context.Context, userID int, conversationID int, pageDto *req.PageDto) (*messagePb.FindMessagesResp, error) {
+	return fill(&messagePb.FindMessagesResp{}), nil
+}
+
+func (fakeUsecase) MarkRead(ctx context.Context, userID int, conversationID int, markReadDto *req.MarkReadDto) (*messagePb.MarkReadResp, error) {
+	return fill(&messagePb.MarkReadResp{}), nil
+}
+
+func (fakeUsecase) CountUnread(ctx context.Context, userID int) (*messagePb.CountUnreadResp, error) {
+	return fill(&messagePb.CountUnreadResp{}), nil
+}
+
+func (fakeUsecase) UpdateMessagePermission(ctx context.Context, userID int, messagePermissionDto *req.MessagePermissionDto) (*messagePb.UpdateMessagePermissionResp, error) {
+	return fill(&messagePb.UpdateMessagePermissionResp{}), nil
+}
+
+func (fakeUsecase) CreateCollection(ctx context.Context, userID int, collectionDto *req.CollectionDto) (*bookmarkPb.CreateCollectionResp, error) {
+	return fill(&bookmarkPb.CreateCollectionResp{}), nil
+}
+
+func (fakeUsecase) UpdateCollection(ctx context.Context, userID int, collectionID int, updateCollectionDto *req.UpdateCollectionDto) (*bookmarkPb.UpdateCollectionResp, error) {
+	return fill(&bookmarkPb.UpdateCollectionResp{}), nil
+}
+
+func (fakeUsecase) DeleteCollection(ctx context.Context, userID int, collectionID int) (*bookmarkPb.DeleteCollectionResp, error) {
+	return fill(&bookmarkPb.DeleteCollectionResp{}), nil
+}
+
+func (fakeUsecase) FindCollections(ctx context.Context, viewerID int, userID int, pageDto *req.PageDto) (*bookmarkPb.FindCollectionsResp, error) {
+	return fill(&bookmarkPb.FindCollectionsResp{}), nil
+}
+
+func (fakeUsecase) AddBookmark(ctx context.Context, userID int, bookmarkDto *req.BookmarkDto) (*bookmarkPb.AddBookmarkResp, error) {
+	return fill(&bookmarkPb.AddBookmarkResp{}), nil
+}
+
+func (fakeUsecase) RemoveBookmark(ctx context.Context, userID int, itemType string, itemID int) (*bookmarkPb.RemoveBookmarkResp, error) {
+	return fill(&bookmarkPb.RemoveBookmarkResp{}), nil
+}
+
+func (fakeUsecase) FindBookmarks(ctx context.Context, userID int, pageDto *req.PageDto) (*bookmarkPb.FindBookmarksResp, error) {
+	return fill(&bookmarkPb.FindBookmarksResp{}), nil
+}
+
+func (fakeUsecase) FindCollectionBookmarks(ctx context.Context, viewerID int, collectionID int, pageDto *req.PageDto) (*bookmarkPb.FindCollectionBookmarksResp, error) {
+	return fill(&bookmarkPb.FindCollectionBookmarksResp{}), nil
+}
+
+func (fakeUsecase) CreateReport(ctx context.Context, reporterID int, reportDto *req.ReportDto) (*moderationPb.CreateReportResp, error) {
+	return fill(&moderationPb.CreateReportResp{}), nil
+}
+
+func (fakeUsecase) FindReports(ctx context.Context, moderatorID int, reportFilterDto *req.ReportFilterDto, pageDto *req.PageDto) (*moderationPb.FindReportsResp, error) {
+	return fill(&moderationPb.FindReportsResp{}), nil
+}
+
+func (fakeUsecase) FindReportByID(ctx context.Context, moderatorID int, reportID int) (*moderationPb.FindReportByIDResp, error) {
+	return fill(&moderationPb.FindReportByIDResp{}), nil
+}
+
+func (fakeUsecase) TakeAction(ctx context.Context, moderatorID int, reportID int, moderationActionDto *req.ModerationActionDto) (*moderationPb.TakeActionResp, error) {
+	return fill(&moderationPb.TakeActionResp{}), nil
+}
+
+func (fakeUsecase) FindActions(ctx context.Context, moderatorID int, pageDto *req.PageDto) (*moderationPb.FindActionsResp, error) {
+	return fill(&moderationPb.FindActionsResp{}), nil
+}
+
+func (fakeUsecase) Upload(ctx context.Context, userID int, r io.Reader) (*resp.MediaDto, error) {
+	return fakeMedia(), nil
+}
+
+func (fakeUsecase) FindByID(ctx context.Context, mediaID string) (*resp.MediaDto, error) {
+	return fakeMedia(), nil
+}
+
+func (fakeUsecase) FindUploadedByIDs(ctx context.Context, userID int, mediaIDs []string) ([]*resp.MediaDto, error) {
+	return []*resp.MediaDto{fakeMedia()}, nil
+}
+
+func (fakeUsecase) Open(ctx context.Context, mediaID string, variant string) (io.ReadCloser, *resp.MediaDto, error) {
+	return io.NopCloser(strings.NewReader("image")), fakeMedia(), nil
+}
+
+func fakeMedia() *resp.MediaDto {
+	thumbnailURL := "/v1/media/" + strings.Repeat("a", 64) + "/thumbnail"
+	return &resp.MediaDto{
+		ID:           strings.Repeat("a", 64),
+		ContentType:  "image/png",
+		Size:         5,
+		Width:        1,
+		Height:       1,
+		URL:          "/v1/media/" + strings.Repeat("a", 64) + "/content",
+		ThumbnailURL: &thumbnailURL,
+		CreatedAt:    time.Now().String(),
+	}
+}
+
+func (fakeUsecase) Liveness(ctx context.Context) *resp.HealthDto {
+	return &resp.HealthDto{Status: usecase.HealthStatusUp, Checks: map[string]string{}}
+}
+
+func (fakeUsecase) Readiness(ctx context.Context) *resp.HealthDto {
+	return &resp.HealthDto{Status: usecase.HealthStatusUp, Checks: map[string]string{"user_service": usecase.HealthStatusUp}}
+}
+
+func (fakeUsecase) Execute(ctx context.Context, graphqlDto *req.GraphQLDto) (*graphql.Result, error) {
+	return &graphql.Result{Data: map[string]interface{}{"user": map[string]interface{}{"id": 1}}}, nil
+}
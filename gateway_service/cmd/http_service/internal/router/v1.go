@@ -0,0 +1,61 @@
+package router
+
+import (
+	"gatewayservice/cmd/http_service/internal/handler"
+	"gatewayservice/cmd/http_service/internal/middleware"
+	"gatewayservice/cmd/http_service/internal/transcoder"
+	"gatewayservice/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	userPb "github.com/ideaspaper/social-media-proto/user"
+)
+
+// registerV1 adds the routes of v1 to r. A later version gets a register
+// function and handlers of its own, built on the same usecases, so both can
+// be served while clients move over.
+func registerV1(r *gin.RouterGroup, h *handler.Handler, m *middleware.Middleware, t *transcoder.Transcoder) error {
+	// Rate limits go after Authenticate where there is one, so signed-in
+	// callers are limited per user instead of per IP.
+	limit := m.RateLimit(ratelimit.PolicyDefault)
+	r.POST("/register", m.RateLimit(ratelimit.PolicyAuth), h.RegisterUser)
+	r.POST("/login", m.RateLimit(ratelimit.PolicyAuth), h.LoginUser)
+	// The user RPCs annotated with HTTP rules in user.proto.
+	if err := t.Register(r, userPb.File_user_user_proto.Services().ByName("UserService"), m.Authenticate, limit); err != nil {
+		return err
+	}
+	conversations := r.Group("/conversations", m.Authenticate, limit)
+	conversations.POST("", h.CreateConversation)
+	conversations.GET("", h.FindConversations)
+	conversations.GET("/unread", h.CountUnreadMessages)
+	conversations.GET("/:conversationID", h.FindConversationByID)
+	conversations.GET("/:conversationID/messages", h.FindMessages)
+	conversations.POST("/:conversationID/messages", h.SendMessage)
+	conversations.POST("/:conversationID/read", h.MarkConversationRead)
+	r.PUT("/settings/message-permission", m.Authenticate, limit, h.UpdateMessagePermission)
+	r.GET("/users/:id/bookmark-collections", m.Authenticate, limit, h.FindUserBookmarkCollections)
+	bookmarks := r.Group("/bookmarks", m.Authenticate, limit)
+	bookmarks.POST("", h.AddBookmark)
+	bookmarks.GET("", h.FindBookmarks)
+	bookmarks.DELETE("/:itemType/:itemID", h.RemoveBookmark)
+	bookmarkCollections := r.Group("/bookmark-collections", m.Authenticate, limit)
+	bookmarkCollections.POST("", h.CreateBookmarkCollection)
+	bookmarkCollections.GET("", h.FindBookmarkCollections)
+	bookmarkCollections.PATCH("/:collectionID", h.UpdateBookmarkCollection)
+	bookmarkCollections.DELETE("/:collectionID", h.DeleteBookmarkCollection)
+	bookmarkCollections.GET("/:collectionID/bookmarks", h.FindCollectionBookmarks)
+	r.POST("/reports", m.Authenticate, limit, h.CreateReport)
+	moderation := r.Group("/moderation", m.Authenticate, limit, m.RequireRole("moderator"))
+	moderation.GET("/reports", h.FindReports)
+	moderation.GET("/reports/:reportID", h.FindReportByID)
+	moderation.POST("/reports/:reportID/actions", h.TakeModerationAction)
+	moderation.GET("/actions", h.FindModerationActions)
+	r.POST("/graphql", m.OptionalAuthenticate, limit, h.GraphQL)
+	r.POST("/media", m.Authenticate, m.RateLimit(ratelimit.PolicyMedia), h.UploadMedia)
+	r.GET("/media/:mediaID", limit, h.FindMediaByID)
+	r.GET("/media/:mediaID/content", limit, h.ServeMediaContent)
+	r.GET("/media/:mediaID/thumbnail", limit, h.ServeMediaThumbnail)
+	realtime := r.Group("/realtime", m.AuthenticateStream, limit)
+	realtime.GET("/ws", h.StreamEventsWebSocket)
+	realtime.GET("/sse", h.StreamEventsSSE)
+	return nil
+}
@@ -0,0 +1,527 @@
+package transcoder
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"gatewayservice/cmd/http_service/internal"
+	handlerUtil "gatewayservice/cmd/http_service/internal/util"
+	"gatewayservice/internal/logging"
+	"gatewayservice/internal/usecase"
+	"gatewayservice/internal/util"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Transcoder serves the RPCs of a gRPC service that carry a google.api.http
+// annotation as REST routes, so they need no handler or usecase of their
+// own. Path variables, query params and the body are bound into the request
+// message, and the response goes out in the same envelope the handlers use.
+//
+// Resources with an int64 version field get conditional requests: GET
+// answers with their version as a strong ETag and with 304 when it is in
+// If-None-Match, and other methods whose request has a repeated int64
+// versions field take the versions listed in If-Match. The service answers
+// with Aborted, which becomes 412, when none of them is current.
+//
+// RPCs whose request has an int64 caller_id field act for the signed-in
+// user. They are served behind authentication, and caller_id is always the
+// user of the access token, whatever the request says.
+type Transcoder struct {
+	logger *slog.Logger
+	conn   grpc.ClientConnInterface
+}
+
+func New(logger *slog.Logger, conn grpc.ClientConnInterface) *Transcoder {
+	return &Transcoder{
+		logger: logger,
+		conn:   conn,
+	}
+}
+
+// binding is one HTTP rule of an annotated RPC.
+type binding struct {
+	fullMethod string
+	httpMethod string
+	// path is the gin path, with a param named after the field path of each
+	// variable, such as :id for {id}.
+	path   string
+	input  protoreflect.MessageType
+	output protoreflect.MessageType
+	vars   map[string][]protoreflect.FieldDescriptor
+	// body is nil when the request has none, and empty when it maps to the
+	// whole request message.
+	body         []protoreflect.FieldDescriptor
+	responseBody protoreflect.FieldDescriptor
+	// version leads from the rendered response to the version its ETag is
+	// built from. It is only set for GET.
+	version []protoreflect.FieldDescriptor
+	// precondition is the request field the versions in If-Match are bound
+	// to.
+	precondition protoreflect.FieldDescriptor
+	// caller is the request field bound to the signed-in user.
+	caller protoreflect.FieldDescriptor
+}
+
+// Register adds a route to r for every HTTP rule of the annotated methods of
+// service, behind handlers such as rate limiting. Routes that act for the
+// caller go behind authenticate first.
+func (t *Transcoder) Register(r gin.IRoutes, service protoreflect.ServiceDescriptor, authenticate gin.HandlerFunc, handlers ...gin.HandlerFunc) error {
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+		rule, ok := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+		for _, rule := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+			b, err := newBinding(service, method, rule)
+			if err != nil {
+				return fmt.Errorf("transcode %s: %w", method.FullName(), err)
+			}
+			chain := []gin.HandlerFunc{}
+			if b.caller != nil {
+				chain = append(chain, authenticate)
+			}
+			chain = append(append(chain, handlers...), t.handle(b))
+			r.Handle(b.httpMethod, b.path, chain...)
+		}
+	}
+	return nil
+}
+
+func newBinding(service protoreflect.ServiceDescriptor, method protoreflect.MethodDescriptor, rule *annotations.HttpRule) (*binding, error) {
+	if method.IsStreamingClient() || method.IsStreamingServer() {
+		return nil, fmt.Errorf("streaming methods are not supported")
+	}
+	b := &binding{
+		fullMethod: fmt.Sprintf("/%s/%s", service.FullName(), method.Name()),
+		vars:       map[string][]protoreflect.FieldDescriptor{},
+	}
+	var template string
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		b.httpMethod, template = http.MethodGet, pattern.Get
+	case *annotations.HttpRule_Put:
+		b.httpMethod, template = http.MethodPut, pattern.Put
+	case *annotations.HttpRule_Post:
+		b.httpMethod, template = http.MethodPost, pattern.Post
+	case *annotations.HttpRule_Delete:
+		b.httpMethod, template = http.MethodDelete, pattern.Delete
+	case *annotations.HttpRule_Patch:
+		b.httpMethod, template = http.MethodPatch, pattern.Patch
+	case *annotations.HttpRule_Custom:
+		b.httpMethod, template = pattern.Custom.GetKind(), pattern.Custom.GetPath()
+	default:
+		return nil, fmt.Errorf("rule has no pattern")
+	}
+	var err error
+	if b.input, err = protoregistry.GlobalTypes.FindMessageByName(method.Input().FullName()); err != nil {
+		return nil, err
+	}
+	if b.output, err = protoregistry.GlobalTypes.FindMessageByName(method.Output().FullName()); err != nil {
+		return nil, err
+	}
+	segments := strings.Split(template, "/")
+	if !strings.HasPrefix(template, "/") {
+		return nil, fmt.Errorf("path %q is not absolute", template)
+	}
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") {
+			// Wildcards and custom verbs have no equivalent in gin's router.
+			if strings.ContainsAny(segment, "*:{}") {
+				return nil, fmt.Errorf("path %q: segment %q is not supported", template, segment)
+			}
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+		if name == segment[1:] || strings.Contains(name, "=") {
+			return nil, fmt.Errorf("path %q: variable %q is not supported", template, segment)
+		}
+		fields, err := fieldPath(method.Input(), name)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: %w", template, err)
+		}
+		last := fields[len(fields)-1]
+		if last.IsList() || last.IsMap() || last.Kind() == protoreflect.MessageKind || last.Kind() == protoreflect.GroupKind {
+			return nil, fmt.Errorf("path %q: %s is not a scalar", template, name)
+		}
+		b.vars[name] = fields
+		segments[i] = ":" + name
+	}
+	b.path = strings.Join(segments, "/")
+	switch body := rule.GetBody(); body {
+	case "":
+	case "*":
+		b.body = []protoreflect.FieldDescriptor{}
+	default:
+		field := method.Input().Fields().ByName(protoreflect.Name(body))
+		if field == nil || field.Kind() != protoreflect.MessageKind || field.IsList() || field.IsMap() {
+			return nil, fmt.Errorf("body %q is not a message field of %s", body, method.Input().FullName())
+		}
+		b.body = []protoreflect.FieldDescriptor{field}
+	}
+	if responseBody := rule.GetResponseBody(); responseBody != "" {
+		b.responseBody = method.Output().Fields().ByName(protoreflect.Name(responseBody))
+		if b.responseBody == nil {
+			return nil, fmt.Errorf("response body %q is not a field of %s", responseBody, method.Output().FullName())
+		}
+	}
+	if b.httpMethod == http.MethodGet {
+		rendered := method.Output()
+		if b.responseBody != nil {
+			rendered = nil
+			if b.responseBody.Kind() == protoreflect.MessageKind && !b.responseBody.IsList() && !b.responseBody.IsMap() {
+				rendered = b.responseBody.Message()
+			}
+		}
+		if rendered != nil {
+			b.version = versionPath(rendered)
+		}
+	} else if field := method.Input().Fields().ByName(versionsName); field != nil && field.Kind() == protoreflect.Int64Kind && field.IsList() {
+		b.precondition = field
+	}
+	if field := method.Input().Fields().ByName(callerName); field != nil && field.Kind() == protoreflect.Int64Kind && field.Cardinality() != protoreflect.Repeated {
+		b.caller = field
+	}
+	return b, nil
+}
+
+const (
+	versionName  = "version"
+	versionsName = "versions"
+	callerName   = "caller_id"
+)
+
+func isVersion(field protoreflect.FieldDescriptor) bool {
+	return field != nil && field.Kind() == protoreflect.Int64Kind && !field.IsList()
+}
+
+// versionPath finds the version of the resource in message: its own version
+// field, or that of its only message field that has one, as in
+// FindByIDResp.userResp.version.
+func versionPath(message protoreflect.MessageDescriptor) []protoreflect.FieldDescriptor {
+	if field := message.Fields().ByName(versionName); isVersion(field) {
+		return []protoreflect.FieldDescriptor{field}
+	}
+	var path []protoreflect.FieldDescriptor
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if field.Kind() != protoreflect.MessageKind || field.IsList() || field.IsMap() {
+			continue
+		}
+		if version := field.Message().Fields().ByName(versionName); isVersion(version) {
+			if path != nil {
+				return nil
+			}
+			path = []protoreflect.FieldDescriptor{field, version}
+		}
+	}
+	return path
+}
+
+// fieldPath resolves a dotted path such as user.id in message.
+func fieldPath(message protoreflect.MessageDescriptor, path string) ([]protoreflect.FieldDescriptor, error) {
+	fields := []protoreflect.FieldDescriptor{}
+	for _, name := range strings.Split(path, ".") {
+		if message == nil {
+			return nil, fmt.Errorf("%s is not a message field", fields[len(fields)-1].Name())
+		}
+		field := message.Fields().ByName(protoreflect.Name(name))
+		if field == nil {
+			return nil, fmt.Errorf("%s has no field %s", message.FullName(), name)
+		}
+		fields = append(fields, field)
+		message = field.Message()
+	}
+	return fields, nil
+}
+
+func (t *Transcoder) handle(b *binding) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		const scope = "transcoder#handle"
+		request := b.input.New()
+		if err := b.bind(ctx, request); err != nil {
+			logging.FromContext(ctx, t.logger).Error(
+				"Bad request params",
+				err,
+				slog.String("scope", scope),
+				slog.String("method", b.fullMethod),
+			)
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				ctx.Error(internal.ErrRequestTooLarge.SetError(err))
+				return
+			}
+			ctx.Error(&internal.ErrBadParams)
+			return
+		}
+		versions, err := b.preconditions(ctx)
+		if err != nil {
+			logging.FromContext(ctx, t.logger).Warn(
+				"Unusable If-Match",
+				slog.String("scope", scope),
+				slog.String("method", b.fullMethod),
+				slog.Any("error", err),
+			)
+			ctx.Error(err)
+			return
+		}
+		if len(versions) > 0 {
+			list := request.Mutable(b.precondition).List()
+			for _, version := range versions {
+				list.Append(protoreflect.ValueOfInt64(version))
+			}
+		}
+		response := b.output.New()
+		mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", ctx.GetString(util.RequestID)))
+		if err := t.conn.Invoke(mdCtx, b.fullMethod, request.Interface(), response.Interface()); err != nil {
+			logging.FromContext(ctx, t.logger).Error(
+				"Got error from service",
+				err,
+				slog.String("scope", scope),
+				slog.String("method", b.fullMethod),
+			)
+			ctx.Error(fmt.Errorf("%s: %w", scope, usecase.ErrClientService.SetError(err)))
+			return
+		}
+		logging.FromContext(ctx, t.logger).Info(
+			"Transcoded a request",
+			slog.String("scope", scope),
+			slog.String("method", b.fullMethod),
+		)
+		// The generated structs are encoded with encoding/json like the
+		// handlers do, so transcoded and hand-written routes render messages
+		// the same way.
+		var data interface{} = response.Interface()
+		if b.responseBody != nil {
+			data = response.Get(b.responseBody).Interface()
+			if message, ok := data.(protoreflect.Message); ok {
+				data = message.Interface()
+			}
+		}
+		if etag, ok := b.etag(response); ok {
+			ctx.Header("ETag", etag)
+			if noneMatch(ctx.GetHeader("If-None-Match"), etag) {
+				ctx.Status(http.StatusNotModified)
+				return
+			}
+		}
+		ctx.JSON(
+			http.StatusOK,
+			&handlerUtil.StandardResponse{
+				Code:    http.StatusOK,
+				Message: http.StatusText(http.StatusOK),
+				Data:    data,
+			},
+		)
+	}
+}
+
+// bind fills request from the body, then the query and then the path, so
+// that the path wins when a field is set twice. The caller is set last.
+func (b *binding) bind(ctx *gin.Context, request protoreflect.Message) error {
+	if b.body != nil {
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, internal.MaxJSONBody))
+		if err != nil {
+			return err
+		}
+		if len(body) > 0 {
+			target := request
+			if len(b.body) == 1 {
+				target = request.Mutable(b.body[0]).Message()
+			}
+			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, target.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	// Fields not bound by the path or a "*" body come from the query.
+	if b.body == nil || len(b.body) == 1 {
+		for key, values := range ctx.Request.URL.Query() {
+			if _, ok := b.vars[key]; ok {
+				continue
+			}
+			fields, err := fieldPath(request.Descriptor(), key)
+			if err != nil || (len(b.body) == 1 && fields[0] == b.body[0]) {
+				continue
+			}
+			if err := setField(request, fields, values); err != nil {
+				return fmt.Errorf("query param %s: %w", key, err)
+			}
+		}
+	}
+	for name, fields := range b.vars {
+		if err := setField(request, fields, []string{ctx.Param(name)}); err != nil {
+			return fmt.Errorf("path param %s: %w", name, err)
+		}
+	}
+	if b.caller != nil {
+		request.Clear(b.caller)
+		if userID, ok := ctx.Value(util.UserID).(int); ok {
+			request.Set(b.caller, protoreflect.ValueOfInt64(int64(userID)))
+		}
+	}
+	return nil
+}
+
+// preconditions returns the versions If-Match accepts, or none when it is
+// missing or holds *, which is left to the service since it fails on a
+// missing resource anyway. The service matches the list in one conditional
+// write.
+func (b *binding) preconditions(ctx *gin.Context) ([]int64, error) {
+	ifMatch := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if b.precondition == nil || ifMatch == "" {
+		return nil, nil
+	}
+	tags := strings.Split(ifMatch, ",")
+	var versions []int64
+	seen := map[int64]bool{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, nil
+		}
+		// Weak and unknown ETags never match a version under strong
+		// comparison.
+		if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+			continue
+		}
+		version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+		if err != nil || version <= 0 || seen[version] {
+			continue
+		}
+		seen[version] = true
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return nil, internal.ErrPreconditionFailed.SetError(fmt.Errorf("If-Match %s", ifMatch))
+	}
+	return versions, nil
+}
+
+// etag returns the strong ETag of the resource in response, if it has a
+// version. Services that do not fill the version in leave it 0.
+func (b *binding) etag(response protoreflect.Message) (string, bool) {
+	if b.version == nil {
+		return "", false
+	}
+	message := response
+	if b.responseBody != nil {
+		message = response.Get(b.responseBody).Message()
+	}
+	for _, field := range b.version[:len(b.version)-1] {
+		if !message.Has(field) {
+			return "", false
+		}
+		message = message.Get(field).Message()
+	}
+	version := message.Get(b.version[len(b.version)-1]).Int()
+	if version <= 0 {
+		return "", false
+	}
+	return `"` + strconv.FormatInt(version, 10) + `"`, true
+}
+
+// noneMatch reports whether an If-None-Match header lists etag, comparing
+// weakly as If-None-Match does.
+func noneMatch(ifNoneMatch string, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func setField(message protoreflect.Message, fields []protoreflect.FieldDescriptor, values []string) error {
+	for _, field := range fields[:len(fields)-1] {
+		message = message.Mutable(field).Message()
+	}
+	field := fields[len(fields)-1]
+	if field.IsMap() || field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind {
+		return fmt.Errorf("%s is not a scalar", field.Name())
+	}
+	if field.IsList() {
+		// Lists of numbers, bools and enums may also be given comma
+		// separated, as in ?ids=1,2,3. Strings keep their commas.
+		if field.Kind() != protoreflect.StringKind && field.Kind() != protoreflect.BytesKind {
+			var split []string
+			for _, raw := range values {
+				split = append(split, strings.Split(raw, ",")...)
+			}
+			values = split
+		}
+		list := message.Mutable(field).List()
+		for _, raw := range values {
+			value, err := parseScalar(field, raw)
+			if err != nil {
+				return err
+			}
+			list.Append(value)
+		}
+		return nil
+	}
+	value, err := parseScalar(field, values[len(values)-1])
+	if err != nil {
+		return err
+	}
+	message.Set(field, value)
+	return nil
+}
+
+func parseScalar(field protoreflect.FieldDescriptor, raw string) (protoreflect.Value, error) {
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(raw), nil
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(raw)
+		return protoreflect.ValueOfBool(v), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(raw, 10, 32)
+		return protoreflect.ValueOfInt32(int32(v)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(raw, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(v)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(raw, 10, 64)
+		return protoreflect.ValueOfUint64(v), err
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(raw, 32)
+		return protoreflect.ValueOfFloat32(float32(v)), err
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(raw, 64)
+		return protoreflect.ValueOfFloat64(v), err
+	case protoreflect.BytesKind:
+		v, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil {
+			v, err = base64.URLEncoding.DecodeString(raw)
+		}
+		return protoreflect.ValueOfBytes(v), err
+	case protoreflect.EnumKind:
+		if value := field.Enum().Values().ByName(protoreflect.Name(raw)); value != nil {
+			return protoreflect.ValueOfEnum(value.Number()), nil
+		}
+		v, err := strconv.ParseInt(raw, 10, 32)
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), err
+	default:
+		return protoreflect.Value{}, fmt.Errorf("%s has unsupported kind %s", field.Name(), field.Kind())
+	}
+}
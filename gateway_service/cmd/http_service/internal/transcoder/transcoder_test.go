@@ -0,0 +1,424 @@
+package transcoder
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gatewayservice/cmd/http_service/internal/middleware"
+	"gatewayservice/internal/metrics"
+	"gatewayservice/internal/util"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	messagePb "github.com/ideaspaper/social-media-proto/message"
+	userPb "github.com/ideaspaper/social-media-proto/user"
+)
+
+func TestNewBinding(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       protoreflect.FullName
+		rule         *annotations.HttpRule
+		path         string
+		body         bool
+		precondition bool
+		caller       bool
+		err          string
+	}{
+		{
+			name:   "path variable",
+			method: "user.UserService.FindByID",
+			rule:   get("/users/{id}"),
+			path:   "/users/:id",
+		},
+		{
+			name:   "no variables",
+			method: "user.UserService.FindByIDs",
+			rule:   get("/users"),
+			path:   "/users",
+		},
+		{
+			name:         "precondition and caller",
+			method:       "user.UserService.DeleteByID",
+			rule:         &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: "/users/{id}/softdelete"}},
+			path:         "/users/:id/softdelete",
+			precondition: true,
+			caller:       true,
+		},
+		{
+			name:   "whole body",
+			method: "message.MessageService.SendMessage",
+			rule:   &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: "/conversations/{conversation_id}/messages"}, Body: "*"},
+			path:   "/conversations/:conversation_id/messages",
+			body:   true,
+		},
+		{
+			name:   "custom method",
+			method: "user.UserService.FindByID",
+			rule:   &annotations.HttpRule{Pattern: &annotations.HttpRule_Custom{Custom: &annotations.CustomHttpPattern{Kind: "HEAD", Path: "/users/{id}"}}},
+			path:   "/users/:id",
+		},
+		{name: "no pattern", method: "user.UserService.FindByID", rule: &annotations.HttpRule{}, err: "no pattern"},
+		{name: "relative path", method: "user.UserService.FindByID", rule: get("users/{id}"), err: "not absolute"},
+		{name: "wildcard", method: "user.UserService.FindByIDs", rule: get("/users/*"), err: "not supported"},
+		{name: "custom verb", method: "user.UserService.FindByID", rule: get("/users/{id}:undo"), err: "not supported"},
+		{name: "variable pattern", method: "user.UserService.FindByID", rule: get("/users/{id=*}"), err: "not supported"},
+		{name: "unknown variable", method: "user.UserService.FindByID", rule: get("/users/{user_id}"), err: "has no field user_id"},
+		{name: "list variable", method: "user.UserService.FindByIDs", rule: get("/users/{ids}"), err: "not a scalar"},
+		{
+			name:   "body of a scalar field",
+			method: "user.UserService.FindByID",
+			rule:   &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: "/users"}, Body: "id"},
+			err:    "not a message field",
+		},
+		{
+			name:   "unknown response body",
+			method: "user.UserService.FindByID",
+			rule:   &annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: "/users/{id}"}, ResponseBody: "user"},
+			err:    "not a field",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := newBinding(serviceOf(t, tt.method), methodOf(t, tt.method), tt.rule)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want one containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("new binding: %v", err)
+			}
+			if b.path != tt.path {
+				t.Errorf("got path %q, want %q", b.path, tt.path)
+			}
+			if (b.body != nil) != tt.body {
+				t.Errorf("got body %v, want one: %v", b.body, tt.body)
+			}
+			if (b.precondition != nil) != tt.precondition {
+				t.Errorf("got precondition %v, want one: %v", b.precondition, tt.precondition)
+			}
+			if (b.caller != nil) != tt.caller {
+				t.Errorf("got caller %v, want one: %v", b.caller, tt.caller)
+			}
+		})
+	}
+}
+
+func TestBind(t *testing.T) {
+	sendMessage := &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: "/conversations/{conversation_id}/messages"}, Body: "*"}
+	tests := []struct {
+		name   string
+		method protoreflect.FullName
+		rule   *annotations.HttpRule
+		target string
+		body   string
+		userID int
+		want   proto.Message
+		status int
+	}{
+		{
+			name:   "path variable",
+			method: "user.UserService.FindByID",
+			rule:   get("/users/{id}"),
+			target: "/users/7",
+			want:   &userPb.FindByIDReq{Id: 7},
+		},
+		{
+			name:   "path wins over query",
+			method: "user.UserService.FindByID",
+			rule:   get("/users/{id}"),
+			target: "/users/7?id=9",
+			want:   &userPb.FindByIDReq{Id: 7},
+		},
+		{
+			name:   "repeated and comma separated query",
+			method: "user.UserService.FindByIDs",
+			rule:   get("/users"),
+			target: "/users?ids=1,2&ids=3",
+			want:   &userPb.FindByIDsReq{Ids: []int64{1, 2, 3}},
+		},
+		{
+			name:   "strings keep their commas",
+			method: "message.MessageService.SendMessage",
+			rule:   &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: "/conversations/{conversation_id}/messages"}},
+			target: "/conversations/4/messages?body=hi&media_ids=a,b",
+			want:   &messagePb.SendMessageReq{ConversationId: 4, Body: "hi", MediaIds: []string{"a,b"}},
+		},
+		{
+			name:   "whole body",
+			method: "message.MessageService.SendMessage",
+			rule:   sendMessage,
+			target: "/conversations/4/messages",
+			body:   `{"body":"hi","mediaIds":["a"],"unknown":1}`,
+			want:   &messagePb.SendMessageReq{ConversationId: 4, Body: "hi", MediaIds: []string{"a"}},
+		},
+		{
+			name:   "path wins over body",
+			method: "message.MessageService.SendMessage",
+			rule:   sendMessage,
+			target: "/conversations/4/messages",
+			body:   `{"conversationId":"9","body":"hi"}`,
+			want:   &messagePb.SendMessageReq{ConversationId: 4, Body: "hi"},
+		},
+		{
+			name:   "whole body ignores the query",
+			method: "message.MessageService.SendMessage",
+			rule:   sendMessage,
+			target: "/conversations/4/messages?body=query",
+			body:   `{"body":"hi"}`,
+			want:   &messagePb.SendMessageReq{ConversationId: 4, Body: "hi"},
+		},
+		{
+			name:   "caller from the access token",
+			method: "user.UserService.DeleteByID",
+			rule:   &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: "/users/{id}/softdelete"}},
+			target: "/users/7/softdelete?caller_id=99",
+			userID: 5,
+			want:   &userPb.DeleteByIDReq{Id: 7, CallerId: 5},
+		},
+		{
+			name:   "caller cleared without an access token",
+			method: "user.UserService.DeleteByID",
+			rule:   &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: "/users/{id}/softdelete"}},
+			target: "/users/7/softdelete?caller_id=99",
+			want:   &userPb.DeleteByIDReq{Id: 7},
+		},
+		{
+			name:   "unknown query params are ignored",
+			method: "user.UserService.FindByID",
+			rule:   get("/users/{id}"),
+			target: "/users/7?nope=1",
+			want:   &userPb.FindByIDReq{Id: 7},
+		},
+		{
+			name:   "bad path variable",
+			method: "user.UserService.FindByID",
+			rule:   get("/users/{id}"),
+			target: "/users/x",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "bad query param",
+			method: "user.UserService.FindByIDs",
+			rule:   get("/users"),
+			target: "/users?ids=1,x",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "bad body",
+			method: "message.MessageService.SendMessage",
+			rule:   sendMessage,
+			target: "/conversations/4/messages",
+			body:   `{"body":`,
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeConn{}
+			request := httptest.NewRequest(methodOfRule(tt.rule), tt.target, strings.NewReader(tt.body))
+			recorder := serve(t, conn, tt.method, tt.rule, tt.userID, request)
+			status := tt.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if recorder.Code != status {
+				t.Fatalf("got %d, want %d: %s", recorder.Code, status, recorder.Body)
+			}
+			if tt.want == nil {
+				if len(conn.requests) != 0 {
+					t.Fatalf("got %d calls, want none", len(conn.requests))
+				}
+				return
+			}
+			if len(conn.requests) != 1 {
+				t.Fatalf("got %d calls, want 1", len(conn.requests))
+			}
+			if !proto.Equal(conn.requests[0], tt.want) {
+				t.Errorf("got request %v, want %v", conn.requests[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	rule := &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: "/users/{id}/softdelete"}}
+	tests := []struct {
+		name     string
+		ifMatch  string
+		err      error
+		versions []int64
+		calls    int
+		status   int
+	}{
+		{name: "no If-Match", calls: 1, status: http.StatusOK},
+		{name: "one version", ifMatch: `"3"`, versions: []int64{3}, calls: 1, status: http.StatusOK},
+		{name: "list in one call", ifMatch: `"1", "3" ,"2"`, versions: []int64{1, 3, 2}, calls: 1, status: http.StatusOK},
+		{name: "duplicates dropped", ifMatch: `"3", "3"`, versions: []int64{3}, calls: 1, status: http.StatusOK},
+		{name: "unusable tags skipped", ifMatch: `W/"1", "x", "0", 2, "3"`, versions: []int64{3}, calls: 1, status: http.StatusOK},
+		{name: "any", ifMatch: `"1", *`, calls: 1, status: http.StatusOK},
+		{name: "only weak tags", ifMatch: `W/"3"`, status: http.StatusPreconditionFailed},
+		{name: "no usable tags", ifMatch: `"x", "-1"`, status: http.StatusPreconditionFailed},
+		{
+			name:     "none current",
+			ifMatch:  `"1", "2"`,
+			err:      status.Error(codes.Aborted, "User was changed since it was read"),
+			versions: []int64{1, 2},
+			calls:    1,
+			status:   http.StatusPreconditionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeConn{err: tt.err}
+			request := httptest.NewRequest(http.MethodPost, "/users/7/softdelete", nil)
+			if tt.ifMatch != "" {
+				request.Header.Set("If-Match", tt.ifMatch)
+			}
+			recorder := serve(t, conn, "user.UserService.DeleteByID", rule, 5, request)
+			if recorder.Code != tt.status {
+				t.Fatalf("got %d, want %d: %s", recorder.Code, tt.status, recorder.Body)
+			}
+			if len(conn.requests) != tt.calls {
+				t.Fatalf("got %d calls, want %d", len(conn.requests), tt.calls)
+			}
+			if tt.calls == 0 {
+				return
+			}
+			want := &userPb.DeleteByIDReq{Id: 7, CallerId: 5, Versions: tt.versions}
+			if !proto.Equal(conn.requests[0], want) {
+				t.Errorf("got request %v, want %v", conn.requests[0], want)
+			}
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     int64
+		ifNoneMatch string
+		etag        string
+		status      int
+	}{
+		{name: "version", version: 3, etag: `"3"`, status: http.StatusOK},
+		{name: "no version", status: http.StatusOK},
+		{name: "not modified", version: 3, ifNoneMatch: `"3"`, etag: `"3"`, status: http.StatusNotModified},
+		{name: "weak comparison", version: 3, ifNoneMatch: `"1", W/"3"`, etag: `"3"`, status: http.StatusNotModified},
+		{name: "any", version: 3, ifNoneMatch: `*`, etag: `"3"`, status: http.StatusNotModified},
+		{name: "modified", version: 4, ifNoneMatch: `"3"`, etag: `"4"`, status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeConn{reply: &userPb.FindByIDResp{UserResp: &userPb.UserResp{Id: 7, Version: tt.version}}}
+			request := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+			if tt.ifNoneMatch != "" {
+				request.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			recorder := serve(t, conn, "user.UserService.FindByID", get("/users/{id}"), 0, request)
+			if recorder.Code != tt.status {
+				t.Fatalf("got %d, want %d", recorder.Code, tt.status)
+			}
+			if etag := recorder.Header().Get("ETag"); etag != tt.etag {
+				t.Errorf("got ETag %q, want %q", etag, tt.etag)
+			}
+			if tt.status == http.StatusNotModified && recorder.Body.Len() != 0 {
+				t.Errorf("got body %q, want none", recorder.Body)
+			}
+		})
+	}
+}
+
+func get(path string) *annotations.HttpRule {
+	return &annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: path}}
+}
+
+func methodOfRule(rule *annotations.HttpRule) string {
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Post:
+		return http.MethodPost
+	case *annotations.HttpRule_Delete:
+		return http.MethodDelete
+	case *annotations.HttpRule_Custom:
+		return pattern.Custom.GetKind()
+	default:
+		return http.MethodGet
+	}
+}
+
+func methodOf(t *testing.T, name protoreflect.FullName) protoreflect.MethodDescriptor {
+	t.Helper()
+	descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+	if err != nil {
+		t.Fatalf("find %s: %v", name, err)
+	}
+	return descriptor.(protoreflect.MethodDescriptor)
+}
+
+func serviceOf(t *testing.T, name protoreflect.FullName) protoreflect.ServiceDescriptor {
+	t.Helper()
+	return methodOf(t, name).Parent().(protoreflect.ServiceDescriptor)
+}
+
+// serve sends request to a router with only the route of rule, signed in as
+// userID unless it is 0, and errors handled the way the gateway does.
+func serve(t *testing.T, conn *fakeConn, method protoreflect.FullName, rule *annotations.HttpRule, userID int, request *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	b, err := newBinding(serviceOf(t, method), methodOf(t, method), rule)
+	if err != nil {
+		t.Fatalf("new binding: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard))
+	m := middleware.New(logger, util.NewJwt("secret", "test", time.Hour), metrics.New(), nil, nil, middleware.CORSPolicy{}, nil, middleware.IdempotencyPolicy{})
+	r := gin.New()
+	r.Use(m.ErrorHandler)
+	signIn := func(ctx *gin.Context) {
+		if userID != 0 {
+			ctx.Set(util.UserID, userID)
+		}
+	}
+	r.Handle(b.httpMethod, b.path, signIn, New(logger, conn).handle(b))
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// fakeConn records the request of every call and answers with reply, or
+// with err when it is set.
+type fakeConn struct {
+	requests []proto.Message
+	reply    proto.Message
+	err      error
+}
+
+func (c *fakeConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	c.requests = append(c.requests, proto.Clone(args.(proto.Message)))
+	if c.err != nil {
+		return c.err
+	}
+	if c.reply != nil {
+		proto.Merge(reply.(proto.Message), c.reply)
+	}
+	return nil
+}
+
+func (c *fakeConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, errors.New("streams are not supported")
+}
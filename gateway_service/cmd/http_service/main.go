@@ -1,68 +1,312 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"gatewayservice/cmd/config"
+	"gatewayservice/cmd/http_service/internal/docs"
 	"gatewayservice/cmd/http_service/internal/handler"
 	"gatewayservice/cmd/http_service/internal/middleware"
 	"gatewayservice/cmd/http_service/internal/router"
+	"gatewayservice/cmd/http_service/internal/transcoder"
+	"gatewayservice/internal/blobstore"
+	"gatewayservice/internal/blobstore/local"
+	"gatewayservice/internal/blobstore/s3"
+	"gatewayservice/internal/grpcclient"
+	"gatewayservice/internal/idempotency"
+	idempotencyMemory "gatewayservice/internal/idempotency/memory"
+	idempotencyRedis "gatewayservice/internal/idempotency/redis"
+	"gatewayservice/internal/logging"
+	"gatewayservice/internal/metrics"
+	"gatewayservice/internal/mtls"
+	"gatewayservice/internal/pubsub/memory"
+	"gatewayservice/internal/ratelimit"
+	rateLimitMemory "gatewayservice/internal/ratelimit/memory"
+	"gatewayservice/internal/ratelimit/redis"
+	"gatewayservice/internal/tracing"
 	"gatewayservice/internal/usecase"
+	"gatewayservice/internal/util"
+	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"golang.org/x/exp/slog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	healthPb "google.golang.org/grpc/health/grpc_health_v1"
 
+	bookmarkPb "github.com/ideaspaper/social-media-proto/bookmark"
+	messagePb "github.com/ideaspaper/social-media-proto/message"
+	moderationPb "github.com/ideaspaper/social-media-proto/moderation"
 	userPb "github.com/ideaspaper/social-media-proto/user"
 )
 
-func initLogger() *slog.Logger {
-	logStringLevel := map[string]slog.Level{
-		"DEBUG": slog.LevelDebug,
-		"INFO":  slog.LevelInfo,
-		"WARN":  slog.LevelWarn,
-		"ERROR": slog.LevelError,
-	}
-	opts := slog.HandlerOptions{
-		Level:     logStringLevel[os.Getenv("LOG_LEVEL")],
-		AddSource: true,
-	}
-	textHandler := opts.NewTextHandler(os.Stdout).WithAttrs(
-		[]slog.Attr{
-			slog.String("app-name", os.Getenv("APP_NAME")),
-			slog.String("app-version", os.Getenv("APP_VERSION")),
+func initLogger(cfg *config.Config) *slog.Logger {
+	logger := logging.New(os.Stdout, logging.Config{
+		Format:           cfg.LogFormat,
+		Level:            cfg.Level(),
+		AppName:          cfg.AppName,
+		AppVersion:       cfg.AppVersion,
+		SampleInitial:    cfg.LogSampleInitial,
+		SampleThereafter: cfg.LogSampleThereafter,
+	})
+	slog.SetDefault(logger)
+	return logger
+}
+
+func initBlobStore(mediaConfig config.MediaConfig) (blobstore.IBlobStore, error) {
+	if mediaConfig.Storage == "s3" {
+		return s3.NewBlobStore(
+			s3.Config{
+				Endpoint:  mediaConfig.S3.Endpoint,
+				Region:    mediaConfig.S3.Region,
+				Bucket:    mediaConfig.S3.Bucket,
+				AccessKey: mediaConfig.S3.AccessKey,
+				SecretKey: mediaConfig.S3.SecretKey,
+			},
+			&http.Client{Timeout: 30 * time.Second},
+		), nil
+	}
+	return local.NewBlobStore(mediaConfig.LocalPath)
+}
+
+func initRateLimitStore(rateLimitConfig config.RateLimitConfig) ratelimit.IStore {
+	if !rateLimitConfig.Enabled {
+		return nil
+	}
+	if rateLimitConfig.Store == "redis" {
+		return redis.NewStore(redis.Config{
+			Addr:     rateLimitConfig.Redis.Addr,
+			Password: rateLimitConfig.Redis.Password,
+			DB:       rateLimitConfig.Redis.DB,
+		})
+	}
+	return rateLimitMemory.NewStore()
+}
+
+func initIdempotencyStore(idempotencyConfig config.IdempotencyConfig) idempotency.IStore {
+	if !idempotencyConfig.Enabled {
+		return nil
+	}
+	if idempotencyConfig.Store == "redis" {
+		return idempotencyRedis.NewStore(idempotencyRedis.Config{
+			Addr:     idempotencyConfig.Redis.Addr,
+			Password: idempotencyConfig.Redis.Password,
+			DB:       idempotencyConfig.Redis.DB,
+		})
+	}
+	return idempotencyMemory.NewStore()
+}
+
+func initVersions(apiConfig config.APIConfig) router.Versions {
+	versions := router.Versions{
+		V1: middleware.DeprecationPolicy{
+			Deprecation: apiConfig.V1.Deprecation,
+			Sunset:      apiConfig.V1.Sunset,
 		},
-	)
-	return slog.New(textHandler)
+	}
+	if apiConfig.Unversioned.Enabled {
+		versions.Unversioned = &middleware.DeprecationPolicy{
+			Deprecation: apiConfig.Unversioned.Deprecation,
+			Sunset:      apiConfig.Unversioned.Sunset,
+		}
+	}
+	return versions
+}
+
+// printOpenAPI writes the spec to stdout, failing when it has drifted from
+// the routes. It runs without config so the image build can call it.
+func printOpenAPI() error {
+	gin.SetMode(gin.ReleaseMode)
+	spec, err := docs.Spec(os.Getenv("APP_VERSION"))
+	if err != nil {
+		return err
+	}
+	router, err := router.New(&handler.Handler{}, &middleware.Middleware{}, transcoder.New(nil, nil), http.NotFoundHandler(), spec, router.Versions{Unversioned: &middleware.DeprecationPolicy{}})
+	if err != nil {
+		return err
+	}
+	if err := docs.Check(router.Routes()); err != nil {
+		return fmt.Errorf("openapi spec is out of date: %w", err)
+	}
+	_, err = os.Stdout.Write(spec)
+	return err
 }
 
 func main() {
-	const appPort = "8081"
+	if len(os.Args) > 1 && os.Args[1] == "openapi" {
+		if err := printOpenAPI(); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalln(err)
+	}
 	gin.SetMode(gin.ReleaseMode)
-	logger := initLogger()
+	logger := initLogger(cfg)
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName:    cfg.AppName,
+		ServiceVersion: cfg.AppVersion,
+		Exporter:       cfg.Tracing.Exporter,
+		Endpoint:       cfg.Tracing.Endpoint,
+		Insecure:       cfg.Tracing.Insecure,
+	})
+	if err != nil {
+		logger.Error("Failed to initialize tracing", err)
+		os.Exit(1)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	jwt := util.NewJwt(cfg.JWT.Secret, cfg.AppName, cfg.JWT.ExpiresIn)
+	creds := insecure.NewCredentials()
+	if cfg.TLS.Enabled() {
+		reloader, err := mtls.NewReloader(logger, mtls.Files{
+			CertFile: cfg.TLS.CertFile,
+			KeyFile:  cfg.TLS.KeyFile,
+			CAFile:   cfg.TLS.CAFile,
+		})
+		if err != nil {
+			logger.Error("Failed to load TLS certificates", err)
+			os.Exit(1)
+		}
+		go reloader.Watch(ctx)
+		creds = credentials.NewTLS(reloader.ClientConfig(cfg.UserService.TLSServerName))
+	}
+	breaker := grpcclient.NewBreaker(logger, cfg.UserService.BreakerFailures, cfg.UserService.BreakerCooldown)
+	dialOptions := grpcclient.DialOptions(grpcclient.Config{
+		Timeout:       cfg.UserService.Timeout,
+		AuthTimeout:   cfg.UserService.AuthTimeout,
+		RetryAttempts: cfg.UserService.RetryAttempts,
+	})
 	userServiceConn, err := grpc.Dial(
-		fmt.Sprintf(
-			"%s:%s",
-			os.Getenv("USER_SERVICE_HOST"),
-			os.Getenv("USER_SERVICE_PORT"),
-		),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		net.JoinHostPort(cfg.UserService.Host, strconv.Itoa(cfg.UserService.Port)),
+		append(
+			dialOptions,
+			grpc.WithTransportCredentials(creds),
+			grpc.WithChainUnaryInterceptor(
+				otelgrpc.UnaryClientInterceptor(otelgrpc.WithInterceptorFilter(filters.Not(filters.HealthCheck()))),
+				breaker.Intercept,
+				usecase.UserMetadataInterceptor,
+			),
+		)...,
 	)
 	if err != nil {
 		logger.Error("Connecting to gRPC service failed", err)
 		os.Exit(1)
 	}
-	defer userServiceConn.Close()
 	validate := validator.New()
 	userService := userPb.NewUserServiceClient(userServiceConn)
-	userServiceUsecase := usecase.NewUserServiceUsecase(logger, validate, userService)
-	handler := handler.New(logger, userServiceUsecase)
-	middleware := middleware.New(logger)
-	router := router.New(handler, middleware)
-	logger.Info("Server listening", slog.String("port", appPort))
-	if err := router.Run(fmt.Sprintf(":%s", appPort)); err != nil {
+	userServiceUsecase := usecase.NewUserServiceUsecase(logger, validate, userService, jwt)
+	pubSub := memory.NewPubSub(logger, 256, 5*time.Minute, 64)
+	realtimeUsecase := usecase.NewRealtimeUsecase(logger, pubSub)
+	blobStore, err := initBlobStore(cfg.Media)
+	if err != nil {
+		logger.Error("Initializing blob store failed", err)
+		os.Exit(1)
+	}
+	mediaUsecase := usecase.NewMediaUsecase(logger, blobStore)
+	messageService := messagePb.NewMessageServiceClient(userServiceConn)
+	messageServiceUsecase := usecase.NewMessageServiceUsecase(logger, validate, messageService, pubSub, mediaUsecase)
+	bookmarkService := bookmarkPb.NewBookmarkServiceClient(userServiceConn)
+	bookmarkServiceUsecase := usecase.NewBookmarkServiceUsecase(logger, validate, bookmarkService)
+	moderationService := moderationPb.NewModerationServiceClient(userServiceConn)
+	moderationServiceUsecase := usecase.NewModerationServiceUsecase(logger, validate, moderationService, pubSub)
+	healthUsecase := usecase.NewHealthUsecase(logger, userServiceConn, healthPb.NewHealthClient(userServiceConn))
+	rateLimitStore := initRateLimitStore(cfg.RateLimit)
+	rateLimits := map[string]ratelimit.Limit{
+		ratelimit.PolicyAuth:    {Requests: cfg.RateLimit.Auth.Requests, Per: cfg.RateLimit.Auth.Per},
+		ratelimit.PolicyMedia:   {Requests: cfg.RateLimit.Media.Requests, Per: cfg.RateLimit.Media.Per},
+		ratelimit.PolicyDefault: {Requests: cfg.RateLimit.Default.Requests, Per: cfg.RateLimit.Default.Per},
+	}
+	graphqlUsecase, err := usecase.NewGraphQLUsecase(logger, validate, userServiceUsecase, rateLimitStore, rateLimits[ratelimit.PolicyAuth], cfg.GraphQL.MaxDepth, cfg.GraphQL.MaxComplexity)
+	if err != nil {
+		logger.Error("Failed to build GraphQL schema", err)
+		os.Exit(1)
+	}
+	metrics := metrics.New()
+	corsPolicy := middleware.CORSPolicy{
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		AllowedHeaders:   cfg.CORS.AllowedHeaders,
+		MaxAge:           cfg.CORS.MaxAge,
+	}
+	idempotencyPolicy := middleware.IdempotencyPolicy{
+		TTL:     cfg.Idempotency.TTL,
+		LockTTL: cfg.Idempotency.LockTTL,
+	}
+	handler := handler.New(logger, userServiceUsecase, realtimeUsecase, messageServiceUsecase, mediaUsecase, bookmarkServiceUsecase, moderationServiceUsecase, healthUsecase, graphqlUsecase, corsPolicy.Allows)
+	middleware := middleware.New(logger, jwt, metrics, rateLimitStore, rateLimits, corsPolicy, initIdempotencyStore(cfg.Idempotency), idempotencyPolicy)
+	spec, err := docs.Spec(cfg.AppVersion)
+	if err != nil {
+		logger.Error("Failed to generate OpenAPI spec", err)
+		os.Exit(1)
+	}
+	transcoder := transcoder.New(logger, userServiceConn)
+	router, err := router.New(handler, middleware, transcoder, metrics.Handler(), spec, initVersions(cfg.API))
+	if err != nil {
+		logger.Error("Failed to set up routes", err)
+		os.Exit(1)
+	}
+	if err := docs.Check(router.Routes()); err != nil {
+		logger.Warn("OpenAPI spec is out of date", slog.String("problems", err.Error()))
+	}
+	// Gin trusts X-Forwarded-For from anyone by default, which would let
+	// clients pick the IP they are rate limited by.
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.Error("Invalid trusted proxies", err)
+		os.Exit(1)
+	}
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.Port),
+		Handler: router,
+	}
+	// Shutdown does not wait for SSE and WebSocket streams on its own; closing
+	// the pub/sub ends their subscriptions so the handlers return.
+	srv.RegisterOnShutdown(func() {
+		pubSub.Close()
+	})
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("Server listening", slog.Int("port", cfg.Port))
+		serveErr <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-serveErr:
 		logger.Error("Failed to serve", err)
 		os.Exit(1)
+	case <-ctx.Done():
+	}
+	stop()
+	logger.Info("Shutting down", slog.Duration("timeout", cfg.ShutdownTimeout))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Failed to drain connections", err)
+		srv.Close()
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("Failed to serve", err)
+	}
+	if err := userServiceConn.Close(); err != nil {
+		logger.Error("Failed to close user service connection", err)
+	}
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Error("Failed to flush traces", err)
 	}
+	logger.Info("Server stopped")
 }
@@ -0,0 +1,13 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+)
+
+type IBlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
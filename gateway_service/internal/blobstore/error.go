@@ -0,0 +1,51 @@
+package blobstore
+
+import "fmt"
+
+type errKind int
+
+var (
+	ErrBlobNotFound = Error{kind: blobNotFound}
+	ErrBadKey       = Error{kind: badKey}
+	ErrUnknown      = Error{kind: unknown}
+)
+
+const (
+	_ errKind = iota
+	blobNotFound
+	badKey
+	unknown
+)
+
+type Error struct {
+	kind errKind
+	err  error
+}
+
+func (e *Error) Error() string {
+	switch e.kind {
+	case blobNotFound:
+		return fmt.Sprintf("Blob not found %v", e.err)
+	case badKey:
+		return fmt.Sprintf("Bad key %v", e.err)
+	default:
+		return fmt.Sprintf("Unknown error %v", e.err)
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+func (e *Error) Is(err error) bool {
+	target, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+	return target.kind == e.kind
+}
+
+func (e *Error) SetError(err error) *Error {
+	e.err = err
+	return e
+}
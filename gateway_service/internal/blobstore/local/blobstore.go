@@ -0,0 +1,107 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gatewayservice/internal/blobstore"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type blobStore struct {
+	root string
+}
+
+// NewBlobStore stores every blob as a file under root, using the key as
+// its relative path.
+func NewBlobStore(root string) (blobstore.IBlobStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &blobStore{
+		root: root,
+	}, nil
+}
+
+func (bs blobStore) path(key string) (string, error) {
+	cleanKey := filepath.Clean("/" + key)
+	if cleanKey == "/" || strings.Contains(key, "..") {
+		return "", blobstore.ErrBadKey.SetError(errors.New(key))
+	}
+	return filepath.Join(bs.root, cleanKey), nil
+}
+
+// Put writes to a temporary file first so readers never see a partial blob.
+func (bs blobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	const scope = "localBlobStore#Put"
+	path, err := bs.path(key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", scope, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("%s: %w", scope, blobstore.ErrUnknown.SetError(err))
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("%s: %w", scope, blobstore.ErrUnknown.SetError(err))
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("%s: %w", scope, blobstore.ErrUnknown.SetError(err))
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("%s: %w", scope, blobstore.ErrUnknown.SetError(err))
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("%s: %w", scope, blobstore.ErrUnknown.SetError(err))
+	}
+	return nil
+}
+
+func (bs blobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	const scope = "localBlobStore#Get"
+	path, err := bs.path(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s: %w", scope, blobstore.ErrBlobNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, blobstore.ErrUnknown.SetError(err))
+	}
+	return f, nil
+}
+
+func (bs blobStore) Exists(ctx context.Context, key string) (bool, error) {
+	const scope = "localBlobStore#Exists"
+	path, err := bs.path(key)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", scope, err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s: %w", scope, blobstore.ErrUnknown.SetError(err))
+	}
+	return true, nil
+}
+
+func (bs blobStore) Delete(ctx context.Context, key string) error {
+	const scope = "localBlobStore#Delete"
+	path, err := bs.path(key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", scope, err)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s: %w", scope, blobstore.ErrUnknown.SetError(err))
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package s3
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gatewayservice/internal/blobstore"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+type Config struct {
+	// Endpoint is the base URL of any S3-compatible service, e.g.
+	// https://s3.eu-west-1.amazonaws.com or http://minio:9000.
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+type blobStore struct {
+	config Config
+	client *http.Client
+}
+
+// NewBlobStore talks to the bucket with path-style requests signed with
+// AWS Signature Version 4, which MinIO, Ceph and R2 also accept.
+func NewBlobStore(config Config, client *http.Client) blobstore.IBlobStore {
+	return &blobStore{
+		config: config,
+		client: client,
+	}
+}
+
+func (bs blobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	const scope = "s3BlobStore#Put"
+	req, err := bs.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", scope, blobstore.ErrUnknown.SetError(err))
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	res, err := bs.do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", scope, err)
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (bs blobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	const scope = "s3BlobStore#Get"
+	req, err := bs.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, blobstore.ErrUnknown.SetError(err))
+	}
+	res, err := bs.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	return res.Body, nil
+}
+
+func (bs blobStore) Exists(ctx context.Context, key string) (bool, error) {
+	const scope = "s3BlobStore#Exists"
+	req, err := bs.newRequest(ctx, http.MethodHead, key, nil)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", scope, blobstore.ErrUnknown.SetError(err))
+	}
+	res, err := bs.do(req)
+	if err != nil {
+		if errors.Is(err, &blobstore.ErrBlobNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s: %w", scope, err)
+	}
+	res.Body.Close()
+	return true, nil
+}
+
+func (bs blobStore) Delete(ctx context.Context, key string) error {
+	const scope = "s3BlobStore#Delete"
+	req, err := bs.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", scope, blobstore.ErrUnknown.SetError(err))
+	}
+	res, err := bs.do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", scope, err)
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (bs blobStore) newRequest(ctx context.Context, method string, key string, body io.Reader) (*http.Request, error) {
+	objectURL, err := url.Parse(strings.TrimSuffix(bs.config.Endpoint, "/") + "/" + bs.config.Bucket + "/" + strings.TrimPrefix(key, "/"))
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequestWithContext(ctx, method, objectURL.String(), body)
+}
+
+func (bs blobStore) do(req *http.Request) (*http.Response, error) {
+	bs.sign(req, time.Now().UTC())
+	res, err := bs.client.Do(req)
+	if err != nil {
+		return nil, blobstore.ErrUnknown.SetError(err)
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, blobstore.ErrBlobNotFound.SetError(fmt.Errorf("%s %s", req.Method, req.URL.Path))
+	}
+	if res.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		res.Body.Close()
+		return nil, blobstore.ErrUnknown.SetError(fmt.Errorf("%s %s: %s %s", req.Method, req.URL.Path, res.Status, message))
+	}
+	return res, nil
+}
+
+// sign adds the Signature Version 4 headers. The payload is left unsigned,
+// so bodies can be streamed without hashing them first.
+func (bs blobStore) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + unsignedPayload + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+	credentialScope := date + "/" + bs.config.Region + "/s3/aws4_request"
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		credentialScope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+	signingKey := hmacSHA256([]byte("AWS4"+bs.config.SecretKey), date)
+	signingKey = hmacSHA256(signingKey, bs.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	req.Header.Set(
+		"Authorization",
+		fmt.Sprintf(
+			"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			bs.config.AccessKey,
+			credentialScope,
+			signedHeaders,
+			signature,
+		),
+	)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
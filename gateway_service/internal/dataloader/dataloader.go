@@ -0,0 +1,81 @@
+package dataloader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BatchFunc loads keys in one go and returns a value and an error per key,
+// both in the order of keys.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) ([]V, []error)
+
+type result[V any] struct {
+	value V
+	err   error
+}
+
+// Loader collects the keys asked for through Load until one of the returned
+// thunks is called, then loads every pending key in batches of at most
+// maxBatch. Results are kept for the lifetime of the loader, so a loader
+// belongs to a single request.
+type Loader[K comparable, V any] struct {
+	batch    BatchFunc[K, V]
+	maxBatch int
+
+	mu      sync.Mutex
+	pending []K
+	results map[K]*result[V]
+}
+
+func New[K comparable, V any](batch BatchFunc[K, V], maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		batch:    batch,
+		maxBatch: maxBatch,
+		results:  map[K]*result[V]{},
+	}
+}
+
+// Load queues key and returns a thunk that blocks until its value is loaded.
+// Call Load for every key the caller will need before calling any thunk, so
+// the keys end up in the same batch.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) func() (V, error) {
+	l.mu.Lock()
+	if _, ok := l.results[key]; !ok {
+		l.results[key] = nil
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.results[key] == nil {
+			l.dispatch(ctx)
+		}
+		r := l.results[key]
+		return r.value, r.err
+	}
+}
+
+// dispatch must be called with mu held.
+func (l *Loader[K, V]) dispatch(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+	for len(keys) > 0 {
+		n := len(keys)
+		if n > l.maxBatch {
+			n = l.maxBatch
+		}
+		values, errs := l.batch(ctx, keys[:n])
+		for i, key := range keys[:n] {
+			r := &result[V]{}
+			if len(values) != n || len(errs) != n {
+				r.err = fmt.Errorf("batch returned %d values and %d errors for %d keys", len(values), len(errs), n)
+			} else {
+				r.value, r.err = values[i], errs[i]
+			}
+			l.results[key] = r
+		}
+		keys = keys[n:]
+	}
+}
@@ -0,0 +1,117 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	errOdd := errors.New("odd")
+	tests := []struct {
+		name     string
+		rounds   [][]int
+		maxBatch int
+		// short makes the batch return one value too few.
+		short   bool
+		batches [][]int
+		errs    map[int]error
+	}{
+		{name: "one batch", rounds: [][]int{{1, 2, 3}}, maxBatch: 10, batches: [][]int{{1, 2, 3}}},
+		{name: "duplicates loaded once", rounds: [][]int{{1, 2, 1, 2}}, maxBatch: 10, batches: [][]int{{1, 2}}},
+		{name: "split by max batch", rounds: [][]int{{1, 2, 3, 4, 5}}, maxBatch: 2, batches: [][]int{{1, 2}, {3, 4}, {5}}},
+		{
+			name:     "later rounds only load new keys",
+			rounds:   [][]int{{1, 2}, {2, 3}, {1}},
+			maxBatch: 10,
+			batches:  [][]int{{1, 2}, {3}},
+		},
+		{
+			name:     "errors per key",
+			rounds:   [][]int{{1, 2, 3}},
+			maxBatch: 10,
+			batches:  [][]int{{1, 2, 3}},
+			errs:     map[int]error{1: errOdd, 3: errOdd},
+		},
+		{name: "short batch", rounds: [][]int{{1, 2}}, maxBatch: 10, short: true, batches: [][]int{{1, 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var batches [][]int
+			batch := func(ctx context.Context, keys []int) ([]string, []error) {
+				batches = append(batches, append([]int(nil), keys...))
+				values := make([]string, len(keys))
+				errs := make([]error, len(keys))
+				for i, key := range keys {
+					values[i] = fmt.Sprint(key)
+					errs[i] = tt.errs[key]
+				}
+				if tt.short {
+					return values[1:], errs[1:]
+				}
+				return values, errs
+			}
+			loader := New[int, string](batch, tt.maxBatch)
+			ctx := context.Background()
+			for _, round := range tt.rounds {
+				thunks := make([]func() (string, error), len(round))
+				for i, key := range round {
+					thunks[i] = loader.Load(ctx, key)
+				}
+				for i, thunk := range thunks {
+					key := round[i]
+					value, err := thunk()
+					if tt.short {
+						if err == nil {
+							t.Errorf("key %d: got no error from a short batch", key)
+						}
+						continue
+					}
+					if err != tt.errs[key] {
+						t.Errorf("key %d: got error %v, want %v", key, err, tt.errs[key])
+					}
+					if value != fmt.Sprint(key) {
+						t.Errorf("key %d: got value %q", key, value)
+					}
+				}
+			}
+			if !reflect.DeepEqual(batches, tt.batches) {
+				t.Errorf("got batches %v, want %v", batches, tt.batches)
+			}
+		})
+	}
+}
+
+func TestLoadConcurrentThunks(t *testing.T) {
+	var calls int
+	batch := func(ctx context.Context, keys []int) ([]int, []error) {
+		calls++
+		values := make([]int, len(keys))
+		for i, key := range keys {
+			values[i] = key * 10
+		}
+		return values, make([]error, len(keys))
+	}
+	loader := New[int, int](batch, 100)
+	thunks := make([]func() (int, error), 20)
+	for i := range thunks {
+		thunks[i] = loader.Load(context.Background(), i)
+	}
+	var wg sync.WaitGroup
+	for i, thunk := range thunks {
+		wg.Add(1)
+		go func(key int, thunk func() (int, error)) {
+			defer wg.Done()
+			if value, err := thunk(); err != nil || value != key*10 {
+				t.Errorf("key %d: got %d, %v", key, value, err)
+			}
+		}(i, thunk)
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("got %d batches, want 1", calls)
+	}
+}
@@ -0,0 +1,31 @@
+package req
+
+import "strings"
+
+type ConversationDto struct {
+	ParticipantIDs []int   `json:"participant_ids" validate:"required,min=1,max=9,unique,dive,gt=0"`
+	Title          *string `json:"title" validate:"omitempty,max=100"`
+}
+
+func (cd ConversationDto) ErrorMessages(field, tag string) string {
+	if strings.HasPrefix(field, "ParticipantIDs[") {
+		return "participant_ids must be positive"
+	}
+	switch field {
+	case "ParticipantIDs":
+		switch tag {
+		case "required", "min":
+			return "participant_ids requires at least 1 participant"
+		case "max":
+			return "participant_ids maximum length is 9"
+		case "unique":
+			return "participant_ids must be unique"
+		}
+	case "Title":
+		switch tag {
+		case "max":
+			return "title maximum length is 100"
+		}
+	}
+	return ""
+}
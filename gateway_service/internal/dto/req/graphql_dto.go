@@ -0,0 +1,18 @@
+package req
+
+type GraphQLDto struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (gd GraphQLDto) ErrorMessages(field, tag string) string {
+	switch field {
+	case "Query":
+		switch tag {
+		case "required":
+			return "query is required"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,16 @@
+package req
+
+type MarkReadDto struct {
+	MessageID int `json:"message_id" validate:"min=0"`
+}
+
+func (mrd MarkReadDto) ErrorMessages(field, tag string) string {
+	switch field {
+	case "MessageID":
+		switch tag {
+		case "min":
+			return "message_id must not be negative"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,6 @@
+package resp
+
+type HealthDto struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
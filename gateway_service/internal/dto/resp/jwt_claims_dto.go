@@ -5,5 +5,6 @@ import "github.com/golang-jwt/jwt/v5"
 type JwtClaimsDto struct {
 	ID    int    `json:"id"`
 	Email string `json:"email"`
+	Role  string `json:"role"`
 	jwt.RegisteredClaims
 }
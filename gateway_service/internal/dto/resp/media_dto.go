@@ -0,0 +1,12 @@
+package resp
+
+type MediaDto struct {
+	ID           string  `json:"id"`
+	ContentType  string  `json:"content_type"`
+	Size         int64   `json:"size"`
+	Width        int     `json:"width"`
+	Height       int     `json:"height"`
+	URL          string  `json:"url"`
+	ThumbnailURL *string `json:"thumbnail_url,omitempty"`
+	CreatedAt    string  `json:"created_at"`
+}
@@ -4,4 +4,7 @@ type StandardDto struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data"`
+	// RequestID is only set on errors, so users can quote it when reporting
+	// a problem.
+	RequestID string `json:"request_id,omitempty"`
 }
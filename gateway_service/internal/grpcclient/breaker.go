@@ -0,0 +1,111 @@
+package grpcclient
+
+import (
+	"context"
+	"gatewayservice/internal/logging"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// Breaker fails calls fast with codes.Unavailable once failures calls in a
+// row could not reach the service or ran out of time. After cooldown it lets
+// a single call through, and closes again if that call gets an answer.
+type Breaker struct {
+	logger   *slog.Logger
+	failures int
+	cooldown time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failed   int
+	openedAt time.Time
+}
+
+func NewBreaker(logger *slog.Logger, failures int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		logger:   logger,
+		failures: failures,
+		cooldown: cooldown,
+	}
+}
+
+func (b *Breaker) Intercept(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	// Readiness probes have to see the real state of the service.
+	if strings.HasPrefix(method, "/grpc.health.v1.Health/") {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	if !b.allow() {
+		return status.Error(codes.Unavailable, "circuit breaker is open")
+	}
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	b.record(ctx, err)
+	return err
+}
+
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerClosed:
+		return true
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *Breaker) record(ctx context.Context, err error) {
+	const scope = "breaker#record"
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		b.failed++
+		if b.state == breakerHalfOpen || b.failed >= b.failures {
+			if b.state != breakerOpen {
+				logging.FromContext(ctx, b.logger).Warn(
+					"Circuit breaker opened",
+					slog.String("scope", scope),
+					slog.Int("failures", b.failed),
+					slog.Any("error", err),
+				)
+			}
+			b.state = breakerOpen
+			b.openedAt = time.Now()
+		}
+	case codes.Canceled:
+		// The caller gave up, which says nothing about the service. A
+		// cancelled probe leaves the next call to probe again.
+		if b.state == breakerHalfOpen {
+			b.state = breakerOpen
+		}
+	default:
+		if b.state != breakerClosed {
+			logging.FromContext(ctx, b.logger).Info(
+				"Circuit breaker closed",
+				slog.String("scope", scope),
+			)
+		}
+		b.state = breakerClosed
+		b.failed = 0
+	}
+}
@@ -0,0 +1,130 @@
+package grpcclient
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/user.UserService/FindByID"
+
+// call is one call through the breaker: what the service answers, and what
+// the caller gets. A call the breaker rejects never reaches the service.
+type call struct {
+	method string
+	answer codes.Code
+	want   codes.Code
+	// rejected is set when the breaker should not let the call through.
+	rejected bool
+	// cooledDown moves the breaker past its cooldown before the call.
+	cooledDown bool
+}
+
+func TestBreaker(t *testing.T) {
+	failed := func(code codes.Code) call { return call{answer: code, want: code} }
+	answered := call{answer: codes.OK, want: codes.OK}
+	rejected := call{want: codes.Unavailable, rejected: true}
+	probe := func(c call) call {
+		c.cooledDown = true
+		return c
+	}
+	tests := []struct {
+		name  string
+		calls []call
+	}{
+		{
+			name:  "opens after failures in a row",
+			calls: []call{failed(codes.Unavailable), failed(codes.DeadlineExceeded), rejected, rejected},
+		},
+		{
+			name:  "an answer resets the count",
+			calls: []call{failed(codes.Unavailable), failed(codes.NotFound), failed(codes.Unavailable), answered},
+		},
+		{
+			name:  "errors from the service are answers",
+			calls: []call{failed(codes.Internal), failed(codes.PermissionDenied), failed(codes.Aborted), answered},
+		},
+		{
+			name:  "stays open during the cooldown",
+			calls: []call{failed(codes.Unavailable), failed(codes.Unavailable), rejected},
+		},
+		{
+			name:  "an answered probe closes",
+			calls: []call{failed(codes.Unavailable), failed(codes.Unavailable), probe(answered), answered, failed(codes.Unavailable), answered},
+		},
+		{
+			name:  "a failed probe opens again",
+			calls: []call{failed(codes.Unavailable), failed(codes.Unavailable), probe(failed(codes.DeadlineExceeded)), rejected},
+		},
+		{
+			name:  "a cancelled probe leaves the next call to probe",
+			calls: []call{failed(codes.Unavailable), failed(codes.Unavailable), probe(failed(codes.Canceled)), answered, answered},
+		},
+		{
+			name:  "cancelled calls do not break the row",
+			calls: []call{failed(codes.Unavailable), failed(codes.Canceled), failed(codes.Unavailable), rejected},
+		},
+		{
+			name: "health checks bypass an open breaker",
+			calls: []call{
+				failed(codes.Unavailable), failed(codes.Unavailable),
+				{method: "/grpc.health.v1.Health/Check", answer: codes.OK, want: codes.OK},
+				rejected,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBreaker(slog.New(slog.NewTextHandler(io.Discard)), 2, time.Minute)
+			for i, c := range tt.calls {
+				if c.cooledDown {
+					b.mu.Lock()
+					b.openedAt = b.openedAt.Add(-time.Minute)
+					b.mu.Unlock()
+				}
+				method := c.method
+				if method == "" {
+					method = testMethod
+				}
+				reached := false
+				err := b.Intercept(context.Background(), method, nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+					reached = true
+					return status.Error(c.answer, "answer")
+				})
+				if reached == c.rejected {
+					t.Fatalf("call %d reached the service: %v, want %v", i, reached, !c.rejected)
+				}
+				if code := status.Code(err); code != c.want {
+					t.Fatalf("call %d got %v, want %v", i, code, c.want)
+				}
+			}
+		})
+	}
+}
+
+// While the probe is in flight, other calls are still failed fast.
+func TestBreakerSingleProbe(t *testing.T) {
+	b := NewBreaker(slog.New(slog.NewTextHandler(io.Discard)), 1, time.Minute)
+	unavailable := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "down")
+	}
+	b.Intercept(context.Background(), testMethod, nil, nil, nil, unavailable)
+	b.openedAt = b.openedAt.Add(-time.Minute)
+	var concurrent error
+	err := b.Intercept(context.Background(), testMethod, nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		concurrent = b.Intercept(ctx, testMethod, nil, nil, nil, unavailable)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("probe got %v", err)
+	}
+	if status.Code(concurrent) != codes.Unavailable || status.Convert(concurrent).Message() != "circuit breaker is open" {
+		t.Fatalf("concurrent call got %v, want it failed fast", concurrent)
+	}
+}
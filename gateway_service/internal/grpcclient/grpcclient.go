@@ -0,0 +1,113 @@
+package grpcclient
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/keepalive"
+)
+
+// The user service has to permit pings at least this often, see
+// keepalive.EnforcementPolicy in its main.go.
+const (
+	keepaliveTime    = 30 * time.Second
+	keepaliveTimeout = 10 * time.Second
+)
+
+type Config struct {
+	// Timeout is the deadline of every RPC that has no more specific one.
+	Timeout time.Duration
+	// AuthTimeout is the deadline of Login and Register, which hash or
+	// compare passwords with bcrypt.
+	AuthTimeout time.Duration
+	// RetryAttempts caps the calls made for an idempotent RPC, including the
+	// first one. gRPC allows at most 5.
+	RetryAttempts int
+}
+
+type methodName struct {
+	Service string `json:"service,omitempty"`
+	Method  string `json:"method,omitempty"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	Timeout     string       `json:"timeout"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// ServiceConfig renders the gRPC service config holding the deadlines and
+// retry policy of the user service methods. Retries are only enabled for
+// reads, and gRPC adds jitter to their backoff on its own.
+func ServiceConfig(cfg Config) string {
+	methodConfigs := []methodConfig{
+		{
+			Name:    []methodName{{}},
+			Timeout: protoDuration(cfg.Timeout),
+		},
+		{
+			Name: []methodName{
+				{Service: "user.UserService", Method: "Login"},
+				{Service: "user.UserService", Method: "Register"},
+			},
+			Timeout: protoDuration(cfg.AuthTimeout),
+		},
+	}
+	if cfg.RetryAttempts > 1 {
+		methodConfigs = append(methodConfigs, methodConfig{
+			Name: []methodName{
+				{Service: "user.UserService", Method: "FindByID"},
+				{Service: "user.UserService", Method: "FindByIDs"},
+			},
+			Timeout: protoDuration(cfg.Timeout),
+			RetryPolicy: &retryPolicy{
+				MaxAttempts:          cfg.RetryAttempts,
+				InitialBackoff:       "0.1s",
+				MaxBackoff:           "1s",
+				BackoffMultiplier:    2,
+				RetryableStatusCodes: []string{"UNAVAILABLE"},
+			},
+		})
+	}
+	serviceConfig, _ := json.Marshal(struct {
+		MethodConfig []methodConfig `json:"methodConfig"`
+	}{methodConfigs})
+	return string(serviceConfig)
+}
+
+// DialOptions returns the service config, keepalive and reconnect options
+// shared by connections to the user service.
+func DialOptions(cfg Config) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(ServiceConfig(cfg)),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                keepaliveTime,
+			Timeout:             keepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  500 * time.Millisecond,
+				Multiplier: 1.6,
+				Jitter:     0.2,
+				MaxDelay:   10 * time.Second,
+			},
+			MinConnectTimeout: 5 * time.Second,
+		}),
+	}
+}
+
+func protoDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
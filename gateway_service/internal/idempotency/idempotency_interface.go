@@ -0,0 +1,29 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+type Response struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header"`
+	Body   []byte            `json:"body"`
+}
+
+// Record is what is kept for a key: the request it was first used with and,
+// once that request has finished, its response.
+type Record struct {
+	Fingerprint string    `json:"fingerprint"`
+	Response    *Response `json:"response"`
+}
+
+type IStore interface {
+	// Begin claims key for the request with fingerprint for at most lockTTL.
+	// When key is already claimed it returns the record of key and false.
+	Begin(ctx context.Context, key string, fingerprint string, lockTTL time.Duration) (*Record, bool, error)
+	// Complete keeps the response of the request that claimed key for ttl.
+	Complete(ctx context.Context, key string, record *Record, ttl time.Duration) error
+	// Release drops key so the request can be tried again.
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"gatewayservice/internal/idempotency"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type entry struct {
+	record  idempotency.Record
+	expires time.Time
+}
+
+type store struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+	swept   time.Time
+}
+
+// NewStore keeps records in this process, so a retry that reaches another
+// gateway instance runs again.
+func NewStore() idempotency.IStore {
+	return &store{
+		entries: map[string]*entry{},
+		swept:   time.Now(),
+	}
+}
+
+func (s *store) Begin(ctx context.Context, key string, fingerprint string, lockTTL time.Duration) (*idempotency.Record, bool, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.swept) > sweepInterval {
+		s.sweep(now)
+	}
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		record := e.record
+		return &record, false, nil
+	}
+	s.entries[key] = &entry{
+		record:  idempotency.Record{Fingerprint: fingerprint},
+		expires: now.Add(lockTTL),
+	}
+	return nil, true, nil
+}
+
+func (s *store) Complete(ctx context.Context, key string, record *idempotency.Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = &entry{
+		record:  *record,
+		expires: time.Now().Add(ttl),
+	}
+	return nil
+}
+
+func (s *store) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *store) sweep(now time.Time) {
+	for key, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+	s.swept = now
+}
@@ -0,0 +1,104 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gatewayservice/internal/idempotency"
+	"gatewayservice/internal/redisconn"
+	"strconv"
+	"time"
+)
+
+const keyPrefix = "idempotency:"
+
+type Config = redisconn.Config
+
+type store struct {
+	pool *redisconn.Pool
+}
+
+// NewStore shares records between gateway instances, so a retry is
+// recognized whichever instance it reaches.
+func NewStore(config Config) idempotency.IStore {
+	return &store{
+		pool: redisconn.New(config),
+	}
+}
+
+func (s *store) Begin(ctx context.Context, key string, fingerprint string, lockTTL time.Duration) (*idempotency.Record, bool, error) {
+	const scope = "redisStore#Begin"
+	value, err := json.Marshal(&idempotency.Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", scope, idempotency.ErrUnknown.SetError(err))
+	}
+	// The key may expire between SET NX and GET, in which case claiming it
+	// again is right.
+	for i := 0; i < 2; i++ {
+		reply, err := s.do(ctx, "SET", keyPrefix+key, string(value), "NX", "PX", milliseconds(lockTTL))
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", scope, err)
+		}
+		if reply != nil {
+			return nil, true, nil
+		}
+		reply, err = s.do(ctx, "GET", keyPrefix+key)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", scope, err)
+		}
+		if reply == nil {
+			continue
+		}
+		stored, ok := reply.(string)
+		if !ok {
+			return nil, false, fmt.Errorf("%s: %w", scope, idempotency.ErrBadReply.SetError(fmt.Errorf("%v", reply)))
+		}
+		record := &idempotency.Record{}
+		if err := json.Unmarshal([]byte(stored), record); err != nil {
+			return nil, false, fmt.Errorf("%s: %w", scope, idempotency.ErrBadReply.SetError(err))
+		}
+		return record, false, nil
+	}
+	return nil, false, fmt.Errorf("%s: %w", scope, idempotency.ErrUnknown.SetError(errors.New("key keeps expiring")))
+}
+
+func (s *store) Complete(ctx context.Context, key string, record *idempotency.Record, ttl time.Duration) error {
+	const scope = "redisStore#Complete"
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("%s: %w", scope, idempotency.ErrUnknown.SetError(err))
+	}
+	if _, err := s.do(ctx, "SET", keyPrefix+key, string(value), "PX", milliseconds(ttl)); err != nil {
+		return fmt.Errorf("%s: %w", scope, err)
+	}
+	return nil
+}
+
+func (s *store) Release(ctx context.Context, key string) error {
+	const scope = "redisStore#Release"
+	if _, err := s.do(ctx, "DEL", keyPrefix+key); err != nil {
+		return fmt.Errorf("%s: %w", scope, err)
+	}
+	return nil
+}
+
+func (s *store) do(ctx context.Context, args ...string) (interface{}, error) {
+	reply, err := s.pool.Do(ctx, args...)
+	var replyErr redisconn.ReplyError
+	if errors.As(err, &replyErr) {
+		return nil, idempotency.ErrBadReply.SetError(err)
+	}
+	if err != nil {
+		return nil, idempotency.ErrUnavailable.SetError(err)
+	}
+	return reply, nil
+}
+
+func milliseconds(d time.Duration) string {
+	ms := d.Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+	return strconv.FormatInt(ms, 10)
+}
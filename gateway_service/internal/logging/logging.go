@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"io"
+
+	"golang.org/x/exp/slog"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+type Config struct {
+	Format     string
+	Level      slog.Level
+	AppName    string
+	AppVersion string
+	// SampleInitial records below WARN with the same message are logged per
+	// second before only every SampleThereafter-th one is. Zero disables
+	// sampling.
+	SampleInitial    int
+	SampleThereafter int
+}
+
+// New builds the service logger. Sensitive attributes are redacted whatever
+// the format.
+func New(w io.Writer, cfg Config) *slog.Logger {
+	opts := slog.HandlerOptions{
+		Level:       cfg.Level,
+		AddSource:   true,
+		ReplaceAttr: redact,
+	}
+	var handler slog.Handler
+	if cfg.Format == FormatText {
+		handler = opts.NewTextHandler(w)
+	} else {
+		handler = opts.NewJSONHandler(w)
+	}
+	if cfg.SampleInitial > 0 {
+		handler = newSamplingHandler(handler, cfg.SampleInitial, cfg.SampleThereafter)
+	}
+	return slog.New(handler.WithAttrs(
+		[]slog.Attr{
+			slog.String("app-name", cfg.AppName),
+			slog.String("app-version", cfg.AppVersion),
+		},
+	))
+}
@@ -0,0 +1,55 @@
+package pubsub
+
+import "fmt"
+
+type errKind int
+
+var (
+	ErrSlowConsumer = Error{kind: slowConsumer}
+	ErrClosed       = Error{kind: closed}
+	ErrBadEventData = Error{kind: badEventData}
+	ErrUnknown      = Error{kind: unknown}
+)
+
+const (
+	_ errKind = iota
+	slowConsumer
+	closed
+	badEventData
+	unknown
+)
+
+type Error struct {
+	kind errKind
+	err  error
+}
+
+func (e *Error) Error() string {
+	switch e.kind {
+	case slowConsumer:
+		return "Slow consumer"
+	case closed:
+		return "Pub/sub closed"
+	case badEventData:
+		return fmt.Sprintf("Bad event data %v", e.err)
+	default:
+		return fmt.Sprintf("Unknown error %v", e.err)
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+func (e *Error) Is(err error) bool {
+	target, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+	return target.kind == e.kind
+}
+
+func (e *Error) SetError(err error) *Error {
+	e.err = err
+	return e
+}
@@ -0,0 +1,231 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"gatewayservice/internal/pubsub"
+
+	"golang.org/x/exp/slog"
+)
+
+type topic struct {
+	lastID      uint64
+	history     []*pubsub.Event
+	subscribers map[*subscription]struct{}
+}
+
+type pubSub struct {
+	logger      *slog.Logger
+	historySize int
+	historyTTL  time.Duration
+	bufferSize  int
+	mu          sync.Mutex
+	topics      map[int]*topic
+	closed      bool
+	stop        chan struct{}
+}
+
+// NewPubSub keeps the last historySize events of every user for historyTTL
+// so reconnecting clients can resume. A subscriber whose bufferSize events
+// are not drained in time is dropped instead of blocking publishers.
+func NewPubSub(logger *slog.Logger, historySize int, historyTTL time.Duration, bufferSize int) pubsub.IPubSub {
+	ps := &pubSub{
+		logger:      logger,
+		historySize: historySize,
+		historyTTL:  historyTTL,
+		bufferSize:  bufferSize,
+		topics:      map[int]*topic{},
+		stop:        make(chan struct{}),
+	}
+	go ps.janitor()
+	return ps
+}
+
+func (ps *pubSub) Publish(ctx context.Context, userID int, eventType string, data interface{}) (*pubsub.Event, error) {
+	const scope = "pubSub#Publish"
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, pubsub.ErrBadEventData.SetError(err))
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.closed {
+		return nil, fmt.Errorf("%s: %w", scope, &pubsub.ErrClosed)
+	}
+	t := ps.topic(userID)
+	t.lastID++
+	event := &pubsub.Event{
+		ID:        strconv.FormatUint(t.lastID, 10),
+		Type:      eventType,
+		Data:      rawData,
+		CreatedAt: time.Now(),
+	}
+	t.history = append(t.history, event)
+	if len(t.history) > ps.historySize {
+		t.history = t.history[len(t.history)-ps.historySize:]
+	}
+	for s := range t.subscribers {
+		select {
+		case s.events <- event:
+		default:
+			ps.logger.Warn(
+				"Dropped a slow subscriber",
+				slog.Int("user_id", userID),
+				slog.String("scope", scope),
+			)
+			delete(t.subscribers, s)
+			s.end(&pubsub.ErrSlowConsumer)
+		}
+	}
+	return event, nil
+}
+
+func (ps *pubSub) Subscribe(ctx context.Context, userID int, lastEventID string) (pubsub.ISubscription, error) {
+	const scope = "pubSub#Subscribe"
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.closed {
+		return nil, fmt.Errorf("%s: %w", scope, &pubsub.ErrClosed)
+	}
+	t := ps.topic(userID)
+	s := &subscription{
+		ps:     ps,
+		userID: userID,
+		events: make(chan *pubsub.Event, ps.bufferSize),
+	}
+	if lastEventID != "" {
+		for _, event := range ps.replay(t, lastEventID) {
+			s.events <- event
+		}
+	}
+	t.subscribers[s] = struct{}{}
+	return s, nil
+}
+
+func (ps *pubSub) Close() error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.closed {
+		return nil
+	}
+	ps.closed = true
+	close(ps.stop)
+	for _, t := range ps.topics {
+		for s := range t.subscribers {
+			s.end(&pubsub.ErrClosed)
+		}
+	}
+	ps.topics = map[int]*topic{}
+	return nil
+}
+
+// topic must be called with mu held.
+func (ps *pubSub) topic(userID int) *topic {
+	t, ok := ps.topics[userID]
+	if !ok {
+		t = &topic{subscribers: map[*subscription]struct{}{}}
+		ps.topics[userID] = t
+	}
+	return t
+}
+
+// replay must be called with mu held. It returns the events published after
+// lastEventID, or a single resync event when they are no longer available.
+func (ps *pubSub) replay(t *topic, lastEventID string) []*pubsub.Event {
+	resync := []*pubsub.Event{{
+		ID:        strconv.FormatUint(t.lastID, 10),
+		Type:      pubsub.EventResync,
+		CreatedAt: time.Now(),
+	}}
+	lastID, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil || lastID > t.lastID {
+		return resync
+	}
+	if lastID == t.lastID {
+		return nil
+	}
+	if len(t.history) == 0 {
+		return resync
+	}
+	oldestID, _ := strconv.ParseUint(t.history[0].ID, 10, 64)
+	if lastID+1 < oldestID {
+		return resync
+	}
+	missed := t.history[lastID+1-oldestID:]
+	if len(missed) > ps.bufferSize {
+		return resync
+	}
+	return missed
+}
+
+func (ps *pubSub) janitor() {
+	ticker := time.NewTicker(ps.historyTTL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ps.stop:
+			return
+		case <-ticker.C:
+			ps.prune()
+		}
+	}
+}
+
+func (ps *pubSub) prune() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	expiredBefore := time.Now().Add(-ps.historyTTL)
+	for userID, t := range ps.topics {
+		i := 0
+		for i < len(t.history) && t.history[i].CreatedAt.Before(expiredBefore) {
+			i++
+		}
+		t.history = t.history[i:]
+		if len(t.history) == 0 && len(t.subscribers) == 0 {
+			delete(ps.topics, userID)
+		}
+	}
+}
+
+type subscription struct {
+	ps     *pubSub
+	userID int
+	events chan *pubsub.Event
+	err    error
+	ended  bool
+}
+
+func (s *subscription) Events() <-chan *pubsub.Event {
+	return s.events
+}
+
+func (s *subscription) Err() error {
+	s.ps.mu.Lock()
+	defer s.ps.mu.Unlock()
+	return s.err
+}
+
+func (s *subscription) Close() {
+	s.ps.mu.Lock()
+	defer s.ps.mu.Unlock()
+	if t, ok := s.ps.topics[s.userID]; ok {
+		delete(t.subscribers, s)
+	}
+	s.end(nil)
+}
+
+// end must be called with mu held, which is also what every send on events
+// holds, so the channel is never written after being closed.
+func (s *subscription) end(err error) {
+	if s.ended {
+		return
+	}
+	s.ended = true
+	s.err = err
+	close(s.events)
+}
@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"gatewayservice/internal/pubsub"
+
+	"golang.org/x/exp/slog"
+)
+
+func newTestPubSub(t *testing.T, historySize int, bufferSize int) *pubSub {
+	ps := NewPubSub(slog.New(slog.NewTextHandler(io.Discard)), historySize, time.Hour, bufferSize).(*pubSub)
+	t.Cleanup(func() { ps.Close() })
+	return ps
+}
+
+// drain returns what is buffered on s without waiting for more, as
+// "type:id".
+func drain(s pubsub.ISubscription) []string {
+	got := []string{}
+	for {
+		select {
+		case event, ok := <-s.Events():
+			if !ok {
+				return got
+			}
+			got = append(got, event.Type+":"+event.ID)
+		default:
+			return got
+		}
+	}
+}
+
+func TestSubscribeReplay(t *testing.T) {
+	tests := []struct {
+		name        string
+		historySize int
+		bufferSize  int
+		published   int
+		lastEventID string
+		want        []string
+	}{
+		{name: "from now", historySize: 3, bufferSize: 8, published: 5, lastEventID: "", want: []string{}},
+		{name: "up to date", historySize: 3, bufferSize: 8, published: 5, lastEventID: "5", want: []string{}},
+		{name: "missed one", historySize: 3, bufferSize: 8, published: 5, lastEventID: "4", want: []string{"notification:5"}},
+		{name: "missed the whole history", historySize: 3, bufferSize: 8, published: 5, lastEventID: "2", want: []string{"notification:3", "notification:4", "notification:5"}},
+		{name: "older than the history", historySize: 3, bufferSize: 8, published: 5, lastEventID: "1", want: []string{"stream.resync:5"}},
+		{name: "more than the buffer", historySize: 8, bufferSize: 2, published: 5, lastEventID: "2", want: []string{"stream.resync:5"}},
+		{name: "ahead of the topic", historySize: 3, bufferSize: 8, published: 5, lastEventID: "9", want: []string{"stream.resync:5"}},
+		{name: "not an ID", historySize: 3, bufferSize: 8, published: 5, lastEventID: "abc", want: []string{"stream.resync:5"}},
+		{name: "no history", historySize: 0, bufferSize: 8, published: 2, lastEventID: "1", want: []string{"stream.resync:2"}},
+		{name: "new topic", historySize: 3, bufferSize: 8, published: 0, lastEventID: "0", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := newTestPubSub(t, tt.historySize, tt.bufferSize)
+			ctx := context.Background()
+			for i := 0; i < tt.published; i++ {
+				if _, err := ps.Publish(ctx, 1, pubsub.EventNotification, i); err != nil {
+					t.Fatalf("publish: %v", err)
+				}
+			}
+			s, err := ps.Subscribe(ctx, 1, tt.lastEventID)
+			if err != nil {
+				t.Fatalf("subscribe: %v", err)
+			}
+			if got := drain(s); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscribeAfterPrune(t *testing.T) {
+	ps := newTestPubSub(t, 3, 8)
+	ctx := context.Background()
+	event, err := ps.Publish(ctx, 1, pubsub.EventNotification, nil)
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	event.CreatedAt = time.Now().Add(-2 * time.Hour)
+	ps.prune()
+	s, err := ps.Subscribe(ctx, 1, "0")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	// The topic went with its history, so the ID count restarted.
+	if got, want := drain(s), []string{}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestPublishLive(t *testing.T) {
+	ps := newTestPubSub(t, 3, 8)
+	ctx := context.Background()
+	mine, err := ps.Subscribe(ctx, 1, "")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	other, err := ps.Subscribe(ctx, 2, "")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if _, err := ps.Publish(ctx, 1, pubsub.EventMessageCreated, nil); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if got, want := drain(mine), []string{"message.created:1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got := drain(other); len(got) != 0 {
+		t.Fatalf("another user got %v", got)
+	}
+}
+
+func TestSubscriptionEnds(t *testing.T) {
+	tests := []struct {
+		name string
+		end  func(ps *pubSub, s pubsub.ISubscription)
+		err  error
+	}{
+		{
+			name: "slow consumer",
+			end: func(ps *pubSub, s pubsub.ISubscription) {
+				for i := 0; i < 2; i++ {
+					ps.Publish(context.Background(), 1, pubsub.EventNotification, nil)
+				}
+			},
+			err: &pubsub.ErrSlowConsumer,
+		},
+		{
+			name: "closed pub/sub",
+			end:  func(ps *pubSub, s pubsub.ISubscription) { ps.Close() },
+			err:  &pubsub.ErrClosed,
+		},
+		{
+			name: "closed subscription",
+			end:  func(ps *pubSub, s pubsub.ISubscription) { s.Close() },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := newTestPubSub(t, 3, 1)
+			s, err := ps.Subscribe(context.Background(), 1, "")
+			if err != nil {
+				t.Fatalf("subscribe: %v", err)
+			}
+			tt.end(ps, s)
+			drain(s)
+			if _, ok := <-s.Events(); ok {
+				t.Fatal("events still open")
+			}
+			if err := s.Err(); (tt.err == nil && err != nil) || (tt.err != nil && !errors.Is(err, tt.err)) {
+				t.Fatalf("got %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestClosed(t *testing.T) {
+	ps := newTestPubSub(t, 3, 8)
+	ps.Close()
+	if _, err := ps.Publish(context.Background(), 1, pubsub.EventNotification, nil); !errors.Is(err, &pubsub.ErrClosed) {
+		t.Fatalf("publish got %v", err)
+	}
+	if _, err := ps.Subscribe(context.Background(), 1, ""); !errors.Is(err, &pubsub.ErrClosed) {
+		t.Fatalf("subscribe got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// The event types delivered to users. There is no feed service behind the
+// gateway yet, so feed updates are not pushed.
+const (
+	EventNotification   = "notification"
+	EventMessageCreated = "message.created"
+	EventMessagesRead   = "messages.read"
+	// EventResync tells the client that events were lost between its last
+	// seen event and the current stream, so it must refetch its state.
+	EventResync = "stream.resync"
+)
+
+type Event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type ISubscription interface {
+	// Events is closed when the subscription ends, Err tells why.
+	Events() <-chan *Event
+	Err() error
+	Close()
+}
+
+type IPubSub interface {
+	Publish(ctx context.Context, userID int, eventType string, data interface{}) (*Event, error)
+	// Subscribe replays the events published after lastEventID before
+	// delivering live ones. An empty lastEventID starts from now.
+	Subscribe(ctx context.Context, userID int, lastEventID string) (ISubscription, error)
+	Close() error
+}
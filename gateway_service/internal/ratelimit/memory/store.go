@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"gatewayservice/internal/ratelimit"
+	"math"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket will have refilled, after which it can be
+	// dropped and recreated on demand.
+	full time.Time
+}
+
+type store struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+	now     func() time.Time
+}
+
+// NewStore keeps buckets in this process, so every gateway instance limits
+// on its own.
+func NewStore() ratelimit.IStore {
+	return &store{
+		buckets: map[string]*bucket{},
+		swept:   time.Now(),
+		now:     time.Now,
+	}
+}
+
+func (s *store) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.swept) > sweepInterval {
+		s.sweep(now)
+	}
+	capacity := float64(limit.Requests)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*limit.Rate())
+	b.updated = now
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	result := ratelimit.NewResult(limit, b.tokens, allowed)
+	b.full = now.Add(result.Reset)
+	return result, nil
+}
+
+func (s *store) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if now.After(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	s.swept = now
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gatewayservice/internal/ratelimit"
+)
+
+func TestTake(t *testing.T) {
+	// Two tokens, refilled at one per second.
+	limit := ratelimit.Limit{Requests: 2, Per: 2 * time.Second}
+	type take struct {
+		key        string
+		after      time.Duration
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}
+	tests := []struct {
+		name  string
+		takes []take
+	}{
+		{
+			name: "burst then empty",
+			takes: []take{
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0, retryAfter: time.Second},
+				{allowed: false, remaining: 0, retryAfter: time.Second},
+			},
+		},
+		{
+			name: "refills over time",
+			takes: []take{
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0, retryAfter: time.Second},
+				{after: 500 * time.Millisecond, allowed: false, remaining: 0, retryAfter: 500 * time.Millisecond},
+				{after: 500 * time.Millisecond, allowed: true, remaining: 0, retryAfter: time.Second},
+			},
+		},
+		{
+			name: "refills no further than full",
+			takes: []take{
+				{allowed: true, remaining: 1},
+				{after: time.Hour, allowed: true, remaining: 1},
+				{allowed: true, remaining: 0, retryAfter: time.Second},
+			},
+		},
+		{
+			name: "keys have buckets of their own",
+			takes: []take{
+				{key: "a", allowed: true, remaining: 1},
+				{key: "a", allowed: true, remaining: 0, retryAfter: time.Second},
+				{key: "b", allowed: true, remaining: 1},
+				{key: "a", allowed: false, remaining: 0, retryAfter: time.Second},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			s := NewStore().(*store)
+			s.now = func() time.Time { return now }
+			for i, take := range tt.takes {
+				now = now.Add(take.after)
+				key := take.key
+				if key == "" {
+					key = "a"
+				}
+				result, err := s.Take(context.Background(), key, limit)
+				if err != nil {
+					t.Fatalf("take %d: %v", i, err)
+				}
+				if result.Allowed != take.allowed || result.Remaining != take.remaining || result.RetryAfter != take.retryAfter {
+					t.Fatalf("take %d got %+v, want allowed %v, remaining %d, retry after %v", i, result, take.allowed, take.remaining, take.retryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestSweep(t *testing.T) {
+	now := time.Now()
+	s := NewStore().(*store)
+	s.now = func() time.Time { return now }
+	limit := ratelimit.Limit{Requests: 10, Per: time.Hour}
+	s.Take(context.Background(), "refilled", ratelimit.Limit{Requests: 10, Per: time.Second})
+	s.Take(context.Background(), "refilling", limit)
+	now = now.Add(2 * sweepInterval)
+	s.Take(context.Background(), "new", limit)
+	if _, ok := s.buckets["refilled"]; ok {
+		t.Fatal("a full bucket was kept")
+	}
+	if _, ok := s.buckets["refilling"]; !ok {
+		t.Fatal("a bucket that is still refilling was dropped")
+	}
+}
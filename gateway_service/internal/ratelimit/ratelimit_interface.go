@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Policies the router applies to its route groups.
+const (
+	PolicyAuth    = "auth"
+	PolicyMedia   = "media"
+	PolicyDefault = "default"
+)
+
+// Limit is a token bucket holding Requests tokens that refills completely
+// over Per, so it allows bursts of Requests and Requests per Per on average.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Rate is the number of tokens added back per second.
+func (l Limit) Rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// UserKey and IPKey name the bucket of a caller under policy.
+func UserKey(policy string, userID int) string {
+	return fmt.Sprintf("%s:user:%d", policy, userID)
+}
+
+func IPKey(policy string, ip string) string {
+	return fmt.Sprintf("%s:ip:%s", policy, ip)
+}
+
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until the next token is available.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// NewResult describes a bucket of limit left with tokens after a take.
+func NewResult(limit Limit, tokens float64, allowed bool) Result {
+	result := Result{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((float64(limit.Requests) - tokens) / limit.Rate() * float64(time.Second)),
+	}
+	if tokens < 1 {
+		result.RetryAfter = time.Duration((1 - tokens) / limit.Rate() * float64(time.Second))
+	}
+	return result
+}
+
+type IStore interface {
+	// Take removes a token from the bucket of key, creating a full one when
+	// key has none.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
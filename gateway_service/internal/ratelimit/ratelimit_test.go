@@ -0,0 +1,29 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewResult(t *testing.T) {
+	limit := Limit{Requests: 10, Per: 10 * time.Second}
+	tests := []struct {
+		name    string
+		tokens  float64
+		allowed bool
+		want    Result
+	}{
+		{name: "full", tokens: 10, allowed: true, want: Result{Allowed: true, Remaining: 10}},
+		{name: "after the first take", tokens: 9, allowed: true, want: Result{Allowed: true, Remaining: 9, Reset: time.Second}},
+		{name: "rounds remaining down", tokens: 2.5, allowed: true, want: Result{Allowed: true, Remaining: 2, Reset: 7500 * time.Millisecond}},
+		{name: "last token spent", tokens: 0, allowed: true, want: Result{Allowed: true, Remaining: 0, RetryAfter: time.Second, Reset: 10 * time.Second}},
+		{name: "refilling", tokens: 0.25, allowed: false, want: Result{Remaining: 0, RetryAfter: 750 * time.Millisecond, Reset: 9750 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewResult(limit, tt.tokens, tt.allowed); got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
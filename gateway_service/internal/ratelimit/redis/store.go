@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gatewayservice/internal/ratelimit"
+	"gatewayservice/internal/redisconn"
+	"strconv"
+)
+
+const keyPrefix = "ratelimit:"
+
+// takeScript refills and takes from the bucket in one step so concurrent
+// gateways cannot both spend the last token. It reads the clock of the Redis
+// server rather than the callers', which may drift apart, and so needs Redis
+// 5 or later to be allowed to write after calling TIME.
+const takeScript = `
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) + tonumber(time[2]) / 1000000
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(bucket[1]) or capacity
+local updated = tonumber(bucket[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - updated) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(now))
+redis.call('EXPIRE', KEYS[1], math.ceil((capacity - tokens) / rate) + 1)
+return {allowed, tostring(tokens)}
+`
+
+type Config = redisconn.Config
+
+type store struct {
+	pool *redisconn.Pool
+}
+
+func NewStore(config Config) ratelimit.IStore {
+	return &store{
+		pool: redisconn.New(config),
+	}
+}
+
+func (s *store) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	const scope = "redisStore#Take"
+	reply, err := s.do(
+		ctx,
+		"EVAL", takeScript, "1", keyPrefix+key,
+		strconv.Itoa(limit.Requests),
+		strconv.FormatFloat(limit.Rate(), 'f', -1, 64),
+	)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("%s: %w", scope, err)
+	}
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return ratelimit.Result{}, fmt.Errorf("%s: %w", scope, ratelimit.ErrBadReply.SetError(fmt.Errorf("%v", reply)))
+	}
+	allowed, _ := values[0].(int64)
+	tokensReply, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(tokensReply, 64)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("%s: %w", scope, ratelimit.ErrBadReply.SetError(err))
+	}
+	return ratelimit.NewResult(limit, tokens, allowed == 1), nil
+}
+
+func (s *store) do(ctx context.Context, args ...string) (interface{}, error) {
+	reply, err := s.pool.Do(ctx, args...)
+	var replyErr redisconn.ReplyError
+	if errors.As(err, &replyErr) {
+		return nil, ratelimit.ErrBadReply.SetError(err)
+	}
+	if err != nil {
+		return nil, ratelimit.ErrUnavailable.SetError(err)
+	}
+	return reply, nil
+}
@@ -0,0 +1,179 @@
+package redisconn
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout = time.Second
+	maxIdleConns   = 8
+)
+
+type Config struct {
+	// Addr is the host:port of Redis or of anything that speaks its protocol
+	// and runs Lua scripts, such as KeyDB or Valkey.
+	Addr     string
+	Password string
+	DB       int
+}
+
+// ReplyError is an error reply from the server, after which the connection
+// is still usable.
+type ReplyError string
+
+func (e ReplyError) Error() string {
+	return string(e)
+}
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// Pool keeps a few idle connections to one server and sends commands over
+// them. Errors other than ReplyError mean the server could not be reached.
+type Pool struct {
+	config Config
+	dialer net.Dialer
+	idle   chan *conn
+}
+
+func New(config Config) *Pool {
+	return &Pool{
+		config: config,
+		idle:   make(chan *conn, maxIdleConns),
+	}
+}
+
+// Do sends one command and returns its reply, see read for the types.
+func (p *Pool) Do(ctx context.Context, args ...string) (interface{}, error) {
+	c, err := p.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	if err := c.SetDeadline(deadline); err != nil {
+		c.Close()
+		return nil, err
+	}
+	reply, err := c.do(args...)
+	var replyErr ReplyError
+	if err != nil && !errors.As(err, &replyErr) {
+		c.Close()
+		return nil, err
+	}
+	p.put(c)
+	return reply, err
+}
+
+func (p *Pool) get(ctx context.Context) (*conn, error) {
+	select {
+	case c := <-p.idle:
+		return c, nil
+	default:
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	netConn, err := p.dialer.DialContext(dialCtx, "tcp", p.config.Addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &conn{Conn: netConn, r: bufio.NewReader(netConn)}
+	if deadline, ok := dialCtx.Deadline(); ok {
+		c.SetDeadline(deadline)
+	}
+	// Failing to set up the connection is not a ReplyError to callers, even
+	// when the server answered with one.
+	if p.config.Password != "" {
+		if _, err := c.do("AUTH", p.config.Password); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("AUTH: %v", err)
+		}
+	}
+	if p.config.DB != 0 {
+		if _, err := c.do("SELECT", strconv.Itoa(p.config.DB)); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("SELECT: %v", err)
+		}
+	}
+	return c, nil
+}
+
+func (p *Pool) put(c *conn) {
+	select {
+	case p.idle <- c:
+	default:
+		c.Close()
+	}
+}
+
+func (c *conn) do(args ...string) (interface{}, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c, b.String()); err != nil {
+		return nil, err
+	}
+	return c.read()
+}
+
+// read parses one RESP2 reply. Bulk strings become strings, integers int64
+// and arrays []interface{}; nil bulk strings and arrays become nil.
+func (c *conn) read() (interface{}, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, ReplyError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			if values[i], err = c.read(); err != nil {
+				var replyErr ReplyError
+				if !errors.As(err, &replyErr) {
+					return nil, err
+				}
+			}
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("unknown reply type %q", kind)
+	}
+}
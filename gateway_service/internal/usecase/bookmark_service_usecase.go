@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"gatewayservice/internal/dto/req"
+	"gatewayservice/internal/logging"
+	"gatewayservice/internal/util"
+
+	"github.com/go-playground/validator/v10"
+	bookmarkPb "github.com/ideaspaper/social-media-proto/bookmark"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc/metadata"
+)
+
+type bookmarkServiceUsecase struct {
+	logger                *slog.Logger
+	validate              *validator.Validate
+	bookmarkServiceClient bookmarkPb.BookmarkServiceClient
+}
+
+func NewBookmarkServiceUsecase(logger *slog.Logger, validate *validator.Validate, bookmarkServiceClient bookmarkPb.BookmarkServiceClient) IBookmarkServiceUsecase {
+	return &bookmarkServiceUsecase{
+		logger:                logger,
+		validate:              validate,
+		bookmarkServiceClient: bookmarkServiceClient,
+	}
+}
+
+func (u bookmarkServiceUsecase) CreateCollection(ctx context.Context, userID int, collectionDto *req.CollectionDto) (*bookmarkPb.CreateCollectionResp, error) {
+	const scope = "bookmarkServiceUsecase#CreateCollection"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, collectionDto); err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	response, err := u.bookmarkServiceClient.CreateCollection(mdCtx, &bookmarkPb.CreateCollectionReq{
+		UserId:    int64(userID),
+		Name:      collectionDto.Name,
+		IsPrivate: collectionDto.IsPrivate,
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u bookmarkServiceUsecase) UpdateCollection(ctx context.Context, userID int, collectionID int, updateCollectionDto *req.UpdateCollectionDto) (*bookmarkPb.UpdateCollectionResp, error) {
+	const scope = "bookmarkServiceUsecase#UpdateCollection"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, updateCollectionDto); err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	response, err := u.bookmarkServiceClient.UpdateCollection(mdCtx, &bookmarkPb.UpdateCollectionReq{
+		UserId:    int64(userID),
+		Id:        int64(collectionID),
+		Name:      updateCollectionDto.Name,
+		IsPrivate: updateCollectionDto.IsPrivate,
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u bookmarkServiceUsecase) DeleteCollection(ctx context.Context, userID int, collectionID int) (*bookmarkPb.DeleteCollectionResp, error) {
+	const scope = "bookmarkServiceUsecase#DeleteCollection"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	response, err := u.bookmarkServiceClient.DeleteCollection(mdCtx, &bookmarkPb.DeleteCollectionReq{
+		UserId: int64(userID),
+		Id:     int64(collectionID),
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u bookmarkServiceUsecase) FindCollections(ctx context.Context, viewerID int, userID int, pageDto *req.PageDto) (*bookmarkPb.FindCollectionsResp, error) {
+	const scope = "bookmarkServiceUsecase#FindCollections"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	response, err := u.bookmarkServiceClient.FindCollections(mdCtx, &bookmarkPb.FindCollectionsReq{
+		ViewerId: int64(viewerID),
+		UserId:   int64(userID),
+		Cursor:   pageDto.Cursor,
+		Limit:    int32(pageDto.Limit),
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u bookmarkServiceUsecase) AddBookmark(ctx context.Context, userID int, bookmarkDto *req.BookmarkDto) (*bookmarkPb.AddBookmarkResp, error) {
+	const scope = "bookmarkServiceUsecase#AddBookmark"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, bookmarkDto); err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	addBookmarkReq := &bookmarkPb.AddBookmarkReq{
+		UserId:   int64(userID),
+		ItemType: bookmarkDto.ItemType,
+		ItemId:   int64(bookmarkDto.ItemID),
+	}
+	if bookmarkDto.CollectionID != nil {
+		collectionID := int64(*bookmarkDto.CollectionID)
+		addBookmarkReq.CollectionId = &collectionID
+	}
+	response, err := u.bookmarkServiceClient.AddBookmark(mdCtx, addBookmarkReq)
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u bookmarkServiceUsecase) RemoveBookmark(ctx context.Context, userID int, itemType string, itemID int) (*bookmarkPb.RemoveBookmarkResp, error) {
+	const scope = "bookmarkServiceUsecase#RemoveBookmark"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	response, err := u.bookmarkServiceClient.RemoveBookmark(mdCtx, &bookmarkPb.RemoveBookmarkReq{
+		UserId:   int64(userID),
+		ItemType: itemType,
+		ItemId:   int64(itemID),
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u bookmarkServiceUsecase) FindBookmarks(ctx context.Context, userID int, pageDto *req.PageDto) (*bookmarkPb.FindBookmarksResp, error) {
+	const scope = "bookmarkServiceUsecase#FindBookmarks"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	response, err := u.bookmarkServiceClient.FindBookmarks(mdCtx, &bookmarkPb.FindBookmarksReq{
+		UserId: int64(userID),
+		Cursor: pageDto.Cursor,
+		Limit:  int32(pageDto.Limit),
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u bookmarkServiceUsecase) FindCollectionBookmarks(ctx context.Context, viewerID int, collectionID int, pageDto *req.PageDto) (*bookmarkPb.FindCollectionBookmarksResp, error) {
+	const scope = "bookmarkServiceUsecase#FindCollectionBookmarks"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	response, err := u.bookmarkServiceClient.FindCollectionBookmarks(mdCtx, &bookmarkPb.FindCollectionBookmarksReq{
+		ViewerId:     int64(viewerID),
+		CollectionId: int64(collectionID),
+		Cursor:       pageDto.Cursor,
+		Limit:        int32(pageDto.Limit),
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
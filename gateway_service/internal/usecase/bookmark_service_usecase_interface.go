@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"context"
+	"gatewayservice/internal/dto/req"
+
+	bookmarkPb "github.com/ideaspaper/social-media-proto/bookmark"
+)
+
+type IBookmarkServiceUsecase interface {
+	CreateCollection(ctx context.Context, userID int, collectionDto *req.CollectionDto) (*bookmarkPb.CreateCollectionResp, error)
+	UpdateCollection(ctx context.Context, userID int, collectionID int, updateCollectionDto *req.UpdateCollectionDto) (*bookmarkPb.UpdateCollectionResp, error)
+	DeleteCollection(ctx context.Context, userID int, collectionID int) (*bookmarkPb.DeleteCollectionResp, error)
+	FindCollections(ctx context.Context, viewerID int, userID int, pageDto *req.PageDto) (*bookmarkPb.FindCollectionsResp, error)
+	AddBookmark(ctx context.Context, userID int, bookmarkDto *req.BookmarkDto) (*bookmarkPb.AddBookmarkResp, error)
+	RemoveBookmark(ctx context.Context, userID int, itemType string, itemID int) (*bookmarkPb.RemoveBookmarkResp, error)
+	FindBookmarks(ctx context.Context, userID int, pageDto *req.PageDto) (*bookmarkPb.FindBookmarksResp, error)
+	FindCollectionBookmarks(ctx context.Context, viewerID int, collectionID int, pageDto *req.PageDto) (*bookmarkPb.FindCollectionBookmarksResp, error)
+}
@@ -8,6 +8,11 @@ var (
 	ErrClientService  = Error{kind: clientService}
 	ErrFailToValidate = Error{kind: failToValidate}
 	ErrFailSigningJWT = Error{kind: failSigningJWT}
+	ErrPubSub         = Error{kind: pubSub}
+	ErrMediaNotFound  = Error{kind: mediaNotFound}
+	ErrMediaTooLarge  = Error{kind: mediaTooLarge}
+	ErrBadMedia       = Error{kind: badMedia}
+	ErrBlobStore      = Error{kind: blobStore}
 	ErrUnknown        = Error{kind: unknown}
 )
 
@@ -16,6 +21,11 @@ const (
 	clientService
 	failToValidate
 	failSigningJWT
+	pubSub
+	mediaNotFound
+	mediaTooLarge
+	badMedia
+	blobStore
 	unknown
 )
 
@@ -32,6 +42,16 @@ func (e *Error) Error() string {
 		return fmt.Sprintf("Fail to validate %v", e.err)
 	case failSigningJWT:
 		return fmt.Sprintf("Fail signing JWT %v", e.err)
+	case pubSub:
+		return fmt.Sprintf("Pub/sub error %v", e.err)
+	case mediaNotFound:
+		return fmt.Sprintf("Media not found %v", e.err)
+	case mediaTooLarge:
+		return fmt.Sprintf("Media too large %v", e.err)
+	case badMedia:
+		return fmt.Sprintf("Bad media %v", e.err)
+	case blobStore:
+		return fmt.Sprintf("Blob store error %v", e.err)
 	default:
 		return fmt.Sprintf("Unknown error %v", e.err)
 	}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listArguments names the argument whose length multiplies the cost of what
+// a list field selects, such as the ids of users.
+var listArguments = map[string]string{
+	"users": "ids",
+}
+
+// queryCost measures the operation of doc that will run. Depth counts the
+// levels of nested fields and complexity counts every field once per item
+// of the list it is selected on. Fragments are expanded and introspection
+// fields are free, so tools can always load the schema. doc must have passed
+// validation, which rules out fragment cycles.
+func queryCost(doc *ast.Document, operationName string, variables map[string]interface{}) (depth int, complexity int) {
+	fragments := map[string]*ast.FragmentDefinition{}
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	if operation == nil {
+		return 0, 0
+	}
+	c := &coster{fragments: fragments, variables: variables}
+	return c.selectionSet(operation.SelectionSet)
+}
+
+type coster struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+func (c *coster) selectionSet(set *ast.SelectionSet) (depth int, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, selection := range set.Selections {
+		var d, n int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			d, n = c.selectionSet(selection.SelectionSet)
+			d, n = d+1, 1+n*c.items(selection)
+		case *ast.InlineFragment:
+			d, n = c.selectionSet(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := c.fragments[selection.Name.Value]; ok {
+				d, n = c.selectionSet(fragment.SelectionSet)
+			}
+		}
+		if d > depth {
+			depth = d
+		}
+		complexity += n
+	}
+	return depth, complexity
+}
+
+// items is how many items field returns, or 1 when it is not a list field.
+func (c *coster) items(field *ast.Field) int {
+	name, ok := listArguments[field.Name.Value]
+	if !ok {
+		return 1
+	}
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != name {
+			continue
+		}
+		switch value := argument.Value.(type) {
+		case *ast.ListValue:
+			return len(value.Values)
+		case *ast.Variable:
+			if list, ok := c.variables[value.Name.Value].([]interface{}); ok {
+				return len(list)
+			}
+		}
+	}
+	return 1
+}
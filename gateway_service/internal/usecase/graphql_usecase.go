@@ -0,0 +1,532 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gatewayservice/internal/dataloader"
+	"gatewayservice/internal/dto/req"
+	"gatewayservice/internal/logging"
+	"gatewayservice/internal/ratelimit"
+	"gatewayservice/internal/util"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	userPb "github.com/ideaspaper/social-media-proto/user"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxUsersPerQuery caps the ids of a users query, and so the size of a batch
+// sent to the user service.
+const maxUsersPerQuery = 100
+
+// Codes set in the extensions of GraphQL errors.
+const (
+	codeBadUserInput    = "BAD_USER_INPUT"
+	codeUnauthenticated = "UNAUTHENTICATED"
+	codeForbidden       = "FORBIDDEN"
+	codeNotFound        = "NOT_FOUND"
+	codeConflict        = "CONFLICT"
+	codeRateLimited     = "RATE_LIMITED"
+	codeQueryTooDeep    = "QUERY_TOO_DEEP"
+	codeQueryTooComplex = "QUERY_TOO_COMPLEX"
+	codeUnavailable     = "SERVICE_UNAVAILABLE"
+	codeTimeout         = "GATEWAY_TIMEOUT"
+	codeInternal        = "INTERNAL_SERVER_ERROR"
+)
+
+var grpcToGraphQL = map[codes.Code]string{
+	codes.InvalidArgument:    codeBadUserInput,
+	codes.Unauthenticated:    codeUnauthenticated,
+	codes.PermissionDenied:   codeForbidden,
+	codes.NotFound:           codeNotFound,
+	codes.AlreadyExists:      codeConflict,
+	codes.FailedPrecondition: codeConflict,
+	codes.Unavailable:        codeUnavailable,
+	codes.DeadlineExceeded:   codeTimeout,
+}
+
+// graphqlError is what resolvers return, graphql-go copies its extensions
+// into the error in the response.
+type graphqlError struct {
+	message    string
+	extensions map[string]interface{}
+}
+
+func newGraphQLError(code string, message string) graphqlError {
+	return graphqlError{
+		message:    message,
+		extensions: map[string]interface{}{"code": code},
+	}
+}
+
+func (e graphqlError) Error() string {
+	return e.message
+}
+
+func (e graphqlError) Extensions() map[string]interface{} {
+	return e.extensions
+}
+
+// graphqlErrorOf turns an error from the other usecases into one that is safe
+// to show to the client.
+func graphqlErrorOf(err error) error {
+	var graphqlErr graphqlError
+	if errors.As(err, &graphqlErr) {
+		return graphqlErr
+	}
+	var usecaseErr *Error
+	if errors.As(err, &usecaseErr) {
+		if errors.Is(usecaseErr, &ErrFailToValidate) {
+			return newGraphQLError(codeBadUserInput, usecaseErr.Unwrap().Error())
+		}
+		if grpcStatus, ok := status.FromError(usecaseErr.Unwrap()); ok && errors.Is(usecaseErr, &ErrClientService) {
+			if code, known := grpcToGraphQL[grpcStatus.Code()]; known {
+				switch code {
+				case codeUnavailable:
+					return newGraphQLError(code, http.StatusText(http.StatusServiceUnavailable))
+				case codeTimeout:
+					return newGraphQLError(code, http.StatusText(http.StatusGatewayTimeout))
+				}
+				return newGraphQLError(code, grpcStatus.Message())
+			}
+		}
+	}
+	return newGraphQLError(codeInternal, http.StatusText(http.StatusInternalServerError))
+}
+
+type userLoaderKey struct{}
+
+type userLoader = dataloader.Loader[int64, *userPb.UserResp]
+
+type graphqlUsecase struct {
+	logger             *slog.Logger
+	validate           *validator.Validate
+	userServiceUsecase IUserServiceUsecase
+	rateLimitStore     ratelimit.IStore
+	authLimit          ratelimit.Limit
+	maxDepth           int
+	maxComplexity      int
+	schema             graphql.Schema
+}
+
+// NewGraphQLUsecase takes a nil rateLimitStore when rate limiting is off.
+// Otherwise the login and register mutations spend tokens of authLimit from
+// the same buckets as the REST routes, so GraphQL is no way around them.
+func NewGraphQLUsecase(logger *slog.Logger, validate *validator.Validate, userServiceUsecase IUserServiceUsecase, rateLimitStore ratelimit.IStore, authLimit ratelimit.Limit, maxDepth int, maxComplexity int) (IGraphQLUsecase, error) {
+	u := &graphqlUsecase{
+		logger:             logger,
+		validate:           validate,
+		userServiceUsecase: userServiceUsecase,
+		rateLimitStore:     rateLimitStore,
+		authLimit:          authLimit,
+		maxDepth:           maxDepth,
+		maxComplexity:      maxComplexity,
+	}
+	schema, err := u.newSchema()
+	if err != nil {
+		return nil, err
+	}
+	u.schema = schema
+	return u, nil
+}
+
+func (u *graphqlUsecase) newSchema() (graphql.Schema, error) {
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+				Resolve: userField(func(user *userPb.UserResp) interface{} {
+					return strconv.FormatInt(user.GetId(), 10)
+				}),
+			},
+			"email": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: userField(func(user *userPb.UserResp) interface{} {
+					return user.GetEmail()
+				}),
+			},
+			"firstName": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: userField(func(user *userPb.UserResp) interface{} {
+					return user.GetFirstName()
+				}),
+			},
+			"lastName": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: userField(func(user *userPb.UserResp) interface{} {
+					return user.GetLastName()
+				}),
+			},
+			"createdAt": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: userField(func(user *userPb.UserResp) interface{} {
+					return user.GetCreatedAt()
+				}),
+			},
+			"updatedAt": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: userField(func(user *userPb.UserResp) interface{} {
+					return user.GetUpdatedAt()
+				}),
+			},
+			"deletedAt": &graphql.Field{
+				Type: graphql.String,
+				Resolve: userField(func(user *userPb.UserResp) interface{} {
+					if user.DeletedAt == nil {
+						return nil
+					}
+					return *user.DeletedAt
+				}),
+			},
+		},
+	})
+	loginPayloadType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "LoginPayload",
+		Fields: graphql.Fields{
+			"token": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+	registerInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "RegisterInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"email":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"password":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"firstName": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"lastName":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+	idArgs := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+	}
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"user": &graphql.Field{
+				Type:    userType,
+				Args:    idArgs,
+				Resolve: u.resolveUser,
+			},
+			"users": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(userType)),
+				Description: "Users that do not exist come back as null.",
+				Args: graphql.FieldConfigArgument{
+					"ids": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID)))},
+				},
+				Resolve: u.resolveUsers,
+			},
+			"me": &graphql.Field{
+				Type:    userType,
+				Resolve: u.resolveMe,
+			},
+		},
+	})
+	mutationType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"register": &graphql.Field{
+				Type: graphql.NewNonNull(userType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(registerInputType)},
+				},
+				Resolve: u.resolveRegister,
+			},
+			"login": &graphql.Field{
+				Type: graphql.NewNonNull(loginPayloadType),
+				Args: graphql.FieldConfigArgument{
+					"email":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"password": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: u.resolveLogin,
+			},
+			"softDeleteUser": &graphql.Field{
+				Type:        graphql.NewNonNull(userType),
+				Description: "Only the user themselves or a moderator may delete a user.",
+				Args:        idArgs,
+				Resolve:     u.resolveSoftDeleteUser,
+			},
+			"deleteUser": &graphql.Field{
+				Type:        graphql.NewNonNull(userType),
+				Description: "Only the user themselves or a moderator may delete a user.",
+				Args:        idArgs,
+				Resolve:     u.resolveDeleteUser,
+			},
+		},
+	})
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    queryType,
+		Mutation: mutationType,
+	})
+}
+
+func userField(get func(user *userPb.UserResp) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(*userPb.UserResp)), nil
+	}
+}
+
+func (u graphqlUsecase) Execute(ctx context.Context, graphqlDto *req.GraphQLDto) (*graphql.Result, error) {
+	const scope = "graphqlUsecase#Execute"
+	err := u.validate.Struct(graphqlDto)
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		validatorErrors := err.(validator.ValidationErrors)
+		errorMessages := []string{}
+		for _, validatorError := range validatorErrors {
+			errorMessages = append(errorMessages, graphqlDto.ErrorMessages(validatorError.Field(), validatorError.Tag()))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(errors.New(strings.Join(errorMessages, ", "))))
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: graphqlDto.Query})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, nil
+	}
+	validation := graphql.ValidateDocument(&u.schema, doc, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}, nil
+	}
+	// The limits are checked before anything runs, so an expensive query
+	// costs the user service nothing.
+	depth, complexity := queryCost(doc, graphqlDto.OperationName, graphqlDto.Variables)
+	if depth > u.maxDepth {
+		logging.FromContext(ctx, u.logger).Warn(
+			"Rejected a query that is too deep",
+			slog.String("scope", scope),
+			slog.Int("depth", depth),
+		)
+		return limitResult(newGraphQLError(codeQueryTooDeep, fmt.Sprintf("Query depth %d exceeds the limit of %d", depth, u.maxDepth))), nil
+	}
+	if complexity > u.maxComplexity {
+		logging.FromContext(ctx, u.logger).Warn(
+			"Rejected a query that is too complex",
+			slog.String("scope", scope),
+			slog.Int("complexity", complexity),
+		)
+		return limitResult(newGraphQLError(codeQueryTooComplex, fmt.Sprintf("Query complexity %d exceeds the limit of %d", complexity, u.maxComplexity))), nil
+	}
+	loader := dataloader.New(u.userServiceUsecase.FindUsersByIDs, maxUsersPerQuery)
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        u.schema,
+		AST:           doc,
+		OperationName: graphqlDto.OperationName,
+		Args:          graphqlDto.Variables,
+		Context:       context.WithValue(ctx, userLoaderKey{}, loader),
+	})
+	for i, err := range result.Errors {
+		if err.Extensions == nil {
+			result.Errors[i].Extensions = extensionsOf(err)
+		}
+	}
+	return result, nil
+}
+
+// extensionsOf finds the extensions of the error err wraps. graphql-go wraps
+// the error a thunk returns twice and loses its extensions on the way.
+func extensionsOf(err error) map[string]interface{} {
+	for err != nil {
+		if extended, ok := err.(gqlerrors.ExtendedError); ok {
+			return extended.Extensions()
+		}
+		switch wrapped := err.(type) {
+		case gqlerrors.FormattedError:
+			err = wrapped.OriginalError()
+		case *gqlerrors.Error:
+			err = wrapped.OriginalError
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func limitResult(err graphqlError) *graphql.Result {
+	formatted := gqlerrors.FormatError(err)
+	formatted.Extensions = err.Extensions()
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{formatted}}
+}
+
+func parseUserID(value interface{}) (int64, error) {
+	userID, err := strconv.ParseInt(fmt.Sprint(value), 10, 64)
+	if err != nil || userID < 1 {
+		return 0, newGraphQLError(codeBadUserInput, fmt.Sprintf("%v is not a valid user ID", value))
+	}
+	return userID, nil
+}
+
+// loadUser queues userID on the loader of the request and returns a thunk.
+// graphql-go calls thunks only once every sibling field has been resolved,
+// so all users a query asks for at one level are found in a single batch.
+func (u graphqlUsecase) loadUser(ctx context.Context, userID int64) func() (interface{}, error) {
+	thunk := ctx.Value(userLoaderKey{}).(*userLoader).Load(ctx, userID)
+	return func() (interface{}, error) {
+		user, err := thunk()
+		if err != nil {
+			return nil, graphqlErrorOf(err)
+		}
+		return user, nil
+	}
+}
+
+func (u graphqlUsecase) resolveUser(p graphql.ResolveParams) (interface{}, error) {
+	userID, err := parseUserID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	return u.loadUser(p.Context, userID), nil
+}
+
+func (u graphqlUsecase) resolveUsers(p graphql.ResolveParams) (interface{}, error) {
+	ids, _ := p.Args["ids"].([]interface{})
+	if len(ids) > maxUsersPerQuery {
+		return nil, newGraphQLError(codeBadUserInput, fmt.Sprintf("At most %d ids can be asked for at once", maxUsersPerQuery))
+	}
+	thunks := []func() (interface{}, error){}
+	for _, id := range ids {
+		userID, err := parseUserID(id)
+		if err != nil {
+			return nil, err
+		}
+		thunks = append(thunks, u.loadUser(p.Context, userID))
+	}
+	return func() (interface{}, error) {
+		users := make([]interface{}, len(thunks))
+		for i, thunk := range thunks {
+			user, err := thunk()
+			var graphqlErr graphqlError
+			if errors.As(err, &graphqlErr) && graphqlErr.extensions["code"] == codeNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			users[i] = user
+		}
+		return users, nil
+	}, nil
+}
+
+func (u graphqlUsecase) resolveMe(p graphql.ResolveParams) (interface{}, error) {
+	userID, ok := p.Context.Value(util.UserID).(int)
+	if !ok {
+		return nil, newGraphQLError(codeUnauthenticated, "Invalid or missing access token")
+	}
+	return u.loadUser(p.Context, int64(userID)), nil
+}
+
+func (u graphqlUsecase) resolveRegister(p graphql.ResolveParams) (interface{}, error) {
+	if err := u.spendAuthToken(p.Context); err != nil {
+		return nil, err
+	}
+	input, _ := p.Args["input"].(map[string]interface{})
+	email, _ := input["email"].(string)
+	password, _ := input["password"].(string)
+	firstName, _ := input["firstName"].(string)
+	lastName, _ := input["lastName"].(string)
+	response, err := u.userServiceUsecase.Register(p.Context, &req.UserDto{
+		Email:     email,
+		Password:  password,
+		FirstName: firstName,
+		LastName:  lastName,
+	})
+	if err != nil {
+		return nil, graphqlErrorOf(err)
+	}
+	return response.GetUserResp(), nil
+}
+
+func (u graphqlUsecase) resolveLogin(p graphql.ResolveParams) (interface{}, error) {
+	if err := u.spendAuthToken(p.Context); err != nil {
+		return nil, err
+	}
+	email, _ := p.Args["email"].(string)
+	password, _ := p.Args["password"].(string)
+	response, err := u.userServiceUsecase.Login(p.Context, &req.LoginDto{
+		Email:    email,
+		Password: password,
+	})
+	if err != nil {
+		return nil, graphqlErrorOf(err)
+	}
+	return map[string]interface{}{"token": response.Token}, nil
+}
+
+func (u graphqlUsecase) resolveSoftDeleteUser(p graphql.ResolveParams) (interface{}, error) {
+	userID, err := u.authorizeDelete(p)
+	if err != nil {
+		return nil, err
+	}
+	response, err := u.userServiceUsecase.DeleteUserByID(p.Context, userID)
+	if err != nil {
+		return nil, graphqlErrorOf(err)
+	}
+	return response.GetUserResp(), nil
+}
+
+func (u graphqlUsecase) resolveDeleteUser(p graphql.ResolveParams) (interface{}, error) {
+	userID, err := u.authorizeDelete(p)
+	if err != nil {
+		return nil, err
+	}
+	response, err := u.userServiceUsecase.DeleteUserPermanentlyByID(p.Context, userID)
+	if err != nil {
+		return nil, graphqlErrorOf(err)
+	}
+	return response.GetUserResp(), nil
+}
+
+// authorizeDelete returns the user ID to delete when there is a caller. The
+// user service checks that the caller is that user or a moderator, as it
+// does for the REST routes.
+func (u graphqlUsecase) authorizeDelete(p graphql.ResolveParams) (int, error) {
+	userID, err := parseUserID(p.Args["id"])
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := p.Context.Value(util.UserID).(int); !ok {
+		return 0, newGraphQLError(codeUnauthenticated, "Invalid or missing access token")
+	}
+	return int(userID), nil
+}
+
+// spendAuthToken fails open when the store does, like the middleware.
+func (u graphqlUsecase) spendAuthToken(ctx context.Context) error {
+	const scope = "graphqlUsecase#spendAuthToken"
+	if u.rateLimitStore == nil {
+		return nil
+	}
+	clientIP, _ := ctx.Value(util.ClientIP).(string)
+	result, err := u.rateLimitStore.Take(ctx, ratelimit.IPKey(ratelimit.PolicyAuth, clientIP), u.authLimit)
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Failed to rate limit",
+			err,
+			slog.String("scope", scope),
+			slog.String("policy", ratelimit.PolicyAuth),
+		)
+		return nil
+	}
+	if !result.Allowed {
+		logging.FromContext(ctx, u.logger).Warn(
+			"Rate limit exceeded",
+			slog.String("scope", scope),
+			slog.String("policy", ratelimit.PolicyAuth),
+		)
+		err := newGraphQLError(codeRateLimited, "Too many requests, slow down")
+		err.extensions["retryAfter"] = int(math.Ceil(result.RetryAfter.Seconds()))
+		return err
+	}
+	return nil
+}
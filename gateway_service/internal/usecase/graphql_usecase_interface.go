@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+	"gatewayservice/internal/dto/req"
+
+	"github.com/graphql-go/graphql"
+)
+
+type IGraphQLUsecase interface {
+	// Execute only returns an error when graphqlDto is invalid. Problems with
+	// the query itself are reported in the errors of the result.
+	Execute(ctx context.Context, graphqlDto *req.GraphQLDto) (*graphql.Result, error)
+}
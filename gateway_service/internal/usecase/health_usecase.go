@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"gatewayservice/internal/dto/resp"
+	"gatewayservice/internal/logging"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	healthPb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+const healthCheckTimeout = 2 * time.Second
+
+type healthUsecase struct {
+	logger            *slog.Logger
+	userServiceConn   *grpc.ClientConn
+	userServiceHealth healthPb.HealthClient
+}
+
+func NewHealthUsecase(logger *slog.Logger, userServiceConn *grpc.ClientConn, userServiceHealth healthPb.HealthClient) IHealthUsecase {
+	return &healthUsecase{
+		logger:            logger,
+		userServiceConn:   userServiceConn,
+		userServiceHealth: userServiceHealth,
+	}
+}
+
+// Liveness only fails once the user service connection is shut down, which
+// the gateway cannot recover from. An unreachable user service is a
+// readiness problem; restarting the gateway would not fix it.
+func (u healthUsecase) Liveness(ctx context.Context) *resp.HealthDto {
+	state := u.userServiceConn.GetState()
+	healthDto := &resp.HealthDto{
+		Status: HealthStatusUp,
+		Checks: map[string]string{
+			"user_service_connection": strings.ToLower(state.String()),
+		},
+	}
+	if state == connectivity.Shutdown {
+		healthDto.Status = HealthStatusDown
+	}
+	return healthDto
+}
+
+func (u healthUsecase) Readiness(ctx context.Context) *resp.HealthDto {
+	const scope = "healthUsecase#Readiness"
+	checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	healthDto := &resp.HealthDto{
+		Status: HealthStatusUp,
+		Checks: map[string]string{},
+	}
+	response, err := u.userServiceHealth.Check(checkCtx, &healthPb.HealthCheckRequest{})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Warn(
+			"User service health check failed",
+			slog.String("scope", scope),
+			slog.Any("error", err),
+		)
+		healthDto.Status = HealthStatusDown
+		healthDto.Checks["user_service"] = "unreachable"
+	} else {
+		if response.Status != healthPb.HealthCheckResponse_SERVING {
+			healthDto.Status = HealthStatusDown
+		}
+		healthDto.Checks["user_service"] = strings.ToLower(response.Status.String())
+	}
+	state := u.userServiceConn.GetState()
+	if state == connectivity.TransientFailure || state == connectivity.Shutdown {
+		healthDto.Status = HealthStatusDown
+	}
+	healthDto.Checks["user_service_connection"] = strings.ToLower(state.String())
+	return healthDto
+}
@@ -0,0 +1,11 @@
+package usecase
+
+import (
+	"context"
+	"gatewayservice/internal/dto/resp"
+)
+
+type IHealthUsecase interface {
+	Liveness(ctx context.Context) *resp.HealthDto
+	Readiness(ctx context.Context) *resp.HealthDto
+}
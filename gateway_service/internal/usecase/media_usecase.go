@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gatewayservice/internal/blobstore"
+	"gatewayservice/internal/dto/resp"
+	"gatewayservice/internal/logging"
+	"gatewayservice/internal/util"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+const (
+	MediaVariantOriginal  = "original"
+	MediaVariantThumbnail = "thumbnail"
+)
+
+const (
+	maxMediaSize      = 10 << 20
+	maxMediaDimension = 8000
+	thumbnailSide     = 320
+)
+
+var allowedMediaTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+var mediaIDPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+type mediaMetadata struct {
+	ID           string    `json:"id"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	HasThumbnail bool      `json:"has_thumbnail"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (m mediaMetadata) toDto() *resp.MediaDto {
+	result := &resp.MediaDto{
+		ID:          m.ID,
+		ContentType: m.ContentType,
+		Size:        m.Size,
+		Width:       m.Width,
+		Height:      m.Height,
+		URL:         fmt.Sprintf("/v1/media/%s/content", m.ID),
+		CreatedAt:   m.CreatedAt.String(),
+	}
+	if m.HasThumbnail {
+		thumbnailURL := fmt.Sprintf("/v1/media/%s/thumbnail", m.ID)
+		result.ThumbnailURL = &thumbnailURL
+	}
+	return result
+}
+
+type mediaUsecase struct {
+	logger    *slog.Logger
+	blobStore blobstore.IBlobStore
+}
+
+func NewMediaUsecase(logger *slog.Logger, blobStore blobstore.IBlobStore) IMediaUsecase {
+	return &mediaUsecase{
+		logger:    logger,
+		blobStore: blobStore,
+	}
+}
+
+// Blobs are sharded by the first two characters of the ID to keep
+// directories small on the local backend.
+func mediaKey(mediaID string, name string) string {
+	return fmt.Sprintf("media/%s/%s/%s", mediaID[:2], mediaID, name)
+}
+
+// Upload stores an image under the SHA-256 of its content, so uploading the
+// same file twice returns the media created the first time. The media is
+// shared, so each uploader is recorded next to it instead of a single owner.
+func (u mediaUsecase) Upload(ctx context.Context, userID int, r io.Reader) (*resp.MediaDto, error) {
+	const scope = "mediaUsecase#Upload"
+	data, err := io.ReadAll(io.LimitReader(r, maxMediaSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	if len(data) > maxMediaSize {
+		return nil, fmt.Errorf("%s: %w", scope, ErrMediaTooLarge.SetError(fmt.Errorf("(limit is %d bytes)", maxMediaSize)))
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%s: %w", scope, ErrBadMedia.SetError(errors.New("empty file")))
+	}
+	contentType := http.DetectContentType(data)
+	if !allowedMediaTypes[contentType] {
+		return nil, fmt.Errorf("%s: %w", scope, ErrBadMedia.SetError(fmt.Errorf("unsupported content type %s", contentType)))
+	}
+	sum := sha256.Sum256(data)
+	mediaID := hex.EncodeToString(sum[:])
+	existing, err := u.findMetadata(ctx, mediaID)
+	if err == nil {
+		if err := u.recordUploader(ctx, mediaID, userID); err != nil {
+			return nil, fmt.Errorf("%s: %w", scope, err)
+		}
+		logging.FromContext(ctx, u.logger).Info(
+			"Media already exists",
+			slog.String("scope", scope),
+			slog.String("media_id", mediaID),
+		)
+		return existing.toDto(), nil
+	}
+	if !errors.Is(err, &ErrMediaNotFound) {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	width, height, err := util.ProbeImage(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrBadMedia.SetError(err))
+	}
+	if width > maxMediaDimension || height > maxMediaDimension {
+		return nil, fmt.Errorf("%s: %w", scope, ErrBadMedia.SetError(fmt.Errorf("dimensions %dx%d exceed %dx%d", width, height, maxMediaDimension, maxMediaDimension)))
+	}
+	if err := u.put(ctx, mediaKey(mediaID, MediaVariantOriginal), data, contentType); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	if err := u.recordUploader(ctx, mediaID, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	metadata := mediaMetadata{
+		ID:          mediaID,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Width:       width,
+		Height:      height,
+		CreatedAt:   time.Now().UTC(),
+	}
+	// A missing thumbnail should not fail the upload, the original is still
+	// usable.
+	thumbnail, err := util.Thumbnail(data, thumbnailSide)
+	if err == nil {
+		err = u.put(ctx, mediaKey(mediaID, MediaVariantThumbnail), thumbnail, "image/jpeg")
+	}
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Warn(
+			"Failed to generate thumbnail",
+			slog.String("scope", scope),
+			slog.String("media_id", mediaID),
+			slog.Any("error", err),
+		)
+	} else {
+		metadata.HasThumbnail = true
+	}
+	// The metadata is written last, its presence marks the media complete.
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	if err := u.put(ctx, mediaKey(mediaID, "meta.json"), metadataJSON, "application/json"); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	logging.FromContext(ctx, u.logger).Info(
+		"Stored media",
+		slog.String("scope", scope),
+		slog.String("media_id", mediaID),
+		slog.String("content_type", contentType),
+	)
+	return metadata.toDto(), nil
+}
+
+func (u mediaUsecase) FindByID(ctx context.Context, mediaID string) (*resp.MediaDto, error) {
+	const scope = "mediaUsecase#FindByID"
+	metadata, err := u.findMetadata(ctx, mediaID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	return metadata.toDto(), nil
+}
+
+// FindUploadedByIDs finds media that userID uploaded. Media uploaded only by
+// others are not found, so a user cannot attach content they merely know the
+// hash of.
+func (u mediaUsecase) FindUploadedByIDs(ctx context.Context, userID int, mediaIDs []string) ([]*resp.MediaDto, error) {
+	const scope = "mediaUsecase#FindUploadedByIDs"
+	result := make([]*resp.MediaDto, 0, len(mediaIDs))
+	for _, mediaID := range mediaIDs {
+		metadata, err := u.findMetadata(ctx, mediaID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", scope, err)
+		}
+		uploaded, err := u.hasUploader(ctx, mediaID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", scope, err)
+		}
+		if !uploaded {
+			return nil, fmt.Errorf("%s: %w", scope, ErrMediaNotFound.SetError(fmt.Errorf("media %s not uploaded by user %d", mediaID, userID)))
+		}
+		result = append(result, metadata.toDto())
+	}
+	return result, nil
+}
+
+func (u mediaUsecase) Open(ctx context.Context, mediaID string, variant string) (io.ReadCloser, *resp.MediaDto, error) {
+	const scope = "mediaUsecase#Open"
+	metadata, err := u.findMetadata(ctx, mediaID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	if variant != MediaVariantOriginal && (variant != MediaVariantThumbnail || !metadata.HasThumbnail) {
+		return nil, nil, fmt.Errorf("%s: %w", scope, ErrMediaNotFound.SetError(fmt.Errorf("no %s variant", variant)))
+	}
+	r, err := u.blobStore.Get(ctx, mediaKey(mediaID, variant))
+	if err != nil {
+		if errors.Is(err, &blobstore.ErrBlobNotFound) {
+			return nil, nil, fmt.Errorf("%s: %w", scope, ErrMediaNotFound.SetError(err))
+		}
+		return nil, nil, fmt.Errorf("%s: %w", scope, ErrBlobStore.SetError(err))
+	}
+	dto := metadata.toDto()
+	if variant == MediaVariantThumbnail {
+		dto.ContentType = "image/jpeg"
+	}
+	return r, dto, nil
+}
+
+func (u mediaUsecase) findMetadata(ctx context.Context, mediaID string) (*mediaMetadata, error) {
+	if !mediaIDPattern.MatchString(mediaID) {
+		return nil, ErrMediaNotFound.SetError(fmt.Errorf("invalid media ID %q", mediaID))
+	}
+	r, err := u.blobStore.Get(ctx, mediaKey(mediaID, "meta.json"))
+	if err != nil {
+		if errors.Is(err, &blobstore.ErrBlobNotFound) {
+			return nil, ErrMediaNotFound.SetError(err)
+		}
+		return nil, ErrBlobStore.SetError(err)
+	}
+	defer r.Close()
+	metadata := &mediaMetadata{}
+	if err := json.NewDecoder(r).Decode(metadata); err != nil {
+		return nil, ErrBlobStore.SetError(err)
+	}
+	return metadata, nil
+}
+
+type mediaUploader struct {
+	UserID     int       `json:"user_id"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+func uploaderKey(mediaID string, userID int) string {
+	return mediaKey(mediaID, fmt.Sprintf("uploaders/%d.json", userID))
+}
+
+func (u mediaUsecase) hasUploader(ctx context.Context, mediaID string, userID int) (bool, error) {
+	r, err := u.blobStore.Get(ctx, uploaderKey(mediaID, userID))
+	if err != nil {
+		if errors.Is(err, &blobstore.ErrBlobNotFound) {
+			return false, nil
+		}
+		return false, ErrBlobStore.SetError(err)
+	}
+	r.Close()
+	return true, nil
+}
+
+// recordUploader keeps one record per user who uploaded the media, the first
+// upload of each user wins. The records decide who may attach the media.
+func (u mediaUsecase) recordUploader(ctx context.Context, mediaID string, userID int) error {
+	uploaded, err := u.hasUploader(ctx, mediaID, userID)
+	if err != nil || uploaded {
+		return err
+	}
+	uploaderJSON, err := json.Marshal(mediaUploader{UserID: userID, UploadedAt: time.Now().UTC()})
+	if err != nil {
+		return ErrUnknown.SetError(err)
+	}
+	return u.put(ctx, uploaderKey(mediaID, userID), uploaderJSON, "application/json")
+}
+
+func (u mediaUsecase) put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := u.blobStore.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return ErrBlobStore.SetError(err)
+	}
+	return nil
+}
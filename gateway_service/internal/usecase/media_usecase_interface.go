@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+	"gatewayservice/internal/dto/resp"
+	"io"
+)
+
+type IMediaUsecase interface {
+	Upload(ctx context.Context, userID int, r io.Reader) (*resp.MediaDto, error)
+	FindByID(ctx context.Context, mediaID string) (*resp.MediaDto, error)
+	FindUploadedByIDs(ctx context.Context, userID int, mediaIDs []string) ([]*resp.MediaDto, error)
+	Open(ctx context.Context, mediaID string, variant string) (io.ReadCloser, *resp.MediaDto, error)
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+
+	"gatewayservice/internal/blobstore/local"
+
+	"golang.org/x/exp/slog"
+)
+
+func TestFindUploadedByIDs(t *testing.T) {
+	blobStore, err := local.NewBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("blob store: %v", err)
+	}
+	u := NewMediaUsecase(slog.New(slog.NewTextHandler(io.Discard)), blobStore)
+	ctx := context.Background()
+	var image1, image2 bytes.Buffer
+	png.Encode(&image1, image.NewGray(image.Rect(0, 0, 2, 2)))
+	png.Encode(&image2, image.NewGray(image.Rect(0, 0, 3, 3)))
+	mine, err := u.Upload(ctx, 1, bytes.NewReader(image1.Bytes()))
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	theirs, err := u.Upload(ctx, 2, bytes.NewReader(image2.Bytes()))
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	// User 3 uploads the same file as user 2, which dedup turns into the
+	// same media.
+	shared, err := u.Upload(ctx, 3, bytes.NewReader(image2.Bytes()))
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if shared.ID != theirs.ID {
+		t.Fatalf("got media %s, want %s", shared.ID, theirs.ID)
+	}
+	tests := []struct {
+		name     string
+		userID   int
+		mediaIDs []string
+		notFound bool
+	}{
+		{name: "none", userID: 1, mediaIDs: nil},
+		{name: "own upload", userID: 1, mediaIDs: []string{mine.ID}},
+		{name: "another user's upload", userID: 1, mediaIDs: []string{mine.ID, theirs.ID}, notFound: true},
+		{name: "same file uploaded again", userID: 3, mediaIDs: []string{theirs.ID}},
+		{name: "never uploaded", userID: 1, mediaIDs: []string{"0000000000000000000000000000000000000000000000000000000000000000"}, notFound: true},
+		{name: "not an ID", userID: 1, mediaIDs: []string{"../meta"}, notFound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			media, err := u.FindUploadedByIDs(ctx, tt.userID, tt.mediaIDs)
+			if tt.notFound {
+				if !errors.Is(err, &ErrMediaNotFound) {
+					t.Fatalf("got %v, want not found", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("find: %v", err)
+			}
+			if len(media) != len(tt.mediaIDs) {
+				t.Fatalf("got %d media, want %d", len(media), len(tt.mediaIDs))
+			}
+		})
+	}
+}
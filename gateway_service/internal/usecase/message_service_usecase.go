@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"gatewayservice/internal/dto/req"
+	"gatewayservice/internal/logging"
+	"gatewayservice/internal/pubsub"
+	"gatewayservice/internal/util"
+
+	"github.com/go-playground/validator/v10"
+	messagePb "github.com/ideaspaper/social-media-proto/message"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc/metadata"
+)
+
+type messageServiceUsecase struct {
+	logger               *slog.Logger
+	validate             *validator.Validate
+	messageServiceClient messagePb.MessageServiceClient
+	pubSub               pubsub.IPubSub
+	mediaUsecase         IMediaUsecase
+}
+
+func NewMessageServiceUsecase(logger *slog.Logger, validate *validator.Validate, messageServiceClient messagePb.MessageServiceClient, pubSub pubsub.IPubSub, mediaUsecase IMediaUsecase) IMessageServiceUsecase {
+	return &messageServiceUsecase{
+		logger:               logger,
+		validate:             validate,
+		messageServiceClient: messageServiceClient,
+		pubSub:               pubSub,
+		mediaUsecase:         mediaUsecase,
+	}
+}
+
+// notify pushes an event to the given users. Delivery is best effort, so
+// failures are only logged.
+func (u messageServiceUsecase) notify(ctx context.Context, userIDs []int64, eventType string, data interface{}) {
+	const scope = "messageServiceUsecase#notify"
+	for _, userID := range userIDs {
+		if _, err := u.pubSub.Publish(ctx, int(userID), eventType, data); err != nil {
+			logging.FromContext(ctx, u.logger).Warn(
+				"Failed to publish event",
+				slog.String("scope", scope),
+				slog.String("event_type", eventType),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+func (u messageServiceUsecase) CreateConversation(ctx context.Context, userID int, conversationDto *req.ConversationDto) (*messagePb.CreateConversationResp, error) {
+	const scope = "messageServiceUsecase#CreateConversation"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, conversationDto); err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	participantIDs := make([]int64, 0, len(conversationDto.ParticipantIDs))
+	for _, participantID := range conversationDto.ParticipantIDs {
+		participantIDs = append(participantIDs, int64(participantID))
+	}
+	response, err := u.messageServiceClient.CreateConversation(mdCtx, &messagePb.CreateConversationReq{
+		UserId:         int64(userID),
+		ParticipantIds: participantIDs,
+		Title:          conversationDto.Title,
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u messageServiceUsecase) FindConversations(ctx context.Context, userID int, pageDto *req.PageDto) (*messagePb.FindConversationsResp, error) {
+	const scope = "messageServiceUsecase#FindConversations"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	response, err := u.messageServiceClient.FindConversations(mdCtx, &messagePb.FindConversationsReq{
+		UserId: int64(userID),
+		Cursor: pageDto.Cursor,
+		Limit:  int32(pageDto.Limit),
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u messageServiceUsecase) FindConversationByID(ctx context.Context, userID int, conversationID int) (*messagePb.FindConversationByIDResp, error) {
+	const scope = "messageServiceUsecase#FindConversationByID"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	response, err := u.messageServiceClient.FindConversationByID(mdCtx, &messagePb.FindConversationByIDReq{
+		UserId: int64(userID),
+		Id:     int64(conversationID),
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u messageServiceUsecase) SendMessage(ctx context.Context, userID int, conversationID int, messageDto *req.MessageDto) (*messagePb.SendMessageResp, error) {
+	const scope = "messageServiceUsecase#SendMessage"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, messageDto); err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	// Attachments must be uploaded by the sender before they can be
+	// referenced.
+	if _, err := u.mediaUsecase.FindUploadedByIDs(ctx, userID, messageDto.MediaIDs); err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from media usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	response, err := u.messageServiceClient.SendMessage(mdCtx, &messagePb.SendMessageReq{
+		UserId:         int64(userID),
+		ConversationId: int64(conversationID),
+		Body:           messageDto.Body,
+		MediaIds:       messageDto.MediaIDs,
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	u.notify(ctx, response.GetRecipientIds(), pubsub.EventMessageCreated, response.GetMessageResp())
+	return response, nil
+}
+
+func (u messageServiceUsecase) FindMessages(ctx context.Context, userID int, conversationID int, pageDto *req.PageDto) (*messagePb.FindMessagesResp, error) {
+	const scope = "messageServiceUsecase#FindMessages"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	response, err := u.messageServiceClient.FindMessages(mdCtx, &messagePb.FindMessagesReq{
+		UserId:         int64(userID),
+		ConversationId: int64(conversationID),
+		Cursor:         pageDto.Cursor,
+		Limit:          int32(pageDto.Limit),
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u messageServiceUsecase) MarkRead(ctx context.Context, userID int, conversationID int, markReadDto *req.MarkReadDto) (*messagePb.MarkReadResp, error) {
+	const scope = "messageServiceUsecase#MarkRead"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, markReadDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	response, err := u.messageServiceClient.MarkRead(mdCtx, &messagePb.MarkReadReq{
+		UserId:         int64(userID),
+		ConversationId: int64(conversationID),
+		MessageId:      int64(markReadDto.MessageID),
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	otherParticipantIDs := []int64{}
+	for _, participantID := range response.GetConversationResp().GetParticipantIds() {
+		if participantID != int64(userID) {
+			otherParticipantIDs = append(otherParticipantIDs, participantID)
+		}
+	}
+	u.notify(ctx, otherParticipantIDs, pubsub.EventMessagesRead, map[string]interface{}{
+		"conversation_id": conversationID,
+		"user_id":         userID,
+		"message_id":      markReadDto.MessageID,
+	})
+	return response, nil
+}
+
+func (u messageServiceUsecase) CountUnread(ctx context.Context, userID int) (*messagePb.CountUnreadResp, error) {
+	const scope = "messageServiceUsecase#CountUnread"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	response, err := u.messageServiceClient.CountUnread(mdCtx, &messagePb.CountUnreadReq{
+		UserId: int64(userID),
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u messageServiceUsecase) UpdateMessagePermission(ctx context.Context, userID int, messagePermissionDto *req.MessagePermissionDto) (*messagePb.UpdateMessagePermissionResp, error) {
+	const scope = "messageServiceUsecase#UpdateMessagePermission"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, messagePermissionDto); err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	response, err := u.messageServiceClient.UpdateMessagePermission(mdCtx, &messagePb.UpdateMessagePermissionReq{
+		UserId:            int64(userID),
+		MessagePermission: messagePermissionDto.MessagePermission,
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"context"
+	"gatewayservice/internal/dto/req"
+
+	messagePb "github.com/ideaspaper/social-media-proto/message"
+)
+
+type IMessageServiceUsecase interface {
+	CreateConversation(ctx context.Context, userID int, conversationDto *req.ConversationDto) (*messagePb.CreateConversationResp, error)
+	FindConversations(ctx context.Context, userID int, pageDto *req.PageDto) (*messagePb.FindConversationsResp, error)
+	FindConversationByID(ctx context.Context, userID int, conversationID int) (*messagePb.FindConversationByIDResp, error)
+	SendMessage(ctx context.Context, userID int, conversationID int, messageDto *req.MessageDto) (*messagePb.SendMessageResp, error)
+	FindMessages(ctx context.Context, userID int, conversationID int, pageDto *req.PageDto) (*messagePb.FindMessagesResp, error)
+	MarkRead(ctx context.Context, userID int, conversationID int, markReadDto *req.MarkReadDto) (*messagePb.MarkReadResp, error)
+	CountUnread(ctx context.Context, userID int) (*messagePb.CountUnreadResp, error)
+	UpdateMessagePermission(ctx context.Context, userID int, messagePermissionDto *req.MessagePermissionDto) (*messagePb.UpdateMessagePermissionResp, error)
+}
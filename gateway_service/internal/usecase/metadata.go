@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"context"
+	"gatewayservice/internal/util"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UserMetadataInterceptor forwards the authenticated user ID so the services
+// can attach it to their request logs. It is informational only; services
+// take the user ID they act on from the request message.
+func UserMetadataInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if userID, ok := ctx.Value(util.UserID).(int); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, "user-id", strconv.Itoa(userID))
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
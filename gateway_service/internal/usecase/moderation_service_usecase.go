@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"gatewayservice/internal/dto/req"
+	"gatewayservice/internal/logging"
+	"gatewayservice/internal/pubsub"
+	"gatewayservice/internal/util"
+
+	"github.com/go-playground/validator/v10"
+	moderationPb "github.com/ideaspaper/social-media-proto/moderation"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc/metadata"
+)
+
+type moderationServiceUsecase struct {
+	logger                  *slog.Logger
+	validate                *validator.Validate
+	moderationServiceClient moderationPb.ModerationServiceClient
+	pubSub                  pubsub.IPubSub
+}
+
+func NewModerationServiceUsecase(logger *slog.Logger, validate *validator.Validate, moderationServiceClient moderationPb.ModerationServiceClient, pubSub pubsub.IPubSub) IModerationServiceUsecase {
+	return &moderationServiceUsecase{
+		logger:                  logger,
+		validate:                validate,
+		moderationServiceClient: moderationServiceClient,
+		pubSub:                  pubSub,
+	}
+}
+
+func (u moderationServiceUsecase) CreateReport(ctx context.Context, reporterID int, reportDto *req.ReportDto) (*moderationPb.CreateReportResp, error) {
+	const scope = "moderationServiceUsecase#CreateReport"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, reportDto); err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	response, err := u.moderationServiceClient.CreateReport(mdCtx, &moderationPb.CreateReportReq{
+		ReporterId: int64(reporterID),
+		TargetType: reportDto.TargetType,
+		TargetId:   int64(reportDto.TargetID),
+		ReasonCode: reportDto.ReasonCode,
+		Details:    reportDto.Details,
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u moderationServiceUsecase) FindReports(ctx context.Context, moderatorID int, reportFilterDto *req.ReportFilterDto, pageDto *req.PageDto) (*moderationPb.FindReportsResp, error) {
+	const scope = "moderationServiceUsecase#FindReports"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, reportFilterDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	if err := validateDto(u.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	response, err := u.moderationServiceClient.FindReports(mdCtx, &moderationPb.FindReportsReq{
+		ModeratorId: int64(moderatorID),
+		Status:      reportFilterDto.Status,
+		Cursor:      pageDto.Cursor,
+		Limit:       int32(pageDto.Limit),
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+func (u moderationServiceUsecase) FindReportByID(ctx context.Context, moderatorID int, reportID int) (*moderationPb.FindReportByIDResp, error) {
+	const scope = "moderationServiceUsecase#FindReportByID"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	response, err := u.moderationServiceClient.FindReportByID(mdCtx, &moderationPb.FindReportByIDReq{
+		ModeratorId: int64(moderatorID),
+		Id:          int64(reportID),
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
+
+// TakeAction tells the affected user about warnings and suspensions.
+// Delivery is best effort, so failures are only logged.
+func (u moderationServiceUsecase) TakeAction(ctx context.Context, moderatorID int, reportID int, moderationActionDto *req.ModerationActionDto) (*moderationPb.TakeActionResp, error) {
+	const scope = "moderationServiceUsecase#TakeAction"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, moderationActionDto); err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	takeActionReq := &moderationPb.TakeActionReq{
+		ModeratorId: int64(moderatorID),
+		ReportId:    int64(reportID),
+		Action:      moderationActionDto.Action,
+		Note:        moderationActionDto.Note,
+	}
+	if moderationActionDto.SuspendHours != nil {
+		suspendHours := int32(*moderationActionDto.SuspendHours)
+		takeActionReq.SuspendHours = &suspendHours
+	}
+	response, err := u.moderationServiceClient.TakeAction(mdCtx, takeActionReq)
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	action := response.GetModerationActionResp()
+	if action.TargetUserId != nil && (action.GetAction() == "warn" || action.GetAction() == "suspend") {
+		_, err := u.pubSub.Publish(ctx, int(action.GetTargetUserId()), pubsub.EventNotification, map[string]interface{}{
+			"type":            "moderation." + action.GetAction(),
+			"target_type":     action.GetTargetType(),
+			"target_id":       action.GetTargetId(),
+			"note":            action.Note,
+			"suspended_until": action.SuspendedUntil,
+		})
+		if err != nil {
+			logging.FromContext(ctx, u.logger).Warn(
+				"Failed to publish event",
+				slog.String("scope", scope),
+				slog.String("event_type", pubsub.EventNotification),
+				slog.Any("error", err),
+			)
+		}
+	}
+	return response, nil
+}
+
+func (u moderationServiceUsecase) FindActions(ctx context.Context, moderatorID int, pageDto *req.PageDto) (*moderationPb.FindActionsResp, error) {
+	const scope = "moderationServiceUsecase#FindActions"
+	requestID := ctx.Value(util.RequestID).(string)
+	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
+	if err := validateDto(u.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	response, err := u.moderationServiceClient.FindActions(mdCtx, &moderationPb.FindActionsReq{
+		ModeratorId: int64(moderatorID),
+		Cursor:      pageDto.Cursor,
+		Limit:       int32(pageDto.Limit),
+	})
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from service",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+	}
+	return response, nil
+}
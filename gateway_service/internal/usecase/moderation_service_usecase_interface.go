@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+	"gatewayservice/internal/dto/req"
+
+	moderationPb "github.com/ideaspaper/social-media-proto/moderation"
+)
+
+type IModerationServiceUsecase interface {
+	CreateReport(ctx context.Context, reporterID int, reportDto *req.ReportDto) (*moderationPb.CreateReportResp, error)
+	FindReports(ctx context.Context, moderatorID int, reportFilterDto *req.ReportFilterDto, pageDto *req.PageDto) (*moderationPb.FindReportsResp, error)
+	FindReportByID(ctx context.Context, moderatorID int, reportID int) (*moderationPb.FindReportByIDResp, error)
+	TakeAction(ctx context.Context, moderatorID int, reportID int, moderationActionDto *req.ModerationActionDto) (*moderationPb.TakeActionResp, error)
+	FindActions(ctx context.Context, moderatorID int, pageDto *req.PageDto) (*moderationPb.FindActionsResp, error)
+}
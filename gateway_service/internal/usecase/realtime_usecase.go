@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"gatewayservice/internal/logging"
+	"gatewayservice/internal/pubsub"
+
+	"golang.org/x/exp/slog"
+)
+
+type realtimeUsecase struct {
+	logger *slog.Logger
+	pubSub pubsub.IPubSub
+}
+
+func NewRealtimeUsecase(logger *slog.Logger, pubSub pubsub.IPubSub) IRealtimeUsecase {
+	return &realtimeUsecase{
+		logger: logger,
+		pubSub: pubSub,
+	}
+}
+
+func (u realtimeUsecase) Subscribe(ctx context.Context, userID int, lastEventID string) (pubsub.ISubscription, error) {
+	const scope = "realtimeUsecase#Subscribe"
+	subscription, err := u.pubSub.Subscribe(ctx, userID, lastEventID)
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from pub/sub",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrPubSub.SetError(err))
+	}
+	logging.FromContext(ctx, u.logger).Info(
+		"Subscribed to user events",
+		slog.String("scope", scope),
+		slog.Int("user_id", userID),
+		slog.String("last_event_id", lastEventID),
+	)
+	return subscription, nil
+}
+
+func (u realtimeUsecase) Publish(ctx context.Context, userID int, eventType string, data interface{}) error {
+	const scope = "realtimeUsecase#Publish"
+	event, err := u.pubSub.Publish(ctx, userID, eventType, data)
+	if err != nil {
+		logging.FromContext(ctx, u.logger).Error(
+			"Got error from pub/sub",
+			err,
+			slog.String("scope", scope),
+		)
+		return fmt.Errorf("%s: %w", scope, ErrPubSub.SetError(err))
+	}
+	logging.FromContext(ctx, u.logger).Debug(
+		"Published user event",
+		slog.String("scope", scope),
+		slog.Int("user_id", userID),
+		slog.String("event_id", event.ID),
+		slog.String("event_type", eventType),
+	)
+	return nil
+}
@@ -0,0 +1,11 @@
+package usecase
+
+import (
+	"context"
+	"gatewayservice/internal/pubsub"
+)
+
+type IRealtimeUsecase interface {
+	Subscribe(ctx context.Context, userID int, lastEventID string) (pubsub.ISubscription, error)
+	Publish(ctx context.Context, userID int, eventType string, data interface{}) error
+}
@@ -6,60 +6,87 @@ import (
 	"fmt"
 	"gatewayservice/internal/dto/req"
 	"gatewayservice/internal/dto/resp"
+	"gatewayservice/internal/logging"
 	"gatewayservice/internal/util"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
 	userPb "github.com/ideaspaper/social-media-proto/user"
 	"golang.org/x/exp/slog"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type userServiceUsecase struct {
 	logger            *slog.Logger
 	validate          *validator.Validate
 	userServiceClient userPb.UserServiceClient
+	jwt               *util.Jwt
 }
 
-func NewUserServiceUsecase(logger *slog.Logger, validate *validator.Validate, userServiceClient userPb.UserServiceClient) IUserServiceUsecase {
+func NewUserServiceUsecase(logger *slog.Logger, validate *validator.Validate, userServiceClient userPb.UserServiceClient, jwt *util.Jwt) IUserServiceUsecase {
 	return &userServiceUsecase{
 		logger:            logger,
 		validate:          validate,
 		userServiceClient: userServiceClient,
+		jwt:               jwt,
 	}
 }
 
-func (u userServiceUsecase) FindUserByID(ctx context.Context, userID int) (*userPb.FindByIDResp, error) {
-	const scope = "userServiceUsecase#FindUserByID"
+// FindUsersByIDs asks the user service for every ID in one call. An ID with
+// no user gets the NotFound error FindByID would have returned for it.
+func (u userServiceUsecase) FindUsersByIDs(ctx context.Context, userIDs []int64) ([]*userPb.UserResp, []error) {
+	const scope = "userServiceUsecase#FindUsersByIDs"
 	requestID := ctx.Value(util.RequestID).(string)
 	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
-	response, err := u.userServiceClient.FindByID(mdCtx, &userPb.FindByIDReq{
-		Id: int64(userID),
+	users := make([]*userPb.UserResp, len(userIDs))
+	errs := make([]error, len(userIDs))
+	response, err := u.userServiceClient.FindByIDs(mdCtx, &userPb.FindByIDsReq{
+		Ids: userIDs,
 	})
 	if err != nil {
-		u.logger.Error(
+		logging.FromContext(ctx, u.logger).Error(
 			"Got error from service",
 			err,
-			slog.String("request_id", requestID),
 			slog.String("scope", scope),
 		)
-		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+		err = fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
+		for i := range errs {
+			errs[i] = err
+		}
+		return users, errs
 	}
-	return response, nil
+	usersByID := map[int64]*userPb.UserResp{}
+	for _, user := range response.GetUsers() {
+		usersByID[user.GetId()] = user
+	}
+	for i, userID := range userIDs {
+		if user, ok := usersByID[userID]; ok {
+			users[i] = user
+			continue
+		}
+		// Each ID gets its own copy, SetError on ErrClientService itself
+		// would leave every ID with the last error.
+		clientServiceErr := ErrClientService
+		errs[i] = fmt.Errorf("%s: %w", scope, clientServiceErr.SetError(status.Error(codes.NotFound, "User not found")))
+	}
+	return users, errs
 }
 
 func (u userServiceUsecase) DeleteUserByID(ctx context.Context, userID int) (*userPb.DeleteByIDResp, error) {
 	const scope = "userServiceUsecase#DeleteUserByID"
 	requestID := ctx.Value(util.RequestID).(string)
+	callerID, _ := ctx.Value(util.UserID).(int)
 	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
 	response, err := u.userServiceClient.DeleteByID(mdCtx, &userPb.DeleteByIDReq{
-		Id: int64(userID),
+		Id:       int64(userID),
+		CallerId: int64(callerID),
 	})
 	if err != nil {
-		u.logger.Error(
+		logging.FromContext(ctx, u.logger).Error(
 			"Got error from service",
 			err,
-			slog.String("request_id", requestID),
 			slog.String("scope", scope),
 		)
 		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
@@ -70,15 +97,16 @@ func (u userServiceUsecase) DeleteUserByID(ctx context.Context, userID int) (*us
 func (u userServiceUsecase) DeleteUserPermanentlyByID(ctx context.Context, userID int) (*userPb.DeletePermanentlyByIDResp, error) {
 	const scope = "userServiceUsecase#DeleteUserPermanentlyByID"
 	requestID := ctx.Value(util.RequestID).(string)
+	callerID, _ := ctx.Value(util.UserID).(int)
 	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
 	response, err := u.userServiceClient.DeletePermanentlyByID(mdCtx, &userPb.DeletePermanentlyByIDReq{
-		Id: int64(userID),
+		Id:       int64(userID),
+		CallerId: int64(callerID),
 	})
 	if err != nil {
-		u.logger.Error(
+		logging.FromContext(ctx, u.logger).Error(
 			"Got error from service",
 			err,
-			slog.String("request_id", requestID),
 			slog.String("scope", scope),
 		)
 		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
@@ -92,10 +120,9 @@ func (u userServiceUsecase) Register(ctx context.Context, userDto *req.UserDto)
 	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
 	err := u.validate.Struct(userDto)
 	if err != nil {
-		u.logger.Error(
+		logging.FromContext(ctx, u.logger).Error(
 			"Failed to validate input",
 			err,
-			slog.String("request_id", requestID),
 			slog.String("scope", scope),
 		)
 		validatorErrors := err.(validator.ValidationErrors)
@@ -112,10 +139,9 @@ func (u userServiceUsecase) Register(ctx context.Context, userDto *req.UserDto)
 		LastName:  userDto.LastName,
 	})
 	if err != nil {
-		u.logger.Error(
+		logging.FromContext(ctx, u.logger).Error(
 			"Got error from service",
 			err,
-			slog.String("request_id", requestID),
 			slog.String("scope", scope),
 		)
 		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
@@ -129,10 +155,9 @@ func (u userServiceUsecase) Login(ctx context.Context, loginDto *req.LoginDto) (
 	mdCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("request-id", requestID))
 	err := u.validate.Struct(loginDto)
 	if err != nil {
-		u.logger.Error(
+		logging.FromContext(ctx, u.logger).Error(
 			"Failed to validate input",
 			err,
-			slog.String("request_id", requestID),
 			slog.String("scope", scope),
 		)
 		validatorErrors := err.(validator.ValidationErrors)
@@ -147,15 +172,14 @@ func (u userServiceUsecase) Login(ctx context.Context, loginDto *req.LoginDto) (
 		Password: loginDto.Password,
 	})
 	if err != nil {
-		u.logger.Error(
+		logging.FromContext(ctx, u.logger).Error(
 			"Got error from service",
 			err,
-			slog.String("request_id", requestID),
 			slog.String("scope", scope),
 		)
 		return nil, fmt.Errorf("%s: %w", scope, ErrClientService.SetError(err))
 	}
-	ss, err := util.GenerateSignedJwt(int(response.GetId()), response.GetEmail())
+	ss, err := u.jwt.GenerateSigned(int(response.GetId()), response.GetEmail(), response.GetRole())
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", scope, ErrFailSigningJWT.SetError(err))
 	}
@@ -9,7 +9,8 @@ import (
 )
 
 type IUserServiceUsecase interface {
-	FindUserByID(ctx context.Context, userID int) (*userPb.FindByIDResp, error)
+	// FindUsersByIDs returns a user or an error for every ID, in order.
+	FindUsersByIDs(ctx context.Context, userIDs []int64) ([]*userPb.UserResp, []error)
 	DeleteUserByID(ctx context.Context, userID int) (*userPb.DeleteByIDResp, error)
 	DeleteUserPermanentlyByID(ctx context.Context, userID int) (*userPb.DeletePermanentlyByIDResp, error)
 	Register(ctx context.Context, registerDto *req.UserDto) (*userPb.RegisterResp, error)
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type validatable interface {
+	ErrorMessages(field, tag string) string
+}
+
+// validateDto joins the messages of every rule the dto fails.
+func validateDto(validate *validator.Validate, dto validatable) error {
+	err := validate.Struct(dto)
+	if err == nil {
+		return nil
+	}
+	validatorErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+	errorMessages := []string{}
+	for _, validatorError := range validatorErrors {
+		errorMessages = append(errorMessages, dto.ErrorMessages(validatorError.Field(), validatorError.Tag()))
+	}
+	return errors.New(strings.Join(errorMessages, ", "))
+}
@@ -4,4 +4,8 @@ package util
 
 const (
 	RequestID string = "request-id"
+	UserID    string = "user-id"
+	UserEmail string = "user-email"
+	UserRole  string = "user-role"
+	ClientIP  string = "client-ip"
 )
@@ -1,34 +1,62 @@
 package util
 
 import (
-	"errors"
 	"fmt"
 	"gatewayservice/internal/dto/resp"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GenerateSignedJwt(userID int, userEmail string) (string, error) {
-	const scope = "helper#GenerateSignedJwt"
-	jwtExpiresAt, err := time.ParseDuration(os.Getenv("JWT_EXPIRES_AT"))
-	if err != nil {
-		return "", fmt.Errorf("%s: %w", scope, errors.New("invalid environment variable"))
+type Jwt struct {
+	secret    []byte
+	issuer    string
+	expiresIn time.Duration
+}
+
+func NewJwt(secret string, issuer string, expiresIn time.Duration) *Jwt {
+	return &Jwt{
+		secret:    []byte(secret),
+		issuer:    issuer,
+		expiresIn: expiresIn,
 	}
+}
+
+func (j Jwt) GenerateSigned(userID int, userEmail string, userRole string) (string, error) {
+	const scope = "jwt#GenerateSigned"
+	now := time.Now()
 	claims := &resp.JwtClaimsDto{
 		ID:    userID,
 		Email: userEmail,
+		Role:  userRole,
 		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    os.Getenv("APP_NAME"),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(jwtExpiresAt)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    j.issuer,
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.expiresIn)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	ss, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	ss, err := token.SignedString(j.secret)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", scope, err)
 	}
 	return ss, nil
 }
+
+func (j Jwt) ParseSigned(signedJwt string) (*resp.JwtClaimsDto, error) {
+	const scope = "jwt#ParseSigned"
+	claims := &resp.JwtClaimsDto{}
+	_, err := jwt.ParseWithClaims(
+		signedJwt,
+		claims,
+		func(token *jwt.Token) (interface{}, error) {
+			return j.secret, nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(j.issuer),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,18 @@
+package util
+
+import (
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._~:=+/-]{1,128}$`)
+
+// RequestIDOrNew returns id when it is safe to log and forward, which covers
+// UUIDs and the IDs common proxies generate, or a fresh UUID otherwise.
+func RequestIDOrNew(id string) string {
+	if requestIDPattern.MatchString(id) {
+		return id
+	}
+	return uuid.New().String()
+}
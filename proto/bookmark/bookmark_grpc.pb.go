@@ -0,0 +1,357 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: bookmark/bookmark.proto
+
+package bookmark
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// BookmarkServiceClient is the client API for BookmarkService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BookmarkServiceClient interface {
+	CreateCollection(ctx context.Context, in *CreateCollectionReq, opts ...grpc.CallOption) (*CreateCollectionResp, error)
+	UpdateCollection(ctx context.Context, in *UpdateCollectionReq, opts ...grpc.CallOption) (*UpdateCollectionResp, error)
+	DeleteCollection(ctx context.Context, in *DeleteCollectionReq, opts ...grpc.CallOption) (*DeleteCollectionResp, error)
+	FindCollections(ctx context.Context, in *FindCollectionsReq, opts ...grpc.CallOption) (*FindCollectionsResp, error)
+	AddBookmark(ctx context.Context, in *AddBookmarkReq, opts ...grpc.CallOption) (*AddBookmarkResp, error)
+	RemoveBookmark(ctx context.Context, in *RemoveBookmarkReq, opts ...grpc.CallOption) (*RemoveBookmarkResp, error)
+	FindBookmarks(ctx context.Context, in *FindBookmarksReq, opts ...grpc.CallOption) (*FindBookmarksResp, error)
+	FindCollectionBookmarks(ctx context.Context, in *FindCollectionBookmarksReq, opts ...grpc.CallOption) (*FindCollectionBookmarksResp, error)
+}
+
+type bookmarkServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBookmarkServiceClient(cc grpc.ClientConnInterface) BookmarkServiceClient {
+	return &bookmarkServiceClient{cc}
+}
+
+func (c *bookmarkServiceClient) CreateCollection(ctx context.Context, in *CreateCollectionReq, opts ...grpc.CallOption) (*CreateCollectionResp, error) {
+	out := new(CreateCollectionResp)
+	err := c.cc.Invoke(ctx, "/bookmark.BookmarkService/CreateCollection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookmarkServiceClient) UpdateCollection(ctx context.Context, in *UpdateCollectionReq, opts ...grpc.CallOption) (*UpdateCollectionResp, error) {
+	out := new(UpdateCollectionResp)
+	err := c.cc.Invoke(ctx, "/bookmark.BookmarkService/UpdateCollection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookmarkServiceClient) DeleteCollection(ctx context.Context, in *DeleteCollectionReq, opts ...grpc.CallOption) (*DeleteCollectionResp, error) {
+	out := new(DeleteCollectionResp)
+	err := c.cc.Invoke(ctx, "/bookmark.BookmarkService/DeleteCollection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookmarkServiceClient) FindCollections(ctx context.Context, in *FindCollectionsReq, opts ...grpc.CallOption) (*FindCollectionsResp, error) {
+	out := new(FindCollectionsResp)
+	err := c.cc.Invoke(ctx, "/bookmark.BookmarkService/FindCollections", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookmarkServiceClient) AddBookmark(ctx context.Context, in *AddBookmarkReq, opts ...grpc.CallOption) (*AddBookmarkResp, error) {
+	out := new(AddBookmarkResp)
+	err := c.cc.Invoke(ctx, "/bookmark.BookmarkService/AddBookmark", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookmarkServiceClient) RemoveBookmark(ctx context.Context, in *RemoveBookmarkReq, opts ...grpc.CallOption) (*RemoveBookmarkResp, error) {
+	out := new(RemoveBookmarkResp)
+	err := c.cc.Invoke(ctx, "/bookmark.BookmarkService/RemoveBookmark", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookmarkServiceClient) FindBookmarks(ctx context.Context, in *FindBookmarksReq, opts ...grpc.CallOption) (*FindBookmarksResp, error) {
+	out := new(FindBookmarksResp)
+	err := c.cc.Invoke(ctx, "/bookmark.BookmarkService/FindBookmarks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookmarkServiceClient) FindCollectionBookmarks(ctx context.Context, in *FindCollectionBookmarksReq, opts ...grpc.CallOption) (*FindCollectionBookmarksResp, error) {
+	out := new(FindCollectionBookmarksResp)
+	err := c.cc.Invoke(ctx, "/bookmark.BookmarkService/FindCollectionBookmarks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BookmarkServiceServer is the server API for BookmarkService service.
+// All implementations must embed UnimplementedBookmarkServiceServer
+// for forward compatibility
+type BookmarkServiceServer interface {
+	CreateCollection(context.Context, *CreateCollectionReq) (*CreateCollectionResp, error)
+	UpdateCollection(context.Context, *UpdateCollectionReq) (*UpdateCollectionResp, error)
+	DeleteCollection(context.Context, *DeleteCollectionReq) (*DeleteCollectionResp, error)
+	FindCollections(context.Context, *FindCollectionsReq) (*FindCollectionsResp, error)
+	AddBookmark(context.Context, *AddBookmarkReq) (*AddBookmarkResp, error)
+	RemoveBookmark(context.Context, *RemoveBookmarkReq) (*RemoveBookmarkResp, error)
+	FindBookmarks(context.Context, *FindBookmarksReq) (*FindBookmarksResp, error)
+	FindCollectionBookmarks(context.Context, *FindCollectionBookmarksReq) (*FindCollectionBookmarksResp, error)
+	mustEmbedUnimplementedBookmarkServiceServer()
+}
+
+// UnimplementedBookmarkServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBookmarkServiceServer struct {
+}
+
+func (UnimplementedBookmarkServiceServer) CreateCollection(context.Context, *CreateCollectionReq) (*CreateCollectionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCollection not implemented")
+}
+func (UnimplementedBookmarkServiceServer) UpdateCollection(context.Context, *UpdateCollectionReq) (*UpdateCollectionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCollection not implemented")
+}
+func (UnimplementedBookmarkServiceServer) DeleteCollection(context.Context, *DeleteCollectionReq) (*DeleteCollectionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCollection not implemented")
+}
+func (UnimplementedBookmarkServiceServer) FindCollections(context.Context, *FindCollectionsReq) (*FindCollectionsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindCollections not implemented")
+}
+func (UnimplementedBookmarkServiceServer) AddBookmark(context.Context, *AddBookmarkReq) (*AddBookmarkResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBookmark not implemented")
+}
+func (UnimplementedBookmarkServiceServer) RemoveBookmark(context.Context, *RemoveBookmarkReq) (*RemoveBookmarkResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveBookmark not implemented")
+}
+func (UnimplementedBookmarkServiceServer) FindBookmarks(context.Context, *FindBookmarksReq) (*FindBookmarksResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindBookmarks not implemented")
+}
+func (UnimplementedBookmarkServiceServer) FindCollectionBookmarks(context.Context, *FindCollectionBookmarksReq) (*FindCollectionBookmarksResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindCollectionBookmarks not implemented")
+}
+func (UnimplementedBookmarkServiceServer) mustEmbedUnimplementedBookmarkServiceServer() {}
+
+// UnsafeBookmarkServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BookmarkServiceServer will
+// result in compilation errors.
+type UnsafeBookmarkServiceServer interface {
+	mustEmbedUnimplementedBookmarkServiceServer()
+}
+
+func RegisterBookmarkServiceServer(s grpc.ServiceRegistrar, srv BookmarkServiceServer) {
+	s.RegisterService(&BookmarkService_ServiceDesc, srv)
+}
+
+func _BookmarkService_CreateCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCollectionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).CreateCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bookmark.BookmarkService/CreateCollection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).CreateCollection(ctx, req.(*CreateCollectionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookmarkService_UpdateCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCollectionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).UpdateCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bookmark.BookmarkService/UpdateCollection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).UpdateCollection(ctx, req.(*UpdateCollectionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookmarkService_DeleteCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCollectionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).DeleteCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bookmark.BookmarkService/DeleteCollection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).DeleteCollection(ctx, req.(*DeleteCollectionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookmarkService_FindCollections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindCollectionsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).FindCollections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bookmark.BookmarkService/FindCollections",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).FindCollections(ctx, req.(*FindCollectionsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookmarkService_AddBookmark_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddBookmarkReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).AddBookmark(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bookmark.BookmarkService/AddBookmark",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).AddBookmark(ctx, req.(*AddBookmarkReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookmarkService_RemoveBookmark_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveBookmarkReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).RemoveBookmark(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bookmark.BookmarkService/RemoveBookmark",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).RemoveBookmark(ctx, req.(*RemoveBookmarkReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookmarkService_FindBookmarks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindBookmarksReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).FindBookmarks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bookmark.BookmarkService/FindBookmarks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).FindBookmarks(ctx, req.(*FindBookmarksReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookmarkService_FindCollectionBookmarks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindCollectionBookmarksReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).FindCollectionBookmarks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bookmark.BookmarkService/FindCollectionBookmarks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).FindCollectionBookmarks(ctx, req.(*FindCollectionBookmarksReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BookmarkService_ServiceDesc is the grpc.ServiceDesc for BookmarkService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BookmarkService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bookmark.BookmarkService",
+	HandlerType: (*BookmarkServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateCollection",
+			Handler:    _BookmarkService_CreateCollection_Handler,
+		},
+		{
+			MethodName: "UpdateCollection",
+			Handler:    _BookmarkService_UpdateCollection_Handler,
+		},
+		{
+			MethodName: "DeleteCollection",
+			Handler:    _BookmarkService_DeleteCollection_Handler,
+		},
+		{
+			MethodName: "FindCollections",
+			Handler:    _BookmarkService_FindCollections_Handler,
+		},
+		{
+			MethodName: "AddBookmark",
+			Handler:    _BookmarkService_AddBookmark_Handler,
+		},
+		{
+			MethodName: "RemoveBookmark",
+			Handler:    _BookmarkService_RemoveBookmark_Handler,
+		},
+		{
+			MethodName: "FindBookmarks",
+			Handler:    _BookmarkService_FindBookmarks_Handler,
+		},
+		{
+			MethodName: "FindCollectionBookmarks",
+			Handler:    _BookmarkService_FindCollectionBookmarks_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "bookmark/bookmark.proto",
+}
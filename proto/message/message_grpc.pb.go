@@ -0,0 +1,357 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: message/message.proto
+
+package message
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// MessageServiceClient is the client API for MessageService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MessageServiceClient interface {
+	CreateConversation(ctx context.Context, in *CreateConversationReq, opts ...grpc.CallOption) (*CreateConversationResp, error)
+	FindConversations(ctx context.Context, in *FindConversationsReq, opts ...grpc.CallOption) (*FindConversationsResp, error)
+	FindConversationByID(ctx context.Context, in *FindConversationByIDReq, opts ...grpc.CallOption) (*FindConversationByIDResp, error)
+	SendMessage(ctx context.Context, in *SendMessageReq, opts ...grpc.CallOption) (*SendMessageResp, error)
+	FindMessages(ctx context.Context, in *FindMessagesReq, opts ...grpc.CallOption) (*FindMessagesResp, error)
+	MarkRead(ctx context.Context, in *MarkReadReq, opts ...grpc.CallOption) (*MarkReadResp, error)
+	CountUnread(ctx context.Context, in *CountUnreadReq, opts ...grpc.CallOption) (*CountUnreadResp, error)
+	UpdateMessagePermission(ctx context.Context, in *UpdateMessagePermissionReq, opts ...grpc.CallOption) (*UpdateMessagePermissionResp, error)
+}
+
+type messageServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMessageServiceClient(cc grpc.ClientConnInterface) MessageServiceClient {
+	return &messageServiceClient{cc}
+}
+
+func (c *messageServiceClient) CreateConversation(ctx context.Context, in *CreateConversationReq, opts ...grpc.CallOption) (*CreateConversationResp, error) {
+	out := new(CreateConversationResp)
+	err := c.cc.Invoke(ctx, "/message.MessageService/CreateConversation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messageServiceClient) FindConversations(ctx context.Context, in *FindConversationsReq, opts ...grpc.CallOption) (*FindConversationsResp, error) {
+	out := new(FindConversationsResp)
+	err := c.cc.Invoke(ctx, "/message.MessageService/FindConversations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messageServiceClient) FindConversationByID(ctx context.Context, in *FindConversationByIDReq, opts ...grpc.CallOption) (*FindConversationByIDResp, error) {
+	out := new(FindConversationByIDResp)
+	err := c.cc.Invoke(ctx, "/message.MessageService/FindConversationByID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messageServiceClient) SendMessage(ctx context.Context, in *SendMessageReq, opts ...grpc.CallOption) (*SendMessageResp, error) {
+	out := new(SendMessageResp)
+	err := c.cc.Invoke(ctx, "/message.MessageService/SendMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messageServiceClient) FindMessages(ctx context.Context, in *FindMessagesReq, opts ...grpc.CallOption) (*FindMessagesResp, error) {
+	out := new(FindMessagesResp)
+	err := c.cc.Invoke(ctx, "/message.MessageService/FindMessages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messageServiceClient) MarkRead(ctx context.Context, in *MarkReadReq, opts ...grpc.CallOption) (*MarkReadResp, error) {
+	out := new(MarkReadResp)
+	err := c.cc.Invoke(ctx, "/message.MessageService/MarkRead", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messageServiceClient) CountUnread(ctx context.Context, in *CountUnreadReq, opts ...grpc.CallOption) (*CountUnreadResp, error) {
+	out := new(CountUnreadResp)
+	err := c.cc.Invoke(ctx, "/message.MessageService/CountUnread", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messageServiceClient) UpdateMessagePermission(ctx context.Context, in *UpdateMessagePermissionReq, opts ...grpc.CallOption) (*UpdateMessagePermissionResp, error) {
+	out := new(UpdateMessagePermissionResp)
+	err := c.cc.Invoke(ctx, "/message.MessageService/UpdateMessagePermission", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MessageServiceServer is the server API for MessageService service.
+// All implementations must embed UnimplementedMessageServiceServer
+// for forward compatibility
+type MessageServiceServer interface {
+	CreateConversation(context.Context, *CreateConversationReq) (*CreateConversationResp, error)
+	FindConversations(context.Context, *FindConversationsReq) (*FindConversationsResp, error)
+	FindConversationByID(context.Context, *FindConversationByIDReq) (*FindConversationByIDResp, error)
+	SendMessage(context.Context, *SendMessageReq) (*SendMessageResp, error)
+	FindMessages(context.Context, *FindMessagesReq) (*FindMessagesResp, error)
+	MarkRead(context.Context, *MarkReadReq) (*MarkReadResp, error)
+	CountUnread(context.Context, *CountUnreadReq) (*CountUnreadResp, error)
+	UpdateMessagePermission(context.Context, *UpdateMessagePermissionReq) (*UpdateMessagePermissionResp, error)
+	mustEmbedUnimplementedMessageServiceServer()
+}
+
+// UnimplementedMessageServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMessageServiceServer struct {
+}
+
+func (UnimplementedMessageServiceServer) CreateConversation(context.Context, *CreateConversationReq) (*CreateConversationResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateConversation not implemented")
+}
+func (UnimplementedMessageServiceServer) FindConversations(context.Context, *FindConversationsReq) (*FindConversationsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindConversations not implemented")
+}
+func (UnimplementedMessageServiceServer) FindConversationByID(context.Context, *FindConversationByIDReq) (*FindConversationByIDResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindConversationByID not implemented")
+}
+func (UnimplementedMessageServiceServer) SendMessage(context.Context, *SendMessageReq) (*SendMessageResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendMessage not implemented")
+}
+func (UnimplementedMessageServiceServer) FindMessages(context.Context, *FindMessagesReq) (*FindMessagesResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindMessages not implemented")
+}
+func (UnimplementedMessageServiceServer) MarkRead(context.Context, *MarkReadReq) (*MarkReadResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkRead not implemented")
+}
+func (UnimplementedMessageServiceServer) CountUnread(context.Context, *CountUnreadReq) (*CountUnreadResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountUnread not implemented")
+}
+func (UnimplementedMessageServiceServer) UpdateMessagePermission(context.Context, *UpdateMessagePermissionReq) (*UpdateMessagePermissionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMessagePermission not implemented")
+}
+func (UnimplementedMessageServiceServer) mustEmbedUnimplementedMessageServiceServer() {}
+
+// UnsafeMessageServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MessageServiceServer will
+// result in compilation errors.
+type UnsafeMessageServiceServer interface {
+	mustEmbedUnimplementedMessageServiceServer()
+}
+
+func RegisterMessageServiceServer(s grpc.ServiceRegistrar, srv MessageServiceServer) {
+	s.RegisterService(&MessageService_ServiceDesc, srv)
+}
+
+func _MessageService_CreateConversation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateConversationReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessageServiceServer).CreateConversation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/message.MessageService/CreateConversation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessageServiceServer).CreateConversation(ctx, req.(*CreateConversationReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessageService_FindConversations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindConversationsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessageServiceServer).FindConversations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/message.MessageService/FindConversations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessageServiceServer).FindConversations(ctx, req.(*FindConversationsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessageService_FindConversationByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindConversationByIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessageServiceServer).FindConversationByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/message.MessageService/FindConversationByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessageServiceServer).FindConversationByID(ctx, req.(*FindConversationByIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessageService_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendMessageReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessageServiceServer).SendMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/message.MessageService/SendMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessageServiceServer).SendMessage(ctx, req.(*SendMessageReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessageService_FindMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindMessagesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessageServiceServer).FindMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/message.MessageService/FindMessages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessageServiceServer).FindMessages(ctx, req.(*FindMessagesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessageService_MarkRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkReadReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessageServiceServer).MarkRead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/message.MessageService/MarkRead",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessageServiceServer).MarkRead(ctx, req.(*MarkReadReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessageService_CountUnread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountUnreadReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessageServiceServer).CountUnread(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/message.MessageService/CountUnread",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessageServiceServer).CountUnread(ctx, req.(*CountUnreadReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessageService_UpdateMessagePermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMessagePermissionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessageServiceServer).UpdateMessagePermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/message.MessageService/UpdateMessagePermission",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessageServiceServer).UpdateMessagePermission(ctx, req.(*UpdateMessagePermissionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MessageService_ServiceDesc is the grpc.ServiceDesc for MessageService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MessageService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "message.MessageService",
+	HandlerType: (*MessageServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateConversation",
+			Handler:    _MessageService_CreateConversation_Handler,
+		},
+		{
+			MethodName: "FindConversations",
+			Handler:    _MessageService_FindConversations_Handler,
+		},
+		{
+			MethodName: "FindConversationByID",
+			Handler:    _MessageService_FindConversationByID_Handler,
+		},
+		{
+			MethodName: "SendMessage",
+			Handler:    _MessageService_SendMessage_Handler,
+		},
+		{
+			MethodName: "FindMessages",
+			Handler:    _MessageService_FindMessages_Handler,
+		},
+		{
+			MethodName: "MarkRead",
+			Handler:    _MessageService_MarkRead_Handler,
+		},
+		{
+			MethodName: "CountUnread",
+			Handler:    _MessageService_CountUnread_Handler,
+		},
+		{
+			MethodName: "UpdateMessagePermission",
+			Handler:    _MessageService_UpdateMessagePermission_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "message/message.proto",
+}
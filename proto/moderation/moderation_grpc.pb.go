@@ -0,0 +1,249 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: moderation/moderation.proto
+
+package moderation
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ModerationServiceClient is the client API for ModerationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ModerationServiceClient interface {
+	CreateReport(ctx context.Context, in *CreateReportReq, opts ...grpc.CallOption) (*CreateReportResp, error)
+	FindReports(ctx context.Context, in *FindReportsReq, opts ...grpc.CallOption) (*FindReportsResp, error)
+	FindReportByID(ctx context.Context, in *FindReportByIDReq, opts ...grpc.CallOption) (*FindReportByIDResp, error)
+	TakeAction(ctx context.Context, in *TakeActionReq, opts ...grpc.CallOption) (*TakeActionResp, error)
+	FindActions(ctx context.Context, in *FindActionsReq, opts ...grpc.CallOption) (*FindActionsResp, error)
+}
+
+type moderationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewModerationServiceClient(cc grpc.ClientConnInterface) ModerationServiceClient {
+	return &moderationServiceClient{cc}
+}
+
+func (c *moderationServiceClient) CreateReport(ctx context.Context, in *CreateReportReq, opts ...grpc.CallOption) (*CreateReportResp, error) {
+	out := new(CreateReportResp)
+	err := c.cc.Invoke(ctx, "/moderation.ModerationService/CreateReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moderationServiceClient) FindReports(ctx context.Context, in *FindReportsReq, opts ...grpc.CallOption) (*FindReportsResp, error) {
+	out := new(FindReportsResp)
+	err := c.cc.Invoke(ctx, "/moderation.ModerationService/FindReports", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moderationServiceClient) FindReportByID(ctx context.Context, in *FindReportByIDReq, opts ...grpc.CallOption) (*FindReportByIDResp, error) {
+	out := new(FindReportByIDResp)
+	err := c.cc.Invoke(ctx, "/moderation.ModerationService/FindReportByID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moderationServiceClient) TakeAction(ctx context.Context, in *TakeActionReq, opts ...grpc.CallOption) (*TakeActionResp, error) {
+	out := new(TakeActionResp)
+	err := c.cc.Invoke(ctx, "/moderation.ModerationService/TakeAction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moderationServiceClient) FindActions(ctx context.Context, in *FindActionsReq, opts ...grpc.CallOption) (*FindActionsResp, error) {
+	out := new(FindActionsResp)
+	err := c.cc.Invoke(ctx, "/moderation.ModerationService/FindActions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ModerationServiceServer is the server API for ModerationService service.
+// All implementations must embed UnimplementedModerationServiceServer
+// for forward compatibility
+type ModerationServiceServer interface {
+	CreateReport(context.Context, *CreateReportReq) (*CreateReportResp, error)
+	FindReports(context.Context, *FindReportsReq) (*FindReportsResp, error)
+	FindReportByID(context.Context, *FindReportByIDReq) (*FindReportByIDResp, error)
+	TakeAction(context.Context, *TakeActionReq) (*TakeActionResp, error)
+	FindActions(context.Context, *FindActionsReq) (*FindActionsResp, error)
+	mustEmbedUnimplementedModerationServiceServer()
+}
+
+// UnimplementedModerationServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedModerationServiceServer struct {
+}
+
+func (UnimplementedModerationServiceServer) CreateReport(context.Context, *CreateReportReq) (*CreateReportResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateReport not implemented")
+}
+func (UnimplementedModerationServiceServer) FindReports(context.Context, *FindReportsReq) (*FindReportsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindReports not implemented")
+}
+func (UnimplementedModerationServiceServer) FindReportByID(context.Context, *FindReportByIDReq) (*FindReportByIDResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindReportByID not implemented")
+}
+func (UnimplementedModerationServiceServer) TakeAction(context.Context, *TakeActionReq) (*TakeActionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TakeAction not implemented")
+}
+func (UnimplementedModerationServiceServer) FindActions(context.Context, *FindActionsReq) (*FindActionsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindActions not implemented")
+}
+func (UnimplementedModerationServiceServer) mustEmbedUnimplementedModerationServiceServer() {}
+
+// UnsafeModerationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ModerationServiceServer will
+// result in compilation errors.
+type UnsafeModerationServiceServer interface {
+	mustEmbedUnimplementedModerationServiceServer()
+}
+
+func RegisterModerationServiceServer(s grpc.ServiceRegistrar, srv ModerationServiceServer) {
+	s.RegisterService(&ModerationService_ServiceDesc, srv)
+}
+
+func _ModerationService_CreateReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateReportReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModerationServiceServer).CreateReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/moderation.ModerationService/CreateReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModerationServiceServer).CreateReport(ctx, req.(*CreateReportReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ModerationService_FindReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindReportsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModerationServiceServer).FindReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/moderation.ModerationService/FindReports",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModerationServiceServer).FindReports(ctx, req.(*FindReportsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ModerationService_FindReportByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindReportByIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModerationServiceServer).FindReportByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/moderation.ModerationService/FindReportByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModerationServiceServer).FindReportByID(ctx, req.(*FindReportByIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ModerationService_TakeAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TakeActionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModerationServiceServer).TakeAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/moderation.ModerationService/TakeAction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModerationServiceServer).TakeAction(ctx, req.(*TakeActionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ModerationService_FindActions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindActionsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModerationServiceServer).FindActions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/moderation.ModerationService/FindActions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModerationServiceServer).FindActions(ctx, req.(*FindActionsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ModerationService_ServiceDesc is the grpc.ServiceDesc for ModerationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ModerationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "moderation.ModerationService",
+	HandlerType: (*ModerationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateReport",
+			Handler:    _ModerationService_CreateReport_Handler,
+		},
+		{
+			MethodName: "FindReports",
+			Handler:    _ModerationService_FindReports_Handler,
+		},
+		{
+			MethodName: "FindReportByID",
+			Handler:    _ModerationService_FindReportByID_Handler,
+		},
+		{
+			MethodName: "TakeAction",
+			Handler:    _ModerationService_TakeAction_Handler,
+		},
+		{
+			MethodName: "FindActions",
+			Handler:    _ModerationService_FindActions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "moderation/moderation.proto",
+}
@@ -0,0 +1,40 @@
+#!/bin/sh
+# Generates a local CA and the certificates docker-compose mounts for mutual
+# TLS between the gateway and the user service. Not for production use.
+#
+# Usage: scripts/gen-dev-certs.sh [output directory, default ./certs]
+set -eu
+
+out="${1:-./certs}"
+days=825
+mkdir -p "$out"
+cd "$out"
+
+if [ ! -f ca.key ]; then
+	openssl ecparam -name prime256v1 -genkey -noout -out ca.key
+	openssl req -x509 -new -key ca.key -sha256 -days "$days" \
+		-subj '/CN=social-media dev CA' -out ca.crt
+fi
+
+# issue <name> <extended key usage> <subject alt names>
+issue() {
+	cat > "$1.ext" <<EXT
+basicConstraints = CA:FALSE
+keyUsage = digitalSignature
+extendedKeyUsage = $2
+subjectAltName = $3
+EXT
+	openssl ecparam -name prime256v1 -genkey -noout -out "$1.key"
+	openssl req -new -key "$1.key" -subj "/CN=$1" -out "$1.csr"
+	openssl x509 -req -in "$1.csr" -CA ca.crt -CAkey ca.key -CAcreateserial \
+		-sha256 -days "$days" -extfile "$1.ext" -out "$1.crt"
+	rm "$1.csr" "$1.ext"
+}
+
+# The user service also presents its certificate as a client when its
+# healthcheck subcommand dials localhost.
+issue user_service 'serverAuth, clientAuth' 'DNS:user_service, DNS:localhost'
+issue gateway_service 'clientAuth' 'DNS:gateway_service'
+
+chmod 600 ./*.key
+echo "Wrote certificates to $out"
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+var logLevels = map[string]slog.Level{
+	"DEBUG": slog.LevelDebug,
+	"INFO":  slog.LevelInfo,
+	"WARN":  slog.LevelWarn,
+	"ERROR": slog.LevelError,
+}
+
+type DBConfig struct {
+	Host           string `yaml:"host"`
+	Port           int    `yaml:"port"`
+	User           string `yaml:"user"`
+	Password       string `yaml:"password"`
+	PasswordFile   string `yaml:"password_file"`
+	Name           string `yaml:"name"`
+	SSLMode        string `yaml:"ssl_mode"`
+	MigrateOnStart bool   `yaml:"migrate_on_start"`
+}
+
+type TracingConfig struct {
+	Exporter string `yaml:"exporter"`
+	Endpoint string `yaml:"endpoint"`
+	Insecure bool   `yaml:"insecure"`
+}
+
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	CAFile   string `yaml:"ca_file"`
+	// AllowedClients are the DNS SANs a client certificate must carry one of.
+	// When empty, any certificate signed by the CA is accepted.
+	AllowedClients []string `yaml:"allowed_clients"`
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+type RedisConfig struct {
+	Addr         string `yaml:"addr"`
+	Password     string `yaml:"password"`
+	PasswordFile string `yaml:"password_file"`
+	DB           int    `yaml:"db"`
+}
+
+type CacheConfig struct {
+	Store       string        `yaml:"store"`
+	TTL         time.Duration `yaml:"ttl"`
+	NegativeTTL time.Duration `yaml:"negative_ttl"`
+	MaxEntries  int           `yaml:"max_entries"`
+	Redis       RedisConfig   `yaml:"redis"`
+}
+
+type Config struct {
+	AppName             string        `yaml:"app_name"`
+	AppVersion          string        `yaml:"app_version"`
+	LogLevel            string        `yaml:"log_level"`
+	LogFormat           string        `yaml:"log_format"`
+	LogSampleInitial    int           `yaml:"log_sample_initial"`
+	LogSampleThereafter int           `yaml:"log_sample_thereafter"`
+	Port                int           `yaml:"port"`
+	MetricsPort         int           `yaml:"metrics_port"`
+	ShutdownTimeout     time.Duration `yaml:"shutdown_timeout"`
+	DB                  DBConfig      `yaml:"db"`
+	Tracing             TracingConfig `yaml:"tracing"`
+	TLS                 TLSConfig     `yaml:"tls"`
+	UserCache           CacheConfig   `yaml:"user_cache"`
+}
+
+func (c Config) Level() slog.Level {
+	return logLevels[strings.ToUpper(c.LogLevel)]
+}
+
+// Load builds the config from defaults, then the YAML or TOML file given by
+// -config or CONFIG_FILE, then environment variables, then flags. It returns
+// the arguments left after the flags.
+func Load(args []string) (*Config, []string, error) {
+	fs := flag.NewFlagSet("grpc_service", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	port := fs.Int("port", 0, "port to listen on")
+	logLevel := fs.String("log-level", "", "one of DEBUG, INFO, WARN, ERROR")
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	cfg := &Config{
+		AppName:             "user-service",
+		LogLevel:            "INFO",
+		LogFormat:           "json",
+		LogSampleInitial:    100,
+		LogSampleThereafter: 100,
+		Port:                50051,
+		MetricsPort:         9090,
+		ShutdownTimeout:     15 * time.Second,
+		DB: DBConfig{
+			Port:    5432,
+			SSLMode: "disable",
+		},
+		Tracing: TracingConfig{
+			Exporter: "none",
+			Endpoint: "localhost:4317",
+		},
+		UserCache: CacheConfig{
+			Store:       "memory",
+			TTL:         time.Minute,
+			NegativeTTL: 10 * time.Second,
+			MaxEntries:  10000,
+			Redis: RedisConfig{
+				Addr: "localhost:6379",
+			},
+		},
+	}
+	l := &loader{}
+	if *configFile != "" {
+		l.file(*configFile, cfg)
+	}
+	l.string(&cfg.AppName, "APP_NAME")
+	l.string(&cfg.AppVersion, "APP_VERSION")
+	l.string(&cfg.LogLevel, "LOG_LEVEL")
+	l.string(&cfg.LogFormat, "LOG_FORMAT")
+	l.int(&cfg.LogSampleInitial, "LOG_SAMPLE_INITIAL")
+	l.int(&cfg.LogSampleThereafter, "LOG_SAMPLE_THEREAFTER")
+	l.int(&cfg.Port, "PORT")
+	l.int(&cfg.MetricsPort, "METRICS_PORT")
+	l.duration(&cfg.ShutdownTimeout, "SHUTDOWN_TIMEOUT")
+	l.string(&cfg.DB.Host, "DB_HOST")
+	l.int(&cfg.DB.Port, "DB_PORT")
+	l.string(&cfg.DB.User, "DB_USER")
+	l.string(&cfg.DB.Password, "DB_PASS")
+	l.string(&cfg.DB.PasswordFile, "DB_PASS_FILE")
+	l.string(&cfg.DB.Name, "DB_NAME")
+	l.string(&cfg.DB.SSLMode, "DB_SSL_MODE")
+	l.bool(&cfg.DB.MigrateOnStart, "DB_MIGRATE_ON_START")
+	l.string(&cfg.Tracing.Exporter, "TRACING_EXPORTER")
+	l.string(&cfg.Tracing.Endpoint, "TRACING_ENDPOINT")
+	l.bool(&cfg.Tracing.Insecure, "TRACING_INSECURE")
+	l.string(&cfg.TLS.CertFile, "TLS_CERT_FILE")
+	l.string(&cfg.TLS.KeyFile, "TLS_KEY_FILE")
+	l.string(&cfg.TLS.CAFile, "TLS_CA_FILE")
+	l.list(&cfg.TLS.AllowedClients, "TLS_ALLOWED_CLIENTS")
+	l.string(&cfg.UserCache.Store, "USER_CACHE_STORE")
+	l.duration(&cfg.UserCache.TTL, "USER_CACHE_TTL")
+	l.duration(&cfg.UserCache.NegativeTTL, "USER_CACHE_NEGATIVE_TTL")
+	l.int(&cfg.UserCache.MaxEntries, "USER_CACHE_MAX_ENTRIES")
+	l.string(&cfg.UserCache.Redis.Addr, "USER_CACHE_REDIS_ADDR")
+	l.string(&cfg.UserCache.Redis.Password, "USER_CACHE_REDIS_PASSWORD")
+	l.string(&cfg.UserCache.Redis.PasswordFile, "USER_CACHE_REDIS_PASSWORD_FILE")
+	l.int(&cfg.UserCache.Redis.DB, "USER_CACHE_REDIS_DB")
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			cfg.Port = *port
+		case "log-level":
+			cfg.LogLevel = *logLevel
+		}
+	})
+	l.secret(&cfg.DB.Password, cfg.DB.PasswordFile, "DB_PASS")
+	l.secret(&cfg.UserCache.Redis.Password, cfg.UserCache.Redis.PasswordFile, "USER_CACHE_REDIS_PASSWORD")
+	if _, ok := logLevels[strings.ToUpper(cfg.LogLevel)]; !ok {
+		l.fail("LOG_LEVEL must be one of DEBUG, INFO, WARN, ERROR")
+	}
+	if cfg.LogFormat != "json" && cfg.LogFormat != "text" {
+		l.fail("LOG_FORMAT must be one of json, text")
+	}
+	if cfg.LogSampleInitial < 0 || cfg.LogSampleThereafter < 0 {
+		l.fail("LOG_SAMPLE_INITIAL and LOG_SAMPLE_THEREAFTER must not be negative")
+	}
+	l.port(cfg.Port, "PORT")
+	l.port(cfg.MetricsPort, "METRICS_PORT")
+	if cfg.ShutdownTimeout <= 0 {
+		l.fail("SHUTDOWN_TIMEOUT must be positive")
+	}
+	l.required(cfg.DB.Host, "DB_HOST")
+	l.port(cfg.DB.Port, "DB_PORT")
+	l.required(cfg.DB.User, "DB_USER")
+	l.required(cfg.DB.Name, "DB_NAME")
+	switch cfg.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		l.required(cfg.Tracing.Endpoint, "TRACING_ENDPOINT")
+	default:
+		l.fail("TRACING_EXPORTER must be one of none, stdout, otlp")
+	}
+	if (cfg.TLS.CertFile != "") != (cfg.TLS.KeyFile != "") || (cfg.TLS.CertFile != "") != (cfg.TLS.CAFile != "") {
+		l.fail("TLS_CERT_FILE, TLS_KEY_FILE and TLS_CA_FILE must be set together")
+	}
+	if len(cfg.TLS.AllowedClients) > 0 && !cfg.TLS.Enabled() {
+		l.fail("TLS_ALLOWED_CLIENTS requires TLS_CERT_FILE")
+	}
+	switch cfg.UserCache.Store {
+	case "none":
+	case "memory":
+		if cfg.UserCache.MaxEntries < 1 {
+			l.fail("USER_CACHE_MAX_ENTRIES must be positive")
+		}
+	case "redis":
+		l.required(cfg.UserCache.Redis.Addr, "USER_CACHE_REDIS_ADDR")
+	default:
+		l.fail("USER_CACHE_STORE must be one of none, memory, redis")
+	}
+	if cfg.UserCache.TTL <= 0 || cfg.UserCache.NegativeTTL <= 0 {
+		l.fail("USER_CACHE_TTL and USER_CACHE_NEGATIVE_TTL must be positive")
+	}
+	if err := l.err(); err != nil {
+		return nil, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
@@ -2,31 +2,22 @@ package config
 
 import (
 	"database/sql"
-	"fmt"
-	"os"
+	"net"
+	"net/url"
+	"strconv"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
-var pool *sql.DB
-
-func ConnectDB() error {
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASS"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
-	)
-	db, err := sql.Open("pgx", dsn)
-	if err != nil {
-		return err
+// ConnectDB builds a URL rather than a key/value DSN so credentials with
+// spaces or quotes need no escaping.
+func ConnectDB(dbConfig DBConfig) (*sql.DB, error) {
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(dbConfig.User, dbConfig.Password),
+		Host:     net.JoinHostPort(dbConfig.Host, strconv.Itoa(dbConfig.Port)),
+		Path:     "/" + dbConfig.Name,
+		RawQuery: url.Values{"sslmode": {dbConfig.SSLMode}}.Encode(),
 	}
-	pool = db
-	return nil
-}
-
-func GetDB() *sql.DB {
-	return pool
+	return sql.Open("pgx", dsn.String())
 }
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// loader collects every problem instead of stopping at the first one, so a
+// broken deployment is fixed in one go.
+type loader struct {
+	errs []string
+}
+
+func (l *loader) fail(format string, args ...interface{}) {
+	l.errs = append(l.errs, fmt.Sprintf(format, args...))
+}
+
+func (l *loader) err() error {
+	if len(l.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid config: %s", strings.Join(l.errs, "; "))
+}
+
+// file decodes a YAML config file, or a TOML one when its name ends in
+// .toml. TOML is converted to YAML first, so the yaml tags name the keys of
+// both and durations are strings such as "30s" in either.
+func (l *loader) file(path string, dst interface{}) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		l.fail("cannot read config file: %v", err)
+		return
+	}
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		values := map[string]interface{}{}
+		if err := toml.Unmarshal(content, &values); err != nil {
+			l.fail("cannot parse config file %s: %v", path, err)
+			return
+		}
+		if content, err = yaml.Marshal(values); err != nil {
+			l.fail("cannot parse config file %s: %v", path, err)
+			return
+		}
+	}
+	if err := yaml.Unmarshal(content, dst); err != nil {
+		l.fail("cannot parse config file %s: %v", path, err)
+	}
+}
+
+func (l *loader) string(dst *string, key string) {
+	if value, ok := os.LookupEnv(key); ok {
+		*dst = value
+	}
+}
+
+func (l *loader) int(dst *int, key string) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		l.fail("%s must be an integer", key)
+		return
+	}
+	*dst = n
+}
+
+func (l *loader) bool(dst *bool, key string) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.fail("%s must be true or false", key)
+		return
+	}
+	*dst = b
+}
+
+// list splits a comma-separated variable, dropping empty items.
+func (l *loader) list(dst *[]string, key string) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*dst = items
+}
+
+func (l *loader) duration(dst *time.Duration, key string) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		l.fail("%s must be a duration such as 30s or 6h", key)
+		return
+	}
+	*dst = d
+}
+
+// secret reads dst from the file named by path when one is set, which is how
+// Docker and Kubernetes mount secrets.
+func (l *loader) secret(dst *string, path string, key string) {
+	if path == "" {
+		return
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		l.fail("cannot read %s_FILE: %v", key, err)
+		return
+	}
+	*dst = strings.TrimRight(string(content), "\r\n")
+}
+
+func (l *loader) required(value string, key string) {
+	if value == "" {
+		l.fail("%s is required", key)
+	}
+}
+
+func (l *loader) port(value int, key string) {
+	if value < 1 || value > 65535 {
+		l.fail("%s must be between 1 and 65535", key)
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoaderFile(t *testing.T) {
+	want := &Config{
+		AppName:         "user-service",
+		Port:            50051,
+		ShutdownTimeout: 15 * time.Second,
+		DB:              DBConfig{Host: "db", MigrateOnStart: true},
+		TLS:             TLSConfig{AllowedClients: []string{"gateway_service"}},
+		UserCache:       CacheConfig{Store: "redis", TTL: time.Minute, Redis: RedisConfig{DB: 1}},
+	}
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		bad     bool
+	}{
+		{
+			name: "yaml",
+			file: "config.yaml",
+			content: `
+app_name: user-service
+port: 50051
+shutdown_timeout: 15s
+db:
+  host: db
+  migrate_on_start: true
+tls:
+  allowed_clients: [gateway_service]
+user_cache:
+  store: redis
+  ttl: 1m
+  redis:
+    db: 1
+`,
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			content: `
+app_name = "user-service"
+port = 50051
+shutdown_timeout = "15s"
+
+[db]
+host = "db"
+migrate_on_start = true
+
+[tls]
+allowed_clients = ["gateway_service"]
+
+[user_cache]
+store = "redis"
+ttl = "1m"
+redis = { db = 1 }
+`,
+		},
+		{
+			name:    "yaml named toml",
+			file:    "config.toml",
+			content: `app_name: user-service`,
+			bad:     true,
+		},
+		{
+			name:    "bad port in toml",
+			file:    "config.toml",
+			content: `port = "high"`,
+			bad:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			l := &loader{}
+			got := &Config{}
+			l.file(path, got)
+			if tt.bad {
+				if l.err() == nil {
+					t.Fatal("no error")
+				}
+				return
+			}
+			if err := l.err(); err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
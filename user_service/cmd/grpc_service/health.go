@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"userservice/cmd/config"
+	"userservice/internal/mtls"
+
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthPb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	healthCheckInterval = 5 * time.Second
+	healthCheckTimeout  = 2 * time.Second
+)
+
+// watchDB pings the database every healthCheckInterval until ctx is done and
+// reports the server and each of its services as NOT_SERVING while the ping
+// fails.
+func watchDB(ctx context.Context, logger *slog.Logger, db *sql.DB, healthServer *health.Server, services []string) {
+	const scope = "main#watchDB"
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	last := healthPb.HealthCheckResponse_UNKNOWN
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := db.PingContext(pingCtx)
+		cancel()
+		status := healthPb.HealthCheckResponse_SERVING
+		if err != nil {
+			status = healthPb.HealthCheckResponse_NOT_SERVING
+		}
+		if status != last {
+			if err != nil {
+				logger.Error("Database ping failed", err, slog.String("scope", scope))
+			} else {
+				logger.Info("Database is reachable", slog.String("scope", scope))
+			}
+			healthServer.SetServingStatus("", status)
+			for _, service := range services {
+				healthServer.SetServingStatus(service, status)
+			}
+			last = status
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runHealthcheck handles the healthcheck subcommand, which lets a container
+// probe the server without shipping a separate client. With TLS on it
+// presents the server's own certificate, so that certificate has to be valid
+// for localhost and for client authentication.
+func runHealthcheck(cfg *config.Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	creds := insecure.NewCredentials()
+	if cfg.TLS.Enabled() {
+		reloader, err := mtls.NewReloader(slog.Default(), mtls.Files{
+			CertFile: cfg.TLS.CertFile,
+			KeyFile:  cfg.TLS.KeyFile,
+			CAFile:   cfg.TLS.CAFile,
+		})
+		if err != nil {
+			return err
+		}
+		creds = credentials.NewTLS(reloader.ClientConfig("localhost"))
+	}
+	conn, err := grpc.DialContext(
+		ctx,
+		fmt.Sprintf("localhost:%d", cfg.Port),
+		grpc.WithTransportCredentials(creds),
+	)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	response, err := healthPb.NewHealthClient(conn).Check(ctx, &healthPb.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if response.Status != healthPb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("server is %s", response.Status)
+	}
+	return nil
+}
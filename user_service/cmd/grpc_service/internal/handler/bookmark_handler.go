@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"context"
+	handlerUtil "userservice/cmd/grpc_service/internal/util"
+	"userservice/internal/dto/req"
+	"userservice/internal/logging"
+
+	bookmarkPb "github.com/ideaspaper/social-media-proto/bookmark"
+
+	"golang.org/x/exp/slog"
+)
+
+func (h BookmarkHandler) CreateCollection(ctx context.Context, in *bookmarkPb.CreateCollectionReq) (*bookmarkPb.CreateCollectionResp, error) {
+	const scope = "bookmarkHandler#CreateCollection"
+	collection, err := h.bookmarkUsecase.CreateCollection(ctx, int(in.GetUserId()), &req.CollectionDto{
+		Name:      in.GetName(),
+		IsPrivate: in.IsPrivate,
+	})
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Created a bookmark collection",
+		slog.String("scope", scope),
+	)
+	return &bookmarkPb.CreateCollectionResp{
+		Message:        "Created a bookmark collection",
+		CollectionResp: handlerUtil.RespCollectionDtoToPb(collection),
+	}, nil
+}
+
+func (h BookmarkHandler) UpdateCollection(ctx context.Context, in *bookmarkPb.UpdateCollectionReq) (*bookmarkPb.UpdateCollectionResp, error) {
+	const scope = "bookmarkHandler#UpdateCollection"
+	collection, err := h.bookmarkUsecase.UpdateCollection(ctx, int(in.GetUserId()), int(in.GetId()), &req.UpdateCollectionDto{
+		Name:      in.Name,
+		IsPrivate: in.IsPrivate,
+	})
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Updated a bookmark collection",
+		slog.String("scope", scope),
+	)
+	return &bookmarkPb.UpdateCollectionResp{
+		Message:        "Updated a bookmark collection",
+		CollectionResp: handlerUtil.RespCollectionDtoToPb(collection),
+	}, nil
+}
+
+func (h BookmarkHandler) DeleteCollection(ctx context.Context, in *bookmarkPb.DeleteCollectionReq) (*bookmarkPb.DeleteCollectionResp, error) {
+	const scope = "bookmarkHandler#DeleteCollection"
+	collection, err := h.bookmarkUsecase.DeleteCollection(ctx, int(in.GetUserId()), int(in.GetId()))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Deleted a bookmark collection",
+		slog.String("scope", scope),
+	)
+	return &bookmarkPb.DeleteCollectionResp{
+		Message:        "Deleted a bookmark collection",
+		CollectionResp: handlerUtil.RespCollectionDtoToPb(collection),
+	}, nil
+}
+
+func (h BookmarkHandler) FindCollections(ctx context.Context, in *bookmarkPb.FindCollectionsReq) (*bookmarkPb.FindCollectionsResp, error) {
+	const scope = "bookmarkHandler#FindCollections"
+	page, err := h.bookmarkUsecase.FindCollections(ctx, int(in.GetViewerId()), int(in.GetUserId()), &req.PageDto{
+		Cursor: in.GetCursor(),
+		Limit:  int(in.GetLimit()),
+	})
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found bookmark collections of a user",
+		slog.String("scope", scope),
+	)
+	collectionResps := make([]*bookmarkPb.CollectionResp, 0, len(page.Collections))
+	for _, collection := range page.Collections {
+		collectionResps = append(collectionResps, handlerUtil.RespCollectionDtoToPb(collection))
+	}
+	return &bookmarkPb.FindCollectionsResp{
+		Message:         "Found bookmark collections of a user",
+		CollectionResps: collectionResps,
+		NextCursor:      page.NextCursor,
+	}, nil
+}
+
+func (h BookmarkHandler) AddBookmark(ctx context.Context, in *bookmarkPb.AddBookmarkReq) (*bookmarkPb.AddBookmarkResp, error) {
+	const scope = "bookmarkHandler#AddBookmark"
+	bookmarkDto := &req.BookmarkDto{
+		ItemType: in.GetItemType(),
+		ItemID:   int(in.GetItemId()),
+	}
+	if in.CollectionId != nil {
+		collectionID := int(in.GetCollectionId())
+		bookmarkDto.CollectionID = &collectionID
+	}
+	bookmark, err := h.bookmarkUsecase.AddBookmark(ctx, int(in.GetUserId()), bookmarkDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Added a bookmark",
+		slog.String("scope", scope),
+	)
+	return &bookmarkPb.AddBookmarkResp{
+		Message:      "Added a bookmark",
+		BookmarkResp: handlerUtil.RespBookmarkDtoToPb(bookmark),
+	}, nil
+}
+
+func (h BookmarkHandler) RemoveBookmark(ctx context.Context, in *bookmarkPb.RemoveBookmarkReq) (*bookmarkPb.RemoveBookmarkResp, error) {
+	const scope = "bookmarkHandler#RemoveBookmark"
+	bookmark, err := h.bookmarkUsecase.RemoveBookmark(ctx, int(in.GetUserId()), in.GetItemType(), int(in.GetItemId()))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Removed a bookmark",
+		slog.String("scope", scope),
+	)
+	return &bookmarkPb.RemoveBookmarkResp{
+		Message:      "Removed a bookmark",
+		BookmarkResp: handlerUtil.RespBookmarkDtoToPb(bookmark),
+	}, nil
+}
+
+func (h BookmarkHandler) FindBookmarks(ctx context.Context, in *bookmarkPb.FindBookmarksReq) (*bookmarkPb.FindBookmarksResp, error) {
+	const scope = "bookmarkHandler#FindBookmarks"
+	page, err := h.bookmarkUsecase.FindBookmarks(ctx, int(in.GetUserId()), &req.PageDto{
+		Cursor: in.GetCursor(),
+		Limit:  int(in.GetLimit()),
+	})
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found bookmarks of a user",
+		slog.String("scope", scope),
+	)
+	bookmarkResps := make([]*bookmarkPb.BookmarkResp, 0, len(page.Bookmarks))
+	for _, bookmark := range page.Bookmarks {
+		bookmarkResps = append(bookmarkResps, handlerUtil.RespBookmarkDtoToPb(bookmark))
+	}
+	return &bookmarkPb.FindBookmarksResp{
+		Message:       "Found bookmarks of a user",
+		BookmarkResps: bookmarkResps,
+		NextCursor:    page.NextCursor,
+	}, nil
+}
+
+func (h BookmarkHandler) FindCollectionBookmarks(ctx context.Context, in *bookmarkPb.FindCollectionBookmarksReq) (*bookmarkPb.FindCollectionBookmarksResp, error) {
+	const scope = "bookmarkHandler#FindCollectionBookmarks"
+	page, err := h.bookmarkUsecase.FindCollectionBookmarks(ctx, int(in.GetViewerId()), int(in.GetCollectionId()), &req.PageDto{
+		Cursor: in.GetCursor(),
+		Limit:  int(in.GetLimit()),
+	})
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found bookmarks of a collection",
+		slog.String("scope", scope),
+	)
+	bookmarkResps := make([]*bookmarkPb.BookmarkResp, 0, len(page.Bookmarks))
+	for _, bookmark := range page.Bookmarks {
+		bookmarkResps = append(bookmarkResps, handlerUtil.RespBookmarkDtoToPb(bookmark))
+	}
+	return &bookmarkPb.FindCollectionBookmarksResp{
+		Message:       "Found bookmarks of a collection",
+		BookmarkResps: bookmarkResps,
+		NextCursor:    page.NextCursor,
+	}, nil
+}
@@ -3,6 +3,9 @@ package handler
 import (
 	"userservice/internal/usecase"
 
+	bookmarkPb "github.com/ideaspaper/social-media-proto/bookmark"
+	messagePb "github.com/ideaspaper/social-media-proto/message"
+	moderationPb "github.com/ideaspaper/social-media-proto/moderation"
 	userPb "github.com/ideaspaper/social-media-proto/user"
 
 	"golang.org/x/exp/slog"
@@ -20,3 +23,42 @@ func New(logger *slog.Logger, userUsecase usecase.IUserUsecase) *Handler {
 		userUsecase: userUsecase,
 	}
 }
+
+type MessageHandler struct {
+	logger              *slog.Logger
+	conversationUsecase usecase.IConversationUsecase
+	messagePb.UnimplementedMessageServiceServer
+}
+
+func NewMessageHandler(logger *slog.Logger, conversationUsecase usecase.IConversationUsecase) *MessageHandler {
+	return &MessageHandler{
+		logger:              logger,
+		conversationUsecase: conversationUsecase,
+	}
+}
+
+type BookmarkHandler struct {
+	logger          *slog.Logger
+	bookmarkUsecase usecase.IBookmarkUsecase
+	bookmarkPb.UnimplementedBookmarkServiceServer
+}
+
+func NewBookmarkHandler(logger *slog.Logger, bookmarkUsecase usecase.IBookmarkUsecase) *BookmarkHandler {
+	return &BookmarkHandler{
+		logger:          logger,
+		bookmarkUsecase: bookmarkUsecase,
+	}
+}
+
+type ModerationHandler struct {
+	logger            *slog.Logger
+	moderationUsecase usecase.IModerationUsecase
+	moderationPb.UnimplementedModerationServiceServer
+}
+
+func NewModerationHandler(logger *slog.Logger, moderationUsecase usecase.IModerationUsecase) *ModerationHandler {
+	return &ModerationHandler{
+		logger:            logger,
+		moderationUsecase: moderationUsecase,
+	}
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"context"
+	handlerUtil "userservice/cmd/grpc_service/internal/util"
+	"userservice/internal/dto/req"
+	"userservice/internal/logging"
+
+	messagePb "github.com/ideaspaper/social-media-proto/message"
+
+	"golang.org/x/exp/slog"
+)
+
+func (h MessageHandler) CreateConversation(ctx context.Context, in *messagePb.CreateConversationReq) (*messagePb.CreateConversationResp, error) {
+	const scope = "messageHandler#CreateConversation"
+	participantIDs := make([]int, 0, len(in.GetParticipantIds()))
+	for _, participantID := range in.GetParticipantIds() {
+		participantIDs = append(participantIDs, int(participantID))
+	}
+	conversation, err := h.conversationUsecase.Create(ctx, int(in.GetUserId()), &req.ConversationDto{
+		ParticipantIDs: participantIDs,
+		Title:          in.Title,
+	})
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Created a conversation",
+		slog.String("scope", scope),
+	)
+	return &messagePb.CreateConversationResp{
+		Message:          "Created a conversation",
+		ConversationResp: handlerUtil.RespConversationDtoToPb(conversation),
+	}, nil
+}
+
+func (h MessageHandler) FindConversations(ctx context.Context, in *messagePb.FindConversationsReq) (*messagePb.FindConversationsResp, error) {
+	const scope = "messageHandler#FindConversations"
+	page, err := h.conversationUsecase.FindByUserID(ctx, int(in.GetUserId()), &req.PageDto{
+		Cursor: in.GetCursor(),
+		Limit:  int(in.GetLimit()),
+	})
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found conversations of a user",
+		slog.String("scope", scope),
+	)
+	conversationResps := make([]*messagePb.ConversationResp, 0, len(page.Conversations))
+	for _, conversation := range page.Conversations {
+		conversationResps = append(conversationResps, handlerUtil.RespConversationDtoToPb(conversation))
+	}
+	return &messagePb.FindConversationsResp{
+		Message:           "Found conversations of a user",
+		ConversationResps: conversationResps,
+		NextCursor:        page.NextCursor,
+	}, nil
+}
+
+func (h MessageHandler) FindConversationByID(ctx context.Context, in *messagePb.FindConversationByIDReq) (*messagePb.FindConversationByIDResp, error) {
+	const scope = "messageHandler#FindConversationByID"
+	conversation, err := h.conversationUsecase.FindByID(ctx, int(in.GetUserId()), int(in.GetId()))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found a conversation by its ID",
+		slog.String("scope", scope),
+	)
+	return &messagePb.FindConversationByIDResp{
+		Message:          "Found a conversation by its ID",
+		ConversationResp: handlerUtil.RespConversationDtoToPb(conversation),
+	}, nil
+}
+
+func (h MessageHandler) SendMessage(ctx context.Context, in *messagePb.SendMessageReq) (*messagePb.SendMessageResp, error) {
+	const scope = "messageHandler#SendMessage"
+	sentMessage, err := h.conversationUsecase.SendMessage(ctx, int(in.GetUserId()), int(in.GetConversationId()), &req.MessageDto{
+		Body:     in.GetBody(),
+		MediaIDs: in.GetMediaIds(),
+	})
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Sent a message",
+		slog.String("scope", scope),
+	)
+	recipientIDs := make([]int64, 0, len(sentMessage.RecipientIDs))
+	for _, recipientID := range sentMessage.RecipientIDs {
+		recipientIDs = append(recipientIDs, int64(recipientID))
+	}
+	return &messagePb.SendMessageResp{
+		Message:      "Sent a message",
+		MessageResp:  handlerUtil.RespMessageDtoToPb(sentMessage.Message),
+		RecipientIds: recipientIDs,
+	}, nil
+}
+
+func (h MessageHandler) FindMessages(ctx context.Context, in *messagePb.FindMessagesReq) (*messagePb.FindMessagesResp, error) {
+	const scope = "messageHandler#FindMessages"
+	page, err := h.conversationUsecase.FindMessages(ctx, int(in.GetUserId()), int(in.GetConversationId()), &req.PageDto{
+		Cursor: in.GetCursor(),
+		Limit:  int(in.GetLimit()),
+	})
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found messages of a conversation",
+		slog.String("scope", scope),
+	)
+	messageResps := make([]*messagePb.MessageResp, 0, len(page.Messages))
+	for _, message := range page.Messages {
+		messageResps = append(messageResps, handlerUtil.RespMessageDtoToPb(message))
+	}
+	return &messagePb.FindMessagesResp{
+		Message:      "Found messages of a conversation",
+		MessageResps: messageResps,
+		NextCursor:   page.NextCursor,
+	}, nil
+}
+
+func (h MessageHandler) MarkRead(ctx context.Context, in *messagePb.MarkReadReq) (*messagePb.MarkReadResp, error) {
+	const scope = "messageHandler#MarkRead"
+	conversation, err := h.conversationUsecase.MarkRead(ctx, int(in.GetUserId()), int(in.GetConversationId()), int(in.GetMessageId()))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Marked a conversation as read",
+		slog.String("scope", scope),
+	)
+	return &messagePb.MarkReadResp{
+		Message:          "Marked a conversation as read",
+		ConversationResp: handlerUtil.RespConversationDtoToPb(conversation),
+	}, nil
+}
+
+func (h MessageHandler) CountUnread(ctx context.Context, in *messagePb.CountUnreadReq) (*messagePb.CountUnreadResp, error) {
+	const scope = "messageHandler#CountUnread"
+	unreadCount, err := h.conversationUsecase.CountUnread(ctx, int(in.GetUserId()))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	return &messagePb.CountUnreadResp{
+		Message:     "Counted unread messages",
+		UnreadCount: int64(unreadCount),
+	}, nil
+}
+
+func (h MessageHandler) UpdateMessagePermission(ctx context.Context, in *messagePb.UpdateMessagePermissionReq) (*messagePb.UpdateMessagePermissionResp, error) {
+	const scope = "messageHandler#UpdateMessagePermission"
+	messagePermission, err := h.conversationUsecase.UpdateMessagePermission(ctx, int(in.GetUserId()), &req.MessagePermissionDto{
+		MessagePermission: in.GetMessagePermission(),
+	})
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Updated message permission",
+		slog.String("scope", scope),
+	)
+	return &messagePb.UpdateMessagePermissionResp{
+		Message:           "Updated message permission",
+		MessagePermission: messagePermission,
+	}, nil
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	handlerUtil "userservice/cmd/grpc_service/internal/util"
+	"userservice/internal/dto/req"
+	"userservice/internal/logging"
+
+	moderationPb "github.com/ideaspaper/social-media-proto/moderation"
+
+	"golang.org/x/exp/slog"
+)
+
+func (h ModerationHandler) CreateReport(ctx context.Context, in *moderationPb.CreateReportReq) (*moderationPb.CreateReportResp, error) {
+	const scope = "moderationHandler#CreateReport"
+	report, err := h.moderationUsecase.CreateReport(ctx, int(in.GetReporterId()), &req.ReportDto{
+		TargetType: in.GetTargetType(),
+		TargetID:   int(in.GetTargetId()),
+		ReasonCode: in.GetReasonCode(),
+		Details:    in.Details,
+	})
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Created a report",
+		slog.String("scope", scope),
+	)
+	return &moderationPb.CreateReportResp{
+		Message:    "Created a report",
+		ReportResp: handlerUtil.RespReportDtoToPb(report),
+	}, nil
+}
+
+func (h ModerationHandler) FindReports(ctx context.Context, in *moderationPb.FindReportsReq) (*moderationPb.FindReportsResp, error) {
+	const scope = "moderationHandler#FindReports"
+	page, err := h.moderationUsecase.FindReports(
+		ctx,
+		int(in.GetModeratorId()),
+		&req.ReportFilterDto{
+			Status: in.GetStatus(),
+		},
+		&req.PageDto{
+			Cursor: in.GetCursor(),
+			Limit:  int(in.GetLimit()),
+		},
+	)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found reports",
+		slog.String("scope", scope),
+	)
+	reportResps := make([]*moderationPb.ReportResp, 0, len(page.Reports))
+	for _, report := range page.Reports {
+		reportResps = append(reportResps, handlerUtil.RespReportDtoToPb(report))
+	}
+	return &moderationPb.FindReportsResp{
+		Message:     "Found reports",
+		ReportResps: reportResps,
+		NextCursor:  page.NextCursor,
+	}, nil
+}
+
+func (h ModerationHandler) FindReportByID(ctx context.Context, in *moderationPb.FindReportByIDReq) (*moderationPb.FindReportByIDResp, error) {
+	const scope = "moderationHandler#FindReportByID"
+	report, err := h.moderationUsecase.FindReportByID(ctx, int(in.GetModeratorId()), int(in.GetId()))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found a report by its ID",
+		slog.String("scope", scope),
+	)
+	return &moderationPb.FindReportByIDResp{
+		Message:    "Found a report by its ID",
+		ReportResp: handlerUtil.RespReportDtoToPb(report),
+	}, nil
+}
+
+func (h ModerationHandler) TakeAction(ctx context.Context, in *moderationPb.TakeActionReq) (*moderationPb.TakeActionResp, error) {
+	const scope = "moderationHandler#TakeAction"
+	moderationActionDto := &req.ModerationActionDto{
+		Action: in.GetAction(),
+		Note:   in.Note,
+	}
+	if in.SuspendHours != nil {
+		suspendHours := int(in.GetSuspendHours())
+		moderationActionDto.SuspendHours = &suspendHours
+	}
+	action, err := h.moderationUsecase.TakeAction(ctx, int(in.GetModeratorId()), int(in.GetReportId()), moderationActionDto)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Took a moderation action",
+		slog.String("scope", scope),
+	)
+	return &moderationPb.TakeActionResp{
+		Message:              "Took a moderation action",
+		ModerationActionResp: handlerUtil.RespModerationActionDtoToPb(action),
+	}, nil
+}
+
+func (h ModerationHandler) FindActions(ctx context.Context, in *moderationPb.FindActionsReq) (*moderationPb.FindActionsResp, error) {
+	const scope = "moderationHandler#FindActions"
+	page, err := h.moderationUsecase.FindActions(ctx, int(in.GetModeratorId()), &req.PageDto{
+		Cursor: in.GetCursor(),
+		Limit:  int(in.GetLimit()),
+	})
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found moderation actions",
+		slog.String("scope", scope),
+	)
+	moderationActionResps := make([]*moderationPb.ModerationActionResp, 0, len(page.Actions))
+	for _, action := range page.Actions {
+		moderationActionResps = append(moderationActionResps, handlerUtil.RespModerationActionDtoToPb(action))
+	}
+	return &moderationPb.FindActionsResp{
+		Message:               "Found moderation actions",
+		ModerationActionResps: moderationActionResps,
+		NextCursor:            page.NextCursor,
+	}, nil
+}
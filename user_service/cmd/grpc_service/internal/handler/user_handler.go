@@ -4,7 +4,7 @@ import (
 	"context"
 	handlerUtil "userservice/cmd/grpc_service/internal/util"
 	"userservice/internal/dto/req"
-	internalUtil "userservice/internal/util"
+	"userservice/internal/logging"
 
 	userPb "github.com/ideaspaper/social-media-proto/user"
 
@@ -13,20 +13,17 @@ import (
 
 func (h Handler) FindByID(ctx context.Context, in *userPb.FindByIDReq) (*userPb.FindByIDResp, error) {
 	const scope = "userHandler#FindByID"
-	requestID := ctx.Value(internalUtil.RequestID).(string)
 	user, err := h.userUsecase.FindByID(ctx, int(in.GetId()))
 	if err != nil {
-		h.logger.Error(
+		logging.FromContext(ctx, h.logger).Error(
 			"Got error from usecase",
 			err,
-			slog.String("request_id", requestID),
 			slog.String("scope", scope),
 		)
 		return nil, err
 	}
-	h.logger.Info(
+	logging.FromContext(ctx, h.logger).Info(
 		"Found a user by its ID",
-		slog.String("request_id", requestID),
 		slog.String("scope", scope),
 	)
 	return &userPb.FindByIDResp{
@@ -35,22 +32,53 @@ func (h Handler) FindByID(ctx context.Context, in *userPb.FindByIDReq) (*userPb.
 	}, nil
 }
 
+func (h Handler) FindByIDs(ctx context.Context, in *userPb.FindByIDsReq) (*userPb.FindByIDsResp, error) {
+	const scope = "userHandler#FindByIDs"
+	ids := make([]int, 0, len(in.GetIds()))
+	for _, id := range in.GetIds() {
+		ids = append(ids, int(id))
+	}
+	users, err := h.userUsecase.FindByIDs(ctx, &req.UserIDsDto{IDs: ids})
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error(
+			"Got error from usecase",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, err
+	}
+	logging.FromContext(ctx, h.logger).Info(
+		"Found users by their IDs",
+		slog.String("scope", scope),
+	)
+	userResps := make([]*userPb.UserResp, 0, len(users.Users))
+	for _, user := range users.Users {
+		userResps = append(userResps, handlerUtil.RespUserDtoToPb(user))
+	}
+	missingIDs := make([]int64, 0, len(users.MissingIDs))
+	for _, id := range users.MissingIDs {
+		missingIDs = append(missingIDs, int64(id))
+	}
+	return &userPb.FindByIDsResp{
+		Message:    "Found users by their IDs",
+		Users:      userResps,
+		MissingIds: missingIDs,
+	}, nil
+}
+
 func (h Handler) DeleteByID(ctx context.Context, in *userPb.DeleteByIDReq) (*userPb.DeleteByIDResp, error) {
 	const scope = "userHandler#DeleteByID"
-	requestID := ctx.Value(internalUtil.RequestID).(string)
-	user, err := h.userUsecase.DeleteByID(ctx, int(in.GetId()))
+	user, err := h.userUsecase.DeleteByID(ctx, int(in.GetCallerId()), int(in.GetId()), versionsOf(in.GetVersions()))
 	if err != nil {
-		h.logger.Error(
+		logging.FromContext(ctx, h.logger).Error(
 			"Got error from usecase",
 			err,
-			slog.String("request_id", requestID),
 			slog.String("scope", scope),
 		)
 		return nil, err
 	}
-	h.logger.Info(
+	logging.FromContext(ctx, h.logger).Info(
 		"Soft deleted a user by its ID",
-		slog.String("request_id", requestID),
 		slog.String("scope", scope),
 	)
 	return &userPb.DeleteByIDResp{
@@ -61,20 +89,17 @@ func (h Handler) DeleteByID(ctx context.Context, in *userPb.DeleteByIDReq) (*use
 
 func (h Handler) DeletePermanentlyByID(ctx context.Context, in *userPb.DeletePermanentlyByIDReq) (*userPb.DeletePermanentlyByIDResp, error) {
 	const scope = "userHandler#DeletePermanentlyByID"
-	requestID := ctx.Value(internalUtil.RequestID).(string)
-	user, err := h.userUsecase.DeletePermanentlyByID(ctx, int(in.GetId()))
+	user, err := h.userUsecase.DeletePermanentlyByID(ctx, int(in.GetCallerId()), int(in.GetId()), versionsOf(in.GetVersions()))
 	if err != nil {
-		h.logger.Error(
+		logging.FromContext(ctx, h.logger).Error(
 			"Got error from usecase",
 			err,
-			slog.String("request_id", requestID),
 			slog.String("scope", scope),
 		)
 		return nil, err
 	}
-	h.logger.Info(
+	logging.FromContext(ctx, h.logger).Info(
 		"Deleted a user permanently by its ID",
-		slog.String("request_id", requestID),
 		slog.String("scope", scope),
 	)
 	return &userPb.DeletePermanentlyByIDResp{
@@ -85,7 +110,6 @@ func (h Handler) DeletePermanentlyByID(ctx context.Context, in *userPb.DeletePer
 
 func (h Handler) Register(ctx context.Context, in *userPb.RegisterReq) (*userPb.RegisterResp, error) {
 	const scope = "userHandler#Register"
-	requestID := ctx.Value(internalUtil.RequestID).(string)
 	user, err := h.userUsecase.Register(ctx, &req.UserDto{
 		Email:     in.GetEmail(),
 		Password:  in.GetPassword(),
@@ -93,17 +117,15 @@ func (h Handler) Register(ctx context.Context, in *userPb.RegisterReq) (*userPb.
 		LastName:  in.GetLastName(),
 	})
 	if err != nil {
-		h.logger.Error(
+		logging.FromContext(ctx, h.logger).Error(
 			"Got error from usecase",
 			err,
-			slog.String("request_id", requestID),
 			slog.String("scope", scope),
 		)
 		return nil, err
 	}
-	h.logger.Info(
+	logging.FromContext(ctx, h.logger).Info(
 		"Registered a user",
-		slog.String("request_id", requestID),
 		slog.String("scope", scope),
 	)
 	return &userPb.RegisterResp{
@@ -114,16 +136,14 @@ func (h Handler) Register(ctx context.Context, in *userPb.RegisterReq) (*userPb.
 
 func (h Handler) Login(ctx context.Context, in *userPb.LoginReq) (*userPb.LoginResp, error) {
 	const scope = "userHandler#Login"
-	requestID := ctx.Value(internalUtil.RequestID).(string)
 	loginDto, err := h.userUsecase.Login(ctx, &req.LoginDto{
 		Email:    in.GetEmail(),
 		Password: in.GetPassword(),
 	})
 	if err != nil {
-		h.logger.Error(
+		logging.FromContext(ctx, h.logger).Error(
 			"Got error from usecase",
 			err,
-			slog.String("request_id", requestID),
 			slog.String("scope", scope),
 		)
 		return nil, err
@@ -132,5 +152,15 @@ func (h Handler) Login(ctx context.Context, in *userPb.LoginReq) (*userPb.LoginR
 		Message: "User logged in",
 		Id:      int64(loginDto.ID),
 		Email:   loginDto.Email,
+		Role:    loginDto.Role,
 	}, nil
 }
+
+// versionsOf converts the versions a delete is conditioned on.
+func versionsOf(versions []int64) []int {
+	converted := make([]int, 0, len(versions))
+	for _, version := range versions {
+		converted = append(converted, int(version))
+	}
+	return converted
+}
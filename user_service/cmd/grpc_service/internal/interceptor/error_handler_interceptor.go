@@ -11,7 +11,10 @@ import (
 func (i Interceptor) ErrorHandler(err error) error {
 	code := codes.Unknown
 	message := "Unknown"
-	if errors.Is(err, &usecase.ErrUserAlreadyExists) {
+	if errors.Is(err, errPanic) {
+		code = codes.Internal
+		message = "Internal error"
+	} else if errors.Is(err, &usecase.ErrUserAlreadyExists) {
 		code = codes.AlreadyExists
 		message = "Email already been registered"
 	} else if errors.Is(err, &usecase.ErrFailHashingPassword) {
@@ -23,9 +26,54 @@ func (i Interceptor) ErrorHandler(err error) error {
 	} else if errors.Is(err, &usecase.ErrUserNotFound) {
 		code = codes.NotFound
 		message = "User not found"
+	} else if errors.Is(err, &usecase.ErrUserVersionMismatch) {
+		code = codes.Aborted
+		message = "User was changed since it was read"
 	} else if errors.Is(err, &usecase.ErrWrongEmailOrPassword) {
 		code = codes.Unauthenticated
 		message = "Wrong email/password"
+	} else if errors.Is(err, &usecase.ErrConversationNotFound) {
+		code = codes.NotFound
+		message = "Conversation not found"
+	} else if errors.Is(err, &usecase.ErrMessagingRestricted) {
+		code = codes.PermissionDenied
+		message = "Recipient does not accept messages from you"
+	} else if errors.Is(err, &usecase.ErrBadCursor) {
+		code = codes.InvalidArgument
+		message = "Bad cursor"
+	} else if errors.Is(err, &usecase.ErrCollectionNotFound) {
+		code = codes.NotFound
+		message = "Collection not found"
+	} else if errors.Is(err, &usecase.ErrCollectionExists) {
+		code = codes.AlreadyExists
+		message = "Collection name already in use"
+	} else if errors.Is(err, &usecase.ErrBookmarkNotFound) {
+		code = codes.NotFound
+		message = "Bookmark not found"
+	} else if errors.Is(err, &usecase.ErrBookmarkItemNotFound) {
+		code = codes.NotFound
+		message = "Bookmarked item not found"
+	} else if errors.Is(err, &usecase.ErrUserSuspended) {
+		code = codes.PermissionDenied
+		message = errors.Unwrap(err).Error()
+	} else if errors.Is(err, &usecase.ErrNotModerator) {
+		code = codes.PermissionDenied
+		message = "Moderator role required"
+	} else if errors.Is(err, &usecase.ErrNotUserOrModerator) {
+		code = codes.PermissionDenied
+		message = "Only the user or a moderator may do this"
+	} else if errors.Is(err, &usecase.ErrReportNotFound) {
+		code = codes.NotFound
+		message = "Report not found"
+	} else if errors.Is(err, &usecase.ErrReportExists) {
+		code = codes.AlreadyExists
+		message = "You already reported this content"
+	} else if errors.Is(err, &usecase.ErrReportResolved) {
+		code = codes.FailedPrecondition
+		message = "Report already resolved"
+	} else if errors.Is(err, &usecase.ErrReportTargetNotFound) {
+		code = codes.NotFound
+		message = "Reported content not found"
 	}
 	return status.Error(code, message)
 }
@@ -2,50 +2,119 @@ package interceptor
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"time"
 
+	"userservice/internal/logging"
+	"userservice/internal/metrics"
 	"userservice/internal/util"
 
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/slog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthPb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
 type Interceptor struct {
-	logger *slog.Logger
+	logger         *slog.Logger
+	metrics        *metrics.Metrics
+	allowedClients []string
 }
 
-func NewInterceptor(logger *slog.Logger) *Interceptor {
+func NewInterceptor(logger *slog.Logger, metrics *metrics.Metrics, allowedClients []string) *Interceptor {
 	return &Interceptor{
-		logger: logger,
+		logger:         logger,
+		metrics:        metrics,
+		allowedClients: allowedClients,
 	}
 }
 
-func (i Interceptor) Intercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+func (i Interceptor) Intercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (h interface{}, err error) {
 	const scope = "interceptor#Intercept"
+	// Probes come from orchestration, not the gateway, so they carry no
+	// request ID and are too frequent to log.
+	if strings.HasPrefix(info.FullMethod, "/"+healthPb.Health_ServiceDesc.ServiceName+"/") {
+		return handler(ctx, req)
+	}
 	start := time.Now()
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, status.Error(codes.Internal, "No metadata provided")
+	i.metrics.InFlight.Inc()
+	defer func() {
+		i.metrics.InFlight.Dec()
+		code := status.Code(err).String()
+		i.metrics.RequestsTotal.WithLabelValues(info.FullMethod, code).Inc()
+		i.metrics.RequestDuration.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+	}()
+	// Callers other than the gateway, such as grpcurl, may send no metadata;
+	// they get a generated request ID, echoed in the response header.
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := ""
+	if values := md.Get("request-id"); len(values) > 0 {
+		requestID = values[0]
+	}
+	requestID = util.RequestIDOrNew(requestID)
+	grpc.SetHeader(ctx, metadata.Pairs("request-id", requestID))
+	ctx = context.WithValue(ctx, util.RequestID, requestID)
+	logger := i.logger.With(slog.String("request_id", requestID))
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		logger = logger.With(slog.String("trace_id", spanContext.TraceID().String()))
 	}
-	requestID := md["request-id"]
-	if len(requestID) == 0 {
-		return nil, status.Error(codes.Internal, "No request ID provided")
+	if userID := md.Get("user-id"); len(userID) > 0 {
+		if id, err := strconv.Atoi(userID[0]); err == nil {
+			logger = logger.With(slog.Int("user_id", id))
+		}
 	}
-	ctx = context.WithValue(ctx, util.RequestID, requestID[0])
-	h, err := handler(ctx, req)
+	ctx = logging.NewContext(ctx, logger)
+	if err = i.authorize(ctx); err != nil {
+		logger.Warn(
+			"Rejected caller",
+			slog.String("scope", scope),
+			slog.String("method", info.FullMethod),
+			slog.Any("error", err),
+		)
+		return nil, err
+	}
+	h, err = handler(ctx, req)
 	if err != nil {
 		err = i.ErrorHandler(err)
 	}
 	stop := time.Now()
-	i.logger.Info(
+	logger.Info(
 		"Handle request",
-		slog.String("request_id", requestID[0]),
 		slog.String("scope", scope),
 		slog.String("method", info.FullMethod),
 		slog.String("latency", stop.Sub(start).String()),
 	)
 	return h, err
 }
+
+// authorize admits callers whose verified client certificate carries one of
+// allowedClients as a DNS SAN. Without allowedClients it admits everyone the
+// TLS handshake let through.
+func (i Interceptor) authorize(ctx context.Context) error {
+	if len(i.allowedClients) == 0 {
+		return nil
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "No client certificate provided")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 {
+		return status.Error(codes.Unauthenticated, "No client certificate provided")
+	}
+	names := tlsInfo.State.VerifiedChains[0][0].DNSNames
+	for _, name := range names {
+		for _, allowed := range i.allowedClients {
+			if name == allowed {
+				return nil
+			}
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "Client %v is not allowed", names)
+}
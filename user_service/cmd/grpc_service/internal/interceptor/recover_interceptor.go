@@ -0,0 +1,36 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"userservice/internal/logging"
+
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+)
+
+// errPanic is what a handler that panicked returns, answered with Internal.
+var errPanic = errors.New("handler panicked")
+
+// Recover turns a panic in a handler into an error, so one request cannot
+// stop the server. It goes after Intercept, whose logger, metrics and
+// ErrorHandler then see the error like any other.
+func (i Interceptor) Recover(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (h interface{}, err error) {
+	const scope = "interceptor#Recover"
+	defer func() {
+		if r := recover(); r != nil {
+			logging.FromContext(ctx, i.logger).Error(
+				"Recovered from a panic",
+				fmt.Errorf("%v", r),
+				slog.String("scope", scope),
+				slog.String("method", info.FullMethod),
+				slog.String("stack", string(debug.Stack())),
+			)
+			h, err = nil, errPanic
+		}
+	}()
+	return handler(ctx, req)
+}
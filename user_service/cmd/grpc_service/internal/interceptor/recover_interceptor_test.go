@@ -0,0 +1,46 @@
+package interceptor
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"userservice/internal/metrics"
+
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecover(t *testing.T) {
+	i := NewInterceptor(slog.New(slog.NewTextHandler(io.Discard)), metrics.New(), nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/message.MessageService/SendMessage"}
+	tests := []struct {
+		name    string
+		handler grpc.UnaryHandler
+		code    codes.Code
+	}{
+		{
+			name:    "returns",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil },
+			code:    codes.OK,
+		},
+		{
+			name:    "panics",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) { panic("index out of range") },
+			code:    codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Chained as in main, Recover runs inside Intercept.
+			_, err := i.Intercept(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return i.Recover(ctx, req, info, tt.handler)
+			})
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("got %v, want %v", code, tt.code)
+			}
+		})
+	}
+}
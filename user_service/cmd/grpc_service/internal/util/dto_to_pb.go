@@ -3,6 +3,9 @@ package util
 import (
 	"userservice/internal/dto/resp"
 
+	bookmarkPb "github.com/ideaspaper/social-media-proto/bookmark"
+	messagePb "github.com/ideaspaper/social-media-proto/message"
+	moderationPb "github.com/ideaspaper/social-media-proto/moderation"
 	userPb "github.com/ideaspaper/social-media-proto/user"
 )
 
@@ -15,5 +18,114 @@ func RespUserDtoToPb(userDto *resp.UserDto) *userPb.UserResp {
 		CreatedAt: userDto.CreatedAt,
 		UpdatedAt: userDto.UpdatedAt,
 		DeletedAt: userDto.DeletedAt,
+		Version:   int64(userDto.Version),
 	}
 }
+
+func RespConversationDtoToPb(conversationDto *resp.ConversationDto) *messagePb.ConversationResp {
+	result := &messagePb.ConversationResp{
+		Id:             int64(conversationDto.ID),
+		Kind:           conversationDto.Kind,
+		Title:          conversationDto.Title,
+		ParticipantIds: make([]int64, 0, len(conversationDto.ParticipantIDs)),
+		UnreadCount:    int64(conversationDto.UnreadCount),
+		CreatedAt:      conversationDto.CreatedAt,
+		UpdatedAt:      conversationDto.UpdatedAt,
+	}
+	for _, participantID := range conversationDto.ParticipantIDs {
+		result.ParticipantIds = append(result.ParticipantIds, int64(participantID))
+	}
+	if conversationDto.LastMessageID != nil {
+		lastMessageID := int64(*conversationDto.LastMessageID)
+		result.LastMessageId = &lastMessageID
+	}
+	return result
+}
+
+func RespMessageDtoToPb(messageDto *resp.MessageDto) *messagePb.MessageResp {
+	result := &messagePb.MessageResp{
+		Id:             int64(messageDto.ID),
+		ConversationId: int64(messageDto.ConversationID),
+		SenderId:       int64(messageDto.SenderID),
+		Body:           messageDto.Body,
+		MediaIds:       messageDto.MediaIDs,
+		CreatedAt:      messageDto.CreatedAt,
+		ReadBy:         make([]int64, 0, len(messageDto.ReadBy)),
+	}
+	for _, userID := range messageDto.ReadBy {
+		result.ReadBy = append(result.ReadBy, int64(userID))
+	}
+	return result
+}
+
+func RespCollectionDtoToPb(collectionDto *resp.CollectionDto) *bookmarkPb.CollectionResp {
+	return &bookmarkPb.CollectionResp{
+		Id:            int64(collectionDto.ID),
+		UserId:        int64(collectionDto.UserID),
+		Name:          collectionDto.Name,
+		IsPrivate:     collectionDto.IsPrivate,
+		BookmarkCount: int64(collectionDto.BookmarkCount),
+		CreatedAt:     collectionDto.CreatedAt,
+		UpdatedAt:     collectionDto.UpdatedAt,
+	}
+}
+
+func RespBookmarkDtoToPb(bookmarkDto *resp.BookmarkDto) *bookmarkPb.BookmarkResp {
+	result := &bookmarkPb.BookmarkResp{
+		Id:        int64(bookmarkDto.ID),
+		UserId:    int64(bookmarkDto.UserID),
+		ItemType:  bookmarkDto.ItemType,
+		ItemId:    int64(bookmarkDto.ItemID),
+		CreatedAt: bookmarkDto.CreatedAt,
+	}
+	if bookmarkDto.CollectionID != nil {
+		collectionID := int64(*bookmarkDto.CollectionID)
+		result.CollectionId = &collectionID
+	}
+	return result
+}
+
+func RespReportDtoToPb(reportDto *resp.ReportDto) *moderationPb.ReportResp {
+	result := &moderationPb.ReportResp{
+		Id:         int64(reportDto.ID),
+		TargetType: reportDto.TargetType,
+		TargetId:   int64(reportDto.TargetID),
+		ReasonCode: reportDto.ReasonCode,
+		Details:    reportDto.Details,
+		Status:     reportDto.Status,
+		Resolution: reportDto.Resolution,
+		ResolvedAt: reportDto.ResolvedAt,
+		CreatedAt:  reportDto.CreatedAt,
+	}
+	if reportDto.ReporterID != nil {
+		reporterID := int64(*reportDto.ReporterID)
+		result.ReporterId = &reporterID
+	}
+	if reportDto.ResolvedBy != nil {
+		resolvedBy := int64(*reportDto.ResolvedBy)
+		result.ResolvedBy = &resolvedBy
+	}
+	return result
+}
+
+func RespModerationActionDtoToPb(moderationActionDto *resp.ModerationActionDto) *moderationPb.ModerationActionResp {
+	result := &moderationPb.ModerationActionResp{
+		Id:             int64(moderationActionDto.ID),
+		ModeratorId:    int64(moderationActionDto.ModeratorID),
+		Action:         moderationActionDto.Action,
+		TargetType:     moderationActionDto.TargetType,
+		TargetId:       int64(moderationActionDto.TargetID),
+		Note:           moderationActionDto.Note,
+		SuspendedUntil: moderationActionDto.SuspendedUntil,
+		CreatedAt:      moderationActionDto.CreatedAt,
+	}
+	if moderationActionDto.ReportID != nil {
+		reportID := int64(*moderationActionDto.ReportID)
+		result.ReportId = &reportID
+	}
+	if moderationActionDto.TargetUserID != nil {
+		targetUserID := int64(*moderationActionDto.TargetUserID)
+		result.TargetUserId = &targetUserID
+	}
+	return result
+}
@@ -1,70 +1,257 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"userservice/cmd/config"
 	"userservice/cmd/grpc_service/internal/handler"
 	"userservice/cmd/grpc_service/internal/interceptor"
+	"userservice/internal/cache"
+	cacheMemory "userservice/internal/cache/memory"
+	"userservice/internal/cache/redis"
+	"userservice/internal/logging"
+	"userservice/internal/metrics"
+	"userservice/internal/migration"
+	"userservice/internal/mtls"
+	"userservice/internal/repository"
+	"userservice/internal/repository/cached"
 	"userservice/internal/repository/pg"
+	"userservice/internal/tracing"
 	"userservice/internal/usecase"
 
+	bookmarkPb "github.com/ideaspaper/social-media-proto/bookmark"
+	messagePb "github.com/ideaspaper/social-media-proto/message"
+	moderationPb "github.com/ideaspaper/social-media-proto/moderation"
 	userPb "github.com/ideaspaper/social-media-proto/user"
 
 	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"golang.org/x/exp/slog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthPb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 )
 
-var logStringLevel = map[string]slog.Level{
-	"DEBUG": slog.LevelDebug,
-	"INFO":  slog.LevelInfo,
-	"WARN":  slog.LevelWarn,
-	"ERROR": slog.LevelError,
+func initLogger(cfg *config.Config) *slog.Logger {
+	logger := logging.New(os.Stdout, logging.Config{
+		Format:           cfg.LogFormat,
+		Level:            cfg.Level(),
+		AppName:          cfg.AppName,
+		AppVersion:       cfg.AppVersion,
+		SampleInitial:    cfg.LogSampleInitial,
+		SampleThereafter: cfg.LogSampleThereafter,
+	})
+	slog.SetDefault(logger)
+	return logger
 }
 
-func initLogger() *slog.Logger {
-	opts := slog.HandlerOptions{
-		Level:     logStringLevel[os.Getenv("LOG_LEVEL")],
-		AddSource: true,
-	}
-	textHandler := opts.NewTextHandler(os.Stdout).WithAttrs(
-		[]slog.Attr{
-			slog.String("app-name", os.Getenv("APP_NAME")),
-			slog.String("app-version", os.Getenv("APP_VERSION")),
-		},
-	)
-	return slog.New(textHandler)
+// initUserRepository reads users through the configured cache, if any.
+func initUserRepository(cfg config.CacheConfig, logger *slog.Logger, metrics *metrics.Metrics, userRepository repository.IUserRepository) repository.IUserRepository {
+	var store cache.IStore
+	switch cfg.Store {
+	case "memory":
+		store = cacheMemory.NewStore(cfg.MaxEntries)
+	case "redis":
+		store = redis.NewStore(redis.Config{
+			Addr:     cfg.Redis.Addr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+	default:
+		return userRepository
+	}
+	return cached.NewUserRepository(logger, userRepository, store, metrics, cached.Config{
+		TTL:         cfg.TTL,
+		NegativeTTL: cfg.NegativeTTL,
+	})
 }
 
 func main() {
-	const appPort = "50051"
-	if err := config.ConnectDB(); err != nil {
+	cfg, args, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if len(args) > 0 && args[0] == "healthcheck" {
+		if err := runHealthcheck(cfg); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	db, err := config.ConnectDB(cfg.DB)
+	if err != nil {
 		log.Fatalln(err)
 	}
-	db := config.GetDB()
-	logger := initLogger()
+	logger := initLogger(cfg)
+	if len(args) > 0 && args[0] == "migrate" {
+		err := runMigrate(logger, db, args[1:])
+		if errors.Is(err, errMigrateUsage) {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			os.Exit(2)
+		}
+		if err != nil {
+			logger.Error("Failed to migrate", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if cfg.DB.MigrateOnStart {
+		migrator, err := migration.NewMigrator(logger, db)
+		if err == nil {
+			err = migrator.Up(context.Background(), 0)
+		}
+		if err != nil {
+			logger.Error("Failed to migrate", err)
+			os.Exit(1)
+		}
+	}
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName:    cfg.AppName,
+		ServiceVersion: cfg.AppVersion,
+		Exporter:       cfg.Tracing.Exporter,
+		Endpoint:       cfg.Tracing.Endpoint,
+		Insecure:       cfg.Tracing.Insecure,
+	})
+	if err != nil {
+		logger.Error("Failed to initialize tracing", err)
+		os.Exit(1)
+	}
+	metrics := metrics.New()
+	metrics.RegisterDB(db, cfg.DB.Name)
 	validate := validator.New()
-	userRepository := pg.NewUserRepository(logger, db)
-	userUsecase := usecase.NewUserUsecase(logger, validate, userRepository)
+	userRepository := initUserRepository(cfg.UserCache, logger, metrics, pg.NewUserRepository(logger, db))
+	userUsecase := usecase.NewUserUsecase(logger, validate, userRepository, metrics)
+	conversationRepository := pg.NewConversationRepository(logger, db)
+	conversationUsecase := usecase.NewConversationUsecase(logger, validate, conversationRepository)
+	messageHandler := handler.NewMessageHandler(logger, conversationUsecase)
+	bookmarkRepository := pg.NewBookmarkRepository(logger, db)
+	bookmarkUsecase := usecase.NewBookmarkUsecase(logger, validate, bookmarkRepository)
+	bookmarkHandler := handler.NewBookmarkHandler(logger, bookmarkUsecase)
+	reportRepository := pg.NewReportRepository(logger, db)
+	moderationUsecase := usecase.NewModerationUsecase(logger, validate, reportRepository, userRepository, conversationRepository)
+	moderationHandler := handler.NewModerationHandler(logger, moderationUsecase)
 	handler := handler.New(logger, userUsecase)
-	interceptor := interceptor.NewInterceptor(logger)
+	interceptor := interceptor.NewInterceptor(logger, metrics, cfg.TLS.AllowedClients)
 	lis, err := net.Listen(
 		"tcp",
-		fmt.Sprintf(":%s", appPort),
+		fmt.Sprintf(":%d", cfg.Port),
 	)
 	if err != nil {
 		logger.Error("Failed to listen", err)
 		os.Exit(1)
 	}
-	s := grpc.NewServer(grpc.UnaryInterceptor(interceptor.Intercept))
+	serverOptions := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			otelgrpc.UnaryServerInterceptor(otelgrpc.WithInterceptorFilter(filters.Not(filters.HealthCheck()))),
+			interceptor.Intercept,
+			interceptor.Recover,
+		),
+		// The gateway pings idle connections every 30s so it notices a dead
+		// peer before a request does; the default policy would answer those
+		// pings with GOAWAY.
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             20 * time.Second,
+			PermitWithoutStream: true,
+		}),
+	}
+	var reloader *mtls.Reloader
+	if cfg.TLS.Enabled() {
+		reloader, err = mtls.NewReloader(logger, mtls.Files{
+			CertFile: cfg.TLS.CertFile,
+			KeyFile:  cfg.TLS.KeyFile,
+			CAFile:   cfg.TLS.CAFile,
+		})
+		if err != nil {
+			logger.Error("Failed to load TLS certificates", err)
+			os.Exit(1)
+		}
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(reloader.ServerConfig())))
+	}
+	s := grpc.NewServer(serverOptions...)
 	userPb.RegisterUserServiceServer(s, handler)
-	logger.Info("Server listening", slog.String("port", appPort))
-	if err := s.Serve(lis); err != nil {
+	messagePb.RegisterMessageServiceServer(s, messageHandler)
+	bookmarkPb.RegisterBookmarkServiceServer(s, bookmarkHandler)
+	moderationPb.RegisterModerationServiceServer(s, moderationHandler)
+	services := make([]string, 0, len(s.GetServiceInfo()))
+	for service := range s.GetServiceInfo() {
+		services = append(services, service)
+	}
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthPb.HealthCheckResponse_NOT_SERVING)
+	healthPb.RegisterHealthServer(s, healthServer)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go watchDB(ctx, logger, db, healthServer, services)
+	if reloader != nil {
+		go reloader.Watch(ctx)
+	}
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", metrics.Handler())
+	metricsSrv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.MetricsPort),
+		Handler: metricsMux,
+	}
+	go func() {
+		logger.Info("Metrics listening", slog.Int("port", cfg.MetricsPort))
+		if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Failed to serve metrics", err)
+		}
+	}()
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("Server listening", slog.Int("port", cfg.Port))
+		serveErr <- s.Serve(lis)
+	}()
+	select {
+	case err := <-serveErr:
 		logger.Error("Failed to serve", err)
 		os.Exit(1)
+	case <-ctx.Done():
+	}
+	stop()
+	logger.Info("Shutting down", slog.Duration("timeout", cfg.ShutdownTimeout))
+	healthServer.Shutdown()
+	gracefulStop(logger, s, cfg.ShutdownTimeout)
+	if err := metricsSrv.Close(); err != nil {
+		logger.Error("Failed to close metrics server", err)
+	}
+	if err := db.Close(); err != nil {
+		logger.Error("Failed to close database", err)
+	}
+	flushCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Error("Failed to flush traces", err)
+	}
+	logger.Info("Server stopped")
+}
+
+// gracefulStop waits for in-flight RPCs to finish and cancels whatever is
+// still running once timeout passes.
+func gracefulStop(logger *slog.Logger, s *grpc.Server, timeout time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+	case <-timer.C:
+		logger.Warn("Graceful stop timed out, cancelling in-flight RPCs")
+		s.Stop()
+		<-stopped
 	}
 }
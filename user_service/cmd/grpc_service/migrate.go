@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"userservice/internal/migration"
+
+	"golang.org/x/exp/slog"
+)
+
+const migrateUsage = `usage: grpc_service [flags] migrate <command>
+
+commands:
+  up [n]              apply all or the next n pending migrations
+  down [n]            revert the last n migrations, 1 by default
+  status              list migrations and when they were applied
+  baseline <version>  mark migrations up to version as applied without running them
+  seed                insert the demo users`
+
+var errMigrateUsage = errors.New("bad migrate command")
+
+// runMigrate handles the migrate subcommand.
+func runMigrate(logger *slog.Logger, db *sql.DB, args []string) error {
+	migrator, err := migration.NewMigrator(logger, db)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+	ctx := context.Background()
+	n := 0
+	if len(args) > 1 {
+		n, err = strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return errMigrateUsage
+		}
+	}
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx, n)
+	case "down":
+		if n == 0 {
+			n = 1
+		}
+		return migrator.Down(ctx, n)
+	case "baseline":
+		if n == 0 {
+			return errMigrateUsage
+		}
+		return migrator.Baseline(ctx, n)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(os.Stdout, "%04d  %-40s  %s\n", status.Version, status.Name, appliedAt)
+		}
+		return nil
+	case "seed":
+		return migrator.Seed(ctx)
+	default:
+		return errMigrateUsage
+	}
+}
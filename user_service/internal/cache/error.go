@@ -0,0 +1,51 @@
+package cache
+
+import "fmt"
+
+type errKind int
+
+var (
+	ErrUnavailable = Error{kind: unavailable}
+	ErrBadReply    = Error{kind: badReply}
+	ErrUnknown     = Error{kind: unknown}
+)
+
+const (
+	_ errKind = iota
+	unavailable
+	badReply
+	unknown
+)
+
+type Error struct {
+	kind errKind
+	err  error
+}
+
+func (e *Error) Error() string {
+	switch e.kind {
+	case unavailable:
+		return fmt.Sprintf("Store unavailable %v", e.err)
+	case badReply:
+		return fmt.Sprintf("Bad reply from store %v", e.err)
+	default:
+		return fmt.Sprintf("Unknown error %v", e.err)
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+func (e *Error) Is(err error) bool {
+	target, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+	return target.kind == e.kind
+}
+
+func (e *Error) SetError(err error) *Error {
+	e.err = err
+	return e
+}
@@ -0,0 +1,224 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"userservice/internal/cache"
+)
+
+const (
+	keyPrefix      = "cache:"
+	defaultTimeout = time.Second
+	maxIdleConns   = 8
+)
+
+type Config struct {
+	// Addr is the host:port of Redis or of anything that speaks its protocol,
+	// such as KeyDB or Valkey.
+	Addr     string
+	Password string
+	DB       int
+}
+
+// replyError is an error reply from the server, after which the connection
+// is still usable.
+type replyError string
+
+func (e replyError) Error() string {
+	return string(e)
+}
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+type store struct {
+	config Config
+	dialer net.Dialer
+	idle   chan *conn
+}
+
+// NewStore shares values between every instance using the same server, so a
+// write through one instance is seen by all of them.
+func NewStore(config Config) cache.IStore {
+	return &store{
+		config: config,
+		idle:   make(chan *conn, maxIdleConns),
+	}
+}
+
+func (s *store) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	const scope = "redisStore#Get"
+	reply, err := s.do(ctx, "GET", keyPrefix+key)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", scope, err)
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	value, ok := reply.(string)
+	if !ok {
+		return nil, false, fmt.Errorf("%s: %w", scope, cache.ErrBadReply.SetError(fmt.Errorf("%v", reply)))
+	}
+	return []byte(value), true, nil
+}
+
+func (s *store) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	const scope = "redisStore#Set"
+	milliseconds := ttl.Milliseconds()
+	if milliseconds < 1 {
+		milliseconds = 1
+	}
+	if _, err := s.do(ctx, "SET", keyPrefix+key, string(value), "PX", strconv.FormatInt(milliseconds, 10)); err != nil {
+		return fmt.Errorf("%s: %w", scope, err)
+	}
+	return nil
+}
+
+func (s *store) Delete(ctx context.Context, keys ...string) error {
+	const scope = "redisStore#Delete"
+	if len(keys) == 0 {
+		return nil
+	}
+	args := []string{"DEL"}
+	for _, key := range keys {
+		args = append(args, keyPrefix+key)
+	}
+	if _, err := s.do(ctx, args...); err != nil {
+		return fmt.Errorf("%s: %w", scope, err)
+	}
+	return nil
+}
+
+func (s *store) do(ctx context.Context, args ...string) (interface{}, error) {
+	c, err := s.get(ctx)
+	if err != nil {
+		return nil, cache.ErrUnavailable.SetError(err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	if err := c.SetDeadline(deadline); err != nil {
+		c.Close()
+		return nil, cache.ErrUnavailable.SetError(err)
+	}
+	reply, err := c.do(args...)
+	var replyErr replyError
+	if err != nil && !errors.As(err, &replyErr) {
+		c.Close()
+		return nil, cache.ErrUnavailable.SetError(err)
+	}
+	s.put(c)
+	if err != nil {
+		return nil, cache.ErrBadReply.SetError(err)
+	}
+	return reply, nil
+}
+
+func (s *store) get(ctx context.Context) (*conn, error) {
+	select {
+	case c := <-s.idle:
+		return c, nil
+	default:
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	netConn, err := s.dialer.DialContext(dialCtx, "tcp", s.config.Addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &conn{Conn: netConn, r: bufio.NewReader(netConn)}
+	if deadline, ok := dialCtx.Deadline(); ok {
+		c.SetDeadline(deadline)
+	}
+	if s.config.Password != "" {
+		if _, err := c.do("AUTH", s.config.Password); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	if s.config.DB != 0 {
+		if _, err := c.do("SELECT", strconv.Itoa(s.config.DB)); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (s *store) put(c *conn) {
+	select {
+	case s.idle <- c:
+	default:
+		c.Close()
+	}
+}
+
+func (c *conn) do(args ...string) (interface{}, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c, b.String()); err != nil {
+		return nil, err
+	}
+	return c.read()
+}
+
+// read parses one RESP2 reply. Bulk strings become strings, integers int64
+// and arrays []interface{}; nil bulk strings and arrays become nil.
+func (c *conn) read() (interface{}, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, replyError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			if values[i], err = c.read(); err != nil {
+				var replyErr replyError
+				if !errors.As(err, &replyErr) {
+					return nil, err
+				}
+			}
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("unknown reply type %q", kind)
+	}
+}
@@ -0,0 +1,64 @@
+package req
+
+type CollectionDto struct {
+	Name      string `json:"name" validate:"required,max=100"`
+	IsPrivate *bool  `json:"is_private"`
+}
+
+func (cd CollectionDto) ErrorMessages(field, tag string) string {
+	switch field {
+	case "Name":
+		switch tag {
+		case "required":
+			return "name is required"
+		case "max":
+			return "name maximum length is 100"
+		}
+	}
+	return ""
+}
+
+type UpdateCollectionDto struct {
+	Name      *string `json:"name" validate:"omitempty,min=1,max=100"`
+	IsPrivate *bool   `json:"is_private"`
+}
+
+func (ucd UpdateCollectionDto) ErrorMessages(field, tag string) string {
+	switch field {
+	case "Name":
+		switch tag {
+		case "min", "max":
+			return "name length must be between 1 and 100"
+		}
+	}
+	return ""
+}
+
+type BookmarkDto struct {
+	ItemType     string `json:"item_type" validate:"required,oneof=user message"`
+	ItemID       int    `json:"item_id" validate:"required,gt=0"`
+	CollectionID *int   `json:"collection_id" validate:"omitempty,gt=0"`
+}
+
+func (bd BookmarkDto) ErrorMessages(field, tag string) string {
+	switch field {
+	case "ItemType":
+		switch tag {
+		case "required":
+			return "item_type is required"
+		case "oneof":
+			return "item_type must be one of user, message"
+		}
+	case "ItemID":
+		switch tag {
+		case "required", "gt":
+			return "item_id must be positive"
+		}
+	case "CollectionID":
+		switch tag {
+		case "gt":
+			return "collection_id must be positive"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package req
+
+import "strings"
+
+type MessageDto struct {
+	Body     string   `json:"body" validate:"required_without=MediaIDs,max=4000"`
+	MediaIDs []string `json:"media_ids" validate:"max=10,dive,len=64,hexadecimal"`
+}
+
+func (md MessageDto) ErrorMessages(field, tag string) string {
+	if strings.HasPrefix(field, "MediaIDs[") {
+		return "media_ids must contain valid media IDs"
+	}
+	switch field {
+	case "Body":
+		switch tag {
+		case "required_without":
+			return "body is required when there are no media_ids"
+		case "max":
+			return "body maximum length is 4000"
+		}
+	case "MediaIDs":
+		switch tag {
+		case "max":
+			return "media_ids maximum length is 10"
+		}
+	}
+	return ""
+}
+
+type MessagePermissionDto struct {
+	MessagePermission string `json:"message_permission" validate:"required,oneof=everyone contacts nobody"`
+}
+
+func (mpd MessagePermissionDto) ErrorMessages(field, tag string) string {
+	switch field {
+	case "MessagePermission":
+		switch tag {
+		case "required":
+			return "message_permission is required"
+		case "oneof":
+			return "message_permission must be one of everyone, contacts, nobody"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package req
+
+type ReportDto struct {
+	TargetType string  `json:"target_type" validate:"required,oneof=user message"`
+	TargetID   int     `json:"target_id" validate:"required,gt=0"`
+	ReasonCode string  `json:"reason_code" validate:"required,oneof=spam harassment hate_speech violence sexual_content impersonation other"`
+	Details    *string `json:"details" validate:"omitempty,max=1000"`
+}
+
+func (rd ReportDto) ErrorMessages(field, tag string) string {
+	switch field {
+	case "TargetType":
+		switch tag {
+		case "required":
+			return "target_type is required"
+		case "oneof":
+			return "target_type must be one of user, message"
+		}
+	case "TargetID":
+		switch tag {
+		case "required", "gt":
+			return "target_id must be positive"
+		}
+	case "ReasonCode":
+		switch tag {
+		case "required":
+			return "reason_code is required"
+		case "oneof":
+			return "reason_code must be one of spam, harassment, hate_speech, violence, sexual_content, impersonation, other"
+		}
+	case "Details":
+		switch tag {
+		case "max":
+			return "details maximum length is 1000"
+		}
+	}
+	return ""
+}
+
+type ReportFilterDto struct {
+	Status string `json:"status" validate:"omitempty,oneof=open resolved"`
+}
+
+func (rfd ReportFilterDto) ErrorMessages(field, tag string) string {
+	switch field {
+	case "Status":
+		switch tag {
+		case "oneof":
+			return "status must be one of open, resolved"
+		}
+	}
+	return ""
+}
+
+type ModerationActionDto struct {
+	Action       string  `json:"action" validate:"required,oneof=dismiss warn suspend remove"`
+	Note         *string `json:"note" validate:"omitempty,max=1000"`
+	SuspendHours *int    `json:"suspend_hours" validate:"required_if=Action suspend,omitempty,min=1,max=8760"`
+}
+
+func (mad ModerationActionDto) ErrorMessages(field, tag string) string {
+	switch field {
+	case "Action":
+		switch tag {
+		case "required":
+			return "action is required"
+		case "oneof":
+			return "action must be one of dismiss, warn, suspend, remove"
+		}
+	case "Note":
+		switch tag {
+		case "max":
+			return "note maximum length is 1000"
+		}
+	case "SuspendHours":
+		switch tag {
+		case "required_if":
+			return "suspend_hours is required to suspend a user"
+		case "min", "max":
+			return "suspend_hours must be between 1 and 8760"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,17 @@
+package req
+
+type PageDto struct {
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit" validate:"min=0,max=100"`
+}
+
+func (pd PageDto) ErrorMessages(field, tag string) string {
+	switch field {
+	case "Limit":
+		switch tag {
+		case "min", "max":
+			return "limit must be between 1 and 100"
+		}
+	}
+	return ""
+}
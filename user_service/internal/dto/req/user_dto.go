@@ -1,5 +1,7 @@
 package req
 
+import "strings"
+
 type UserDto struct {
 	Email     string `json:"email" validate:"required,email"`
 	Password  string `json:"password" validate:"required,min=8"`
@@ -36,3 +38,23 @@ func (ud UserDto) ErrorMessages(field, tag string) string {
 	}
 	return ""
 }
+
+type UserIDsDto struct {
+	IDs []int `json:"ids" validate:"required,min=1,max=100,dive,gt=0"`
+}
+
+func (uid UserIDsDto) ErrorMessages(field, tag string) string {
+	if strings.HasPrefix(field, "IDs[") {
+		return "ids must be positive"
+	}
+	switch field {
+	case "IDs":
+		switch tag {
+		case "required", "min":
+			return "ids requires at least 1 id"
+		case "max":
+			return "ids maximum length is 100"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,30 @@
+package resp
+
+type CollectionDto struct {
+	ID            int    `json:"id"`
+	UserID        int    `json:"user_id"`
+	Name          string `json:"name"`
+	IsPrivate     bool   `json:"is_private"`
+	BookmarkCount int    `json:"bookmark_count"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+type CollectionPageDto struct {
+	Collections []*CollectionDto `json:"collections"`
+	NextCursor  string           `json:"next_cursor"`
+}
+
+type BookmarkDto struct {
+	ID           int    `json:"id"`
+	UserID       int    `json:"user_id"`
+	ItemType     string `json:"item_type"`
+	ItemID       int    `json:"item_id"`
+	CollectionID *int   `json:"collection_id,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type BookmarkPageDto struct {
+	Bookmarks  []*BookmarkDto `json:"bookmarks"`
+	NextCursor string         `json:"next_cursor"`
+}
@@ -0,0 +1,17 @@
+package resp
+
+type ConversationDto struct {
+	ID             int     `json:"id"`
+	Kind           string  `json:"kind"`
+	Title          *string `json:"title,omitempty"`
+	ParticipantIDs []int   `json:"participant_ids"`
+	UnreadCount    int     `json:"unread_count"`
+	LastMessageID  *int    `json:"last_message_id,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+	UpdatedAt      string  `json:"updated_at"`
+}
+
+type ConversationPageDto struct {
+	Conversations []*ConversationDto `json:"conversations"`
+	NextCursor    string             `json:"next_cursor"`
+}
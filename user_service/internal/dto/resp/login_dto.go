@@ -3,4 +3,5 @@ package resp
 type LoginDto struct {
 	ID    int    `json:"id"`
 	Email string `json:"email"`
+	Role  string `json:"role"`
 }
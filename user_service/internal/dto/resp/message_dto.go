@@ -0,0 +1,21 @@
+package resp
+
+type MessageDto struct {
+	ID             int      `json:"id"`
+	ConversationID int      `json:"conversation_id"`
+	SenderID       int      `json:"sender_id"`
+	Body           string   `json:"body"`
+	MediaIDs       []string `json:"media_ids"`
+	CreatedAt      string   `json:"created_at"`
+	ReadBy         []int    `json:"read_by"`
+}
+
+type MessagePageDto struct {
+	Messages   []*MessageDto `json:"messages"`
+	NextCursor string        `json:"next_cursor"`
+}
+
+type SentMessageDto struct {
+	Message      *MessageDto `json:"message"`
+	RecipientIDs []int       `json:"recipient_ids"`
+}
@@ -0,0 +1,38 @@
+package resp
+
+type ReportDto struct {
+	ID         int     `json:"id"`
+	ReporterID *int    `json:"reporter_id,omitempty"`
+	TargetType string  `json:"target_type"`
+	TargetID   int     `json:"target_id"`
+	ReasonCode string  `json:"reason_code"`
+	Details    *string `json:"details,omitempty"`
+	Status     string  `json:"status"`
+	Resolution *string `json:"resolution,omitempty"`
+	ResolvedBy *int    `json:"resolved_by,omitempty"`
+	ResolvedAt *string `json:"resolved_at,omitempty"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+type ReportPageDto struct {
+	Reports    []*ReportDto `json:"reports"`
+	NextCursor string       `json:"next_cursor"`
+}
+
+type ModerationActionDto struct {
+	ID             int     `json:"id"`
+	ReportID       *int    `json:"report_id,omitempty"`
+	ModeratorID    int     `json:"moderator_id"`
+	Action         string  `json:"action"`
+	TargetType     string  `json:"target_type"`
+	TargetID       int     `json:"target_id"`
+	TargetUserID   *int    `json:"target_user_id,omitempty"`
+	Note           *string `json:"note,omitempty"`
+	SuspendedUntil *string `json:"suspended_until,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+type ModerationActionPageDto struct {
+	Actions    []*ModerationActionDto `json:"actions"`
+	NextCursor string                 `json:"next_cursor"`
+}
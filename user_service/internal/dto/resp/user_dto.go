@@ -8,4 +8,10 @@ type UserDto struct {
 	CreatedAt string  `json:"created_at"`
 	UpdatedAt string  `json:"updated_at"`
 	DeletedAt *string `json:"deleted_at,omitempty"`
+	Version   int     `json:"version"`
+}
+
+type UsersDto struct {
+	Users      []*UserDto `json:"users"`
+	MissingIDs []int      `json:"missing_ids"`
 }
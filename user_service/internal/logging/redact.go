@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"strings"
+
+	"golang.org/x/exp/slog"
+)
+
+const redacted = "[REDACTED]"
+
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+	"jwt":           true,
+}
+
+var sensitiveSuffixes = []string{"password", "token", "secret"}
+
+// redact hides the value of attributes whose key names a credential, such as
+// password, access_token or jwt_secret.
+func redact(groups []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	if sensitiveKeys[key] {
+		return slog.String(a.Key, redacted)
+	}
+	for _, suffix := range sensitiveSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return slog.String(a.Key, redacted)
+		}
+	}
+	return a
+}
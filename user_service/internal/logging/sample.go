@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+// sampler counts records per level and message within one-second windows. It
+// is shared by every handler derived from the same root.
+type sampler struct {
+	mu          sync.Mutex
+	initial     int
+	thereafter  int
+	windowStart time.Time
+	counts      map[samplerKey]int
+}
+
+type samplerKey struct {
+	level slog.Level
+	msg   string
+}
+
+func (s *sampler) allow(r slog.Record) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Time.Sub(s.windowStart) >= time.Second {
+		s.windowStart = r.Time
+		s.counts = map[samplerKey]int{}
+	}
+	key := samplerKey{level: r.Level, msg: r.Message}
+	s.counts[key]++
+	n := s.counts[key]
+	if n <= s.initial {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.initial)%s.thereafter == 0
+}
+
+type samplingHandler struct {
+	handler slog.Handler
+	sampler *sampler
+}
+
+func newSamplingHandler(handler slog.Handler, initial int, thereafter int) slog.Handler {
+	return samplingHandler{
+		handler: handler,
+		sampler: &sampler{
+			initial:    initial,
+			thereafter: thereafter,
+			counts:     map[samplerKey]int{},
+		},
+	}
+}
+
+func (h samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle never drops warnings and errors.
+func (h samplingHandler) Handle(r slog.Record) error {
+	if r.Level < slog.LevelWarn && !h.sampler.allow(r) {
+		return nil
+	}
+	return h.handler.Handle(r)
+}
+
+func (h samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return samplingHandler{handler: h.handler.WithAttrs(attrs), sampler: h.sampler}
+}
+
+func (h samplingHandler) WithGroup(name string) slog.Handler {
+	return samplingHandler{handler: h.handler.WithGroup(name), sampler: h.sampler}
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	BcryptOperationHash    = "hash"
+	BcryptOperationCompare = "compare"
+)
+
+const (
+	LoginResultSuccess          = "success"
+	LoginResultWrongCredentials = "wrong_credentials"
+	LoginResultSuspended        = "suspended"
+	LoginResultError            = "error"
+)
+
+const (
+	CacheResultHit      = "hit"
+	CacheResultNegative = "negative_hit"
+	CacheResultMiss     = "miss"
+	CacheResultError    = "error"
+)
+
+type Metrics struct {
+	registry          *prometheus.Registry
+	RequestsTotal     *prometheus.CounterVec
+	RequestDuration   *prometheus.HistogramVec
+	InFlight          prometheus.Gauge
+	BcryptDuration    *prometheus.HistogramVec
+	LoginsTotal       *prometheus.CounterVec
+	CacheLookupsTotal *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		RequestsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "grpc_server_requests_total",
+				Help: "Unary RPCs handled, by method and status code.",
+			},
+			[]string{"method", "code"},
+		),
+		RequestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "grpc_server_request_duration_seconds",
+				Help:    "Unary RPC latency, by method and status code.",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"method", "code"},
+		),
+		InFlight: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "grpc_server_requests_in_flight",
+				Help: "Unary RPCs currently being handled.",
+			},
+		),
+		BcryptDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "bcrypt_duration_seconds",
+				Help:    "Time spent hashing and comparing passwords.",
+				Buckets: prometheus.ExponentialBuckets(0.01, 2, 8),
+			},
+			[]string{"operation"},
+		),
+		LoginsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "logins_total",
+				Help: "Login attempts, by result.",
+			},
+			[]string{"result"},
+		),
+		CacheLookupsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "user_cache_lookups_total",
+				Help: "User cache lookups, by key kind and result.",
+			},
+			[]string{"key", "result"},
+		),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.RequestsTotal,
+		m.RequestDuration,
+		m.InFlight,
+		m.BcryptDuration,
+		m.LoginsTotal,
+		m.CacheLookupsTotal,
+	)
+	return m
+}
+
+// RegisterDB exposes the connection pool statistics of db.
+func (m *Metrics) RegisterDB(db *sql.DB, dbName string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,55 @@
+package migration
+
+import "fmt"
+
+type errKind int
+
+var (
+	ErrBadMigration     = Error{kind: badMigration}
+	ErrChecksumMismatch = Error{kind: checksumMismatch}
+	ErrUnknownVersion   = Error{kind: unknownVersion}
+	ErrUnknown          = Error{kind: unknown}
+)
+
+const (
+	_ errKind = iota
+	badMigration
+	checksumMismatch
+	unknownVersion
+	unknown
+)
+
+type Error struct {
+	kind errKind
+	err  error
+}
+
+func (e *Error) Error() string {
+	switch e.kind {
+	case badMigration:
+		return fmt.Sprintf("Bad migration %v", e.err)
+	case checksumMismatch:
+		return fmt.Sprintf("Checksum mismatch %v", e.err)
+	case unknownVersion:
+		return fmt.Sprintf("Unknown version %v", e.err)
+	default:
+		return fmt.Sprintf("Unknown error %v", e.err)
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+func (e *Error) Is(err error) bool {
+	target, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+	return target.kind == e.kind
+}
+
+func (e *Error) SetError(err error) *Error {
+	e.err = err
+	return e
+}
@@ -0,0 +1,411 @@
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+//go:embed sql/*.sql
+var migrationFS embed.FS
+
+//go:embed seed/seed.sql
+var seedSQL string
+
+// lockKey is an arbitrary constant shared by every instance, so only one of
+// them migrates at a time.
+const lockKey int64 = 0x75736572_6d696772
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type migration struct {
+	version  int
+	name     string
+	up       string
+	down     string
+	checksum string
+}
+
+type migrator struct {
+	logger     *slog.Logger
+	db         *sql.DB
+	migrations []*migration
+}
+
+func NewMigrator(logger *slog.Logger, db *sql.DB) (IMigrator, error) {
+	migrations, err := loadMigrations(migrationFS)
+	if err != nil {
+		return nil, err
+	}
+	return &migrator{
+		logger:     logger,
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// loadMigrations pairs up and down files by version. Every migration must be
+// reversible and versions must not be shared.
+func loadMigrations(fsys fs.FS) ([]*migration, error) {
+	const scope = "migration#loadMigrations"
+	entries, err := fs.ReadDir(fsys, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrBadMigration.SetError(err))
+	}
+	byVersion := map[int]*migration{}
+	for _, entry := range entries {
+		matches := fileNamePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("%s: %w", scope, ErrBadMigration.SetError(fmt.Errorf("unexpected file %s", entry.Name())))
+		}
+		version, _ := strconv.Atoi(matches[1])
+		content, err := fs.ReadFile(fsys, path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", scope, ErrBadMigration.SetError(err))
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{
+				version: version,
+				name:    matches[2],
+			}
+			byVersion[version] = m
+		}
+		if m.name != matches[2] {
+			return nil, fmt.Errorf("%s: %w", scope, ErrBadMigration.SetError(fmt.Errorf("version %d is used by %s and %s", version, m.name, matches[2])))
+		}
+		if matches[3] == "up" {
+			m.up = string(content)
+			checksum := sha256.Sum256(content)
+			m.checksum = hex.EncodeToString(checksum[:])
+		} else {
+			m.down = string(content)
+		}
+	}
+	migrations := make([]*migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("%s: %w", scope, ErrBadMigration.SetError(fmt.Errorf("version %d needs both an up and a down file", m.version)))
+		}
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+	return migrations, nil
+}
+
+// withLock runs fn on a single connection holding the advisory lock, since
+// session locks belong to the connection that took them.
+func (m migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return ErrUnknown.SetError(err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1);`, lockKey); err != nil {
+		return ErrUnknown.SetError(err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1);`, lockKey)
+	_, err = conn.ExecContext(
+		ctx,
+		`
+			CREATE TABLE IF NOT EXISTS "schema_migrations_tab" (
+				"version" BIGINT PRIMARY KEY,
+				"name" VARCHAR NOT NULL,
+				"checksum" VARCHAR NOT NULL,
+				"applied_at" TIMESTAMP NOT NULL
+			);
+		`,
+	)
+	if err != nil {
+		return ErrUnknown.SetError(err)
+	}
+	return fn(conn)
+}
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// applied reads the applied versions and refuses to go on when they do not
+// match the embedded files, which means a migration was edited after it ran
+// or the database was migrated by a newer build.
+func (m migrator) applied(ctx context.Context, conn *sql.Conn) (map[int]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT "version", "checksum", "applied_at" FROM "schema_migrations_tab";`)
+	if err != nil {
+		return nil, ErrUnknown.SetError(err)
+	}
+	defer rows.Close()
+	result := map[int]appliedMigration{}
+	for rows.Next() {
+		var version int
+		var applied appliedMigration
+		if err := rows.Scan(&version, &applied.checksum, &applied.appliedAt); err != nil {
+			return nil, ErrUnknown.SetError(err)
+		}
+		result[version] = applied
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ErrUnknown.SetError(err)
+	}
+	if err := m.verify(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (m migrator) verify(applied map[int]appliedMigration) error {
+	known := map[int]bool{}
+	for _, mig := range m.migrations {
+		known[mig.version] = true
+		if a, ok := applied[mig.version]; ok && a.checksum != mig.checksum {
+			return ErrChecksumMismatch.SetError(fmt.Errorf("version %d %s", mig.version, mig.name))
+		}
+	}
+	for version := range applied {
+		if !known[version] {
+			return ErrUnknownVersion.SetError(fmt.Errorf("version %d is applied but has no file", version))
+		}
+	}
+	return nil
+}
+
+// pending returns the migrations Up applies, oldest first: at most steps of
+// the ones not applied, or all of them when steps is 0.
+func (m migrator) pending(applied map[int]appliedMigration, steps int) []*migration {
+	result := []*migration{}
+	for _, mig := range m.migrations {
+		if steps > 0 && len(result) == steps {
+			break
+		}
+		if _, ok := applied[mig.version]; !ok {
+			result = append(result, mig)
+		}
+	}
+	return result
+}
+
+// reverting returns the migrations Down reverts, newest first.
+func (m migrator) reverting(applied map[int]appliedMigration, steps int) []*migration {
+	result := []*migration{}
+	for i := len(m.migrations) - 1; i >= 0 && len(result) < steps; i-- {
+		if _, ok := applied[m.migrations[i].version]; ok {
+			result = append(result, m.migrations[i])
+		}
+	}
+	return result
+}
+
+// baselined returns the migrations Baseline records: the ones up to version
+// that are not applied yet. version must be one of the migrations.
+func (m migrator) baselined(applied map[int]appliedMigration, version int) ([]*migration, error) {
+	result := []*migration{}
+	found := false
+	for _, mig := range m.migrations {
+		if mig.version > version {
+			break
+		}
+		found = found || mig.version == version
+		if _, ok := applied[mig.version]; !ok {
+			result = append(result, mig)
+		}
+	}
+	if !found {
+		return nil, ErrUnknownVersion.SetError(fmt.Errorf("version %d", version))
+	}
+	return result, nil
+}
+
+// run executes a migration file and records the change in one transaction,
+// so a failing migration leaves nothing behind.
+func (m migrator) run(ctx context.Context, conn *sql.Conn, mig *migration, up bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if up {
+		if _, err := tx.ExecContext(ctx, mig.up); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			`
+				INSERT INTO "schema_migrations_tab" ("version", "name", "checksum", "applied_at")
+				VALUES ($1, $2, $3, $4);
+			`,
+			mig.version,
+			mig.name,
+			mig.checksum,
+			time.Now(),
+		)
+	} else {
+		if _, err := tx.ExecContext(ctx, mig.down); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `DELETE FROM "schema_migrations_tab" WHERE "version" = $1;`, mig.version)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m migrator) Up(ctx context.Context, steps int) error {
+	const scope = "migrator#Up"
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.pending(applied, steps) {
+			if err := m.run(ctx, conn, mig, true); err != nil {
+				return ErrUnknown.SetError(fmt.Errorf("version %d %s: %w", mig.version, mig.name, err))
+			}
+			m.logger.Info(
+				"Applied a migration",
+				slog.String("scope", scope),
+				slog.Int("version", mig.version),
+				slog.String("name", mig.name),
+			)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", scope, err)
+	}
+	return nil
+}
+
+func (m migrator) Down(ctx context.Context, steps int) error {
+	const scope = "migrator#Down"
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.reverting(applied, steps) {
+			if err := m.run(ctx, conn, mig, false); err != nil {
+				return ErrUnknown.SetError(fmt.Errorf("version %d %s: %w", mig.version, mig.name, err))
+			}
+			m.logger.Info(
+				"Reverted a migration",
+				slog.String("scope", scope),
+				slog.Int("version", mig.version),
+				slog.String("name", mig.name),
+			)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", scope, err)
+	}
+	return nil
+}
+
+func (m migrator) Baseline(ctx context.Context, version int) error {
+	const scope = "migrator#Baseline"
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		baselined, err := m.baselined(applied, version)
+		if err != nil {
+			return err
+		}
+		for _, mig := range baselined {
+			_, err := conn.ExecContext(
+				ctx,
+				`
+					INSERT INTO "schema_migrations_tab" ("version", "name", "checksum", "applied_at")
+					VALUES ($1, $2, $3, $4);
+				`,
+				mig.version,
+				mig.name,
+				mig.checksum,
+				time.Now(),
+			)
+			if err != nil {
+				return ErrUnknown.SetError(err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", scope, err)
+	}
+	m.logger.Info(
+		"Baselined migrations",
+		slog.String("scope", scope),
+		slog.Int("version", version),
+	)
+	return nil
+}
+
+func (m migrator) Status(ctx context.Context) ([]Status, error) {
+	const scope = "migrator#Status"
+	result := make([]Status, 0, len(m.migrations))
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			status := Status{
+				Version: mig.version,
+				Name:    mig.name,
+			}
+			if a, ok := applied[mig.version]; ok {
+				appliedAt := a.appliedAt
+				status.AppliedAt = &appliedAt
+			}
+			result = append(result, status)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	return result, nil
+}
+
+// Seed inserts the demo users. It can run any number of times.
+func (m migrator) Seed(ctx context.Context) error {
+	const scope = "migrator#Seed"
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(applied) < len(m.migrations) {
+			return ErrBadMigration.SetError(errors.New("pending migrations must be applied before seeding"))
+		}
+		if _, err := conn.ExecContext(ctx, seedSQL); err != nil {
+			return ErrUnknown.SetError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", scope, err)
+	}
+	m.logger.Info(
+		"Seeded the database",
+		slog.String("scope", scope),
+	)
+	return nil
+}
@@ -0,0 +1,25 @@
+package migration
+
+import (
+	"context"
+	"time"
+)
+
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+type IMigrator interface {
+	// Up applies at most steps pending migrations, or all of them when steps
+	// is 0.
+	Up(ctx context.Context, steps int) error
+	// Down reverts the last steps applied migrations.
+	Down(ctx context.Context, steps int) error
+	// Baseline records migrations up to version as applied without running
+	// them, for databases created before migrations existed.
+	Baseline(ctx context.Context, version int) error
+	Status(ctx context.Context) ([]Status, error)
+	Seed(ctx context.Context) error
+}
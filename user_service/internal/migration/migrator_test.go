@@ -0,0 +1,229 @@
+package migration
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(content string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(content)}
+	}
+	tests := []struct {
+		name     string
+		fsys     fstest.MapFS
+		versions []int
+		bad      bool
+	}{
+		{
+			name: "pairs in version order",
+			fsys: fstest.MapFS{
+				"sql/0010_b.up.sql":   file("B"),
+				"sql/0010_b.down.sql": file("-B"),
+				"sql/0002_a.up.sql":   file("A"),
+				"sql/0002_a.down.sql": file("-A"),
+			},
+			versions: []int{2, 10},
+		},
+		{
+			name: "missing down",
+			fsys: fstest.MapFS{"sql/0001_a.up.sql": file("A")},
+			bad:  true,
+		},
+		{
+			name: "missing up",
+			fsys: fstest.MapFS{"sql/0001_a.down.sql": file("-A")},
+			bad:  true,
+		},
+		{
+			name: "shared version",
+			fsys: fstest.MapFS{
+				"sql/0001_a.up.sql":   file("A"),
+				"sql/0001_a.down.sql": file("-A"),
+				"sql/0001_b.up.sql":   file("B"),
+				"sql/0001_b.down.sql": file("-B"),
+			},
+			bad: true,
+		},
+		{
+			name: "unexpected file",
+			fsys: fstest.MapFS{"sql/README.md": file("")},
+			bad:  true,
+		},
+		{
+			name: "no sql directory",
+			fsys: fstest.MapFS{},
+			bad:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadMigrations(tt.fsys)
+			if tt.bad {
+				if !errors.Is(err, &ErrBadMigration) {
+					t.Fatalf("got %v, want a bad migration", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if got := versionsOf(migrations); !reflect.DeepEqual(got, tt.versions) {
+				t.Fatalf("got versions %v, want %v", got, tt.versions)
+			}
+		})
+	}
+}
+
+func TestLoadMigrationsChecksum(t *testing.T) {
+	load := func(up string, down string) *migration {
+		migrations, err := loadMigrations(fstest.MapFS{
+			"sql/0001_a.up.sql":   {Data: []byte(up)},
+			"sql/0001_a.down.sql": {Data: []byte(down)},
+		})
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		return migrations[0]
+	}
+	original := load("CREATE TABLE a ();", "DROP TABLE a;")
+	// Only the up file is what ran, so only it is checksummed.
+	if got := load("CREATE TABLE a ();", "DROP TABLE IF EXISTS a;"); got.checksum != original.checksum {
+		t.Fatal("editing the down file changed the checksum")
+	}
+	if got := load("CREATE TABLE a (id INT);", "DROP TABLE a;"); got.checksum == original.checksum {
+		t.Fatal("editing the up file kept the checksum")
+	}
+}
+
+// The embedded files are what the service runs, so they must load.
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := loadMigrations(migrationFS)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations")
+	}
+}
+
+func testMigrator() migrator {
+	m := migrator{}
+	for _, version := range []int{1, 2, 3, 4} {
+		m.migrations = append(m.migrations, &migration{version: version, checksum: string(rune('a' + version))})
+	}
+	return m
+}
+
+func appliedOf(m migrator, versions ...int) map[int]appliedMigration {
+	result := map[int]appliedMigration{}
+	for _, mig := range m.migrations {
+		for _, version := range versions {
+			if mig.version == version {
+				result[version] = appliedMigration{checksum: mig.checksum}
+			}
+		}
+	}
+	return result
+}
+
+func versionsOf(migrations []*migration) []int {
+	result := []int{}
+	for _, mig := range migrations {
+		result = append(result, mig.version)
+	}
+	return result
+}
+
+func TestVerify(t *testing.T) {
+	m := testMigrator()
+	edited := appliedOf(m, 1, 2)
+	edited[2] = appliedMigration{checksum: "edited"}
+	newer := appliedOf(m, 1, 2)
+	newer[5] = appliedMigration{checksum: "f"}
+	tests := []struct {
+		name    string
+		applied map[int]appliedMigration
+		want    *Error
+	}{
+		{name: "nothing applied", applied: appliedOf(m)},
+		{name: "some applied", applied: appliedOf(m, 1, 2)},
+		{name: "applied out of order", applied: appliedOf(m, 1, 3)},
+		{name: "edited after it ran", applied: edited, want: &ErrChecksumMismatch},
+		{name: "applied by a newer build", applied: newer, want: &ErrUnknownVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.verify(tt.applied)
+			if (tt.want == nil && err != nil) || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPendingAndReverting(t *testing.T) {
+	m := testMigrator()
+	tests := []struct {
+		name      string
+		applied   []int
+		steps     int
+		pending   []int
+		reverting []int
+	}{
+		{name: "fresh, all steps", applied: nil, steps: 0, pending: []int{1, 2, 3, 4}, reverting: []int{}},
+		{name: "fresh, two steps", applied: nil, steps: 2, pending: []int{1, 2}, reverting: []int{}},
+		{name: "half applied, one step", applied: []int{1, 2}, steps: 1, pending: []int{3}, reverting: []int{2}},
+		{name: "gap filled first", applied: []int{1, 3}, steps: 0, pending: []int{2, 4}, reverting: []int{}},
+		{name: "gap, two steps", applied: []int{1, 3}, steps: 2, pending: []int{2, 4}, reverting: []int{3, 1}},
+		{name: "all applied", applied: []int{1, 2, 3, 4}, steps: 9, pending: []int{}, reverting: []int{4, 3, 2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applied := appliedOf(m, tt.applied...)
+			if got := versionsOf(m.pending(applied, tt.steps)); !reflect.DeepEqual(got, tt.pending) {
+				t.Fatalf("pending got %v, want %v", got, tt.pending)
+			}
+			if got := versionsOf(m.reverting(applied, tt.steps)); !reflect.DeepEqual(got, tt.reverting) {
+				t.Fatalf("reverting got %v, want %v", got, tt.reverting)
+			}
+		})
+	}
+}
+
+func TestBaselined(t *testing.T) {
+	m := testMigrator()
+	tests := []struct {
+		name    string
+		applied []int
+		version int
+		want    []int
+		unknown bool
+	}{
+		{name: "fresh", version: 2, want: []int{1, 2}},
+		{name: "latest", version: 4, want: []int{1, 2, 3, 4}},
+		{name: "skips applied", applied: []int{1}, version: 3, want: []int{2, 3}},
+		{name: "already baselined", applied: []int{1, 2}, version: 2, want: []int{}},
+		{name: "unknown version", version: 7, unknown: true},
+		{name: "no version 0", version: 0, unknown: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.baselined(appliedOf(m, tt.applied...), tt.version)
+			if tt.unknown {
+				if !errors.Is(err, &ErrUnknownVersion) {
+					t.Fatalf("got %v, want an unknown version", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("baseline: %v", err)
+			}
+			if versions := versionsOf(got); !reflect.DeepEqual(versions, tt.want) {
+				t.Fatalf("got %v, want %v", versions, tt.want)
+			}
+		})
+	}
+}
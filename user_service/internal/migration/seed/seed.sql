@@ -1,20 +1,11 @@
-CREATE TABLE "users_tab" (
-    "id" BIGSERIAL PRIMARY KEY,
-    "email" VARCHAR UNIQUE NOT NULL,
-    "password" VARCHAR NOT NULL,
-    "first_name" VARCHAR NOT NULL,
-    "last_name" VARCHAR NOT NULL,
-    "created_at" TIMESTAMP NOT NULL,
-    "updated_at" TIMESTAMP NOT NULL,
-    "deleted_at" TIMESTAMP
-);
 INSERT INTO "users_tab" (
         "email",
         "password",
         "first_name",
         "last_name",
         "created_at",
-        "updated_at"
+        "updated_at",
+        "role"
     )
 VALUES (
         'acong@mail.com',
@@ -22,7 +13,8 @@ VALUES (
         'Acong',
         'Suherman',
         NOW(),
-        NOW()
+        NOW(),
+        'moderator'
     ),
     (
         'djoko@mail.com',
@@ -30,5 +22,7 @@ VALUES (
         'Djoko',
         'Susanto',
         NOW(),
-        NOW()
-    );
\ No newline at end of file
+        NOW(),
+        'user'
+    )
+ON CONFLICT ("email") DO NOTHING;
@@ -0,0 +1,10 @@
+CREATE TABLE "users_tab" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "email" VARCHAR UNIQUE NOT NULL,
+    "password" VARCHAR NOT NULL,
+    "first_name" VARCHAR NOT NULL,
+    "last_name" VARCHAR NOT NULL,
+    "created_at" TIMESTAMP NOT NULL,
+    "updated_at" TIMESTAMP NOT NULL,
+    "deleted_at" TIMESTAMP
+);
@@ -0,0 +1,4 @@
+DROP TABLE "messages_tab";
+DROP TABLE "conversation_participants_tab";
+DROP TABLE "conversations_tab";
+ALTER TABLE "users_tab" DROP COLUMN "message_permission";
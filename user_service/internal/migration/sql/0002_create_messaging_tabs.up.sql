@@ -0,0 +1,28 @@
+ALTER TABLE "users_tab" ADD COLUMN "message_permission" VARCHAR NOT NULL DEFAULT 'everyone';
+CREATE TABLE "conversations_tab" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "kind" VARCHAR NOT NULL,
+    "title" VARCHAR,
+    "direct_key" VARCHAR UNIQUE,
+    "created_by" BIGINT REFERENCES "users_tab" ("id") ON DELETE SET NULL,
+    "last_message_id" BIGINT,
+    "created_at" TIMESTAMP NOT NULL,
+    "updated_at" TIMESTAMP NOT NULL
+);
+CREATE INDEX "conversations_tab_updated_at_idx" ON "conversations_tab" ("updated_at" DESC, "id" DESC);
+CREATE TABLE "conversation_participants_tab" (
+    "conversation_id" BIGINT NOT NULL REFERENCES "conversations_tab" ("id") ON DELETE CASCADE,
+    "user_id" BIGINT NOT NULL REFERENCES "users_tab" ("id") ON DELETE CASCADE,
+    "last_read_message_id" BIGINT NOT NULL DEFAULT 0,
+    "joined_at" TIMESTAMP NOT NULL,
+    PRIMARY KEY ("conversation_id", "user_id")
+);
+CREATE INDEX "conversation_participants_tab_user_id_idx" ON "conversation_participants_tab" ("user_id");
+CREATE TABLE "messages_tab" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "conversation_id" BIGINT NOT NULL REFERENCES "conversations_tab" ("id") ON DELETE CASCADE,
+    "sender_id" BIGINT REFERENCES "users_tab" ("id") ON DELETE SET NULL,
+    "body" TEXT NOT NULL,
+    "created_at" TIMESTAMP NOT NULL
+);
+CREATE INDEX "messages_tab_conversation_id_idx" ON "messages_tab" ("conversation_id", "id" DESC);
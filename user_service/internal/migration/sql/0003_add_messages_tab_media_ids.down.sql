@@ -0,0 +1 @@
+ALTER TABLE "messages_tab" DROP COLUMN "media_ids";
@@ -0,0 +1 @@
+ALTER TABLE "messages_tab" ADD COLUMN "media_ids" TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,5 @@
+DROP TRIGGER "messages_tab_delete_bookmarks" ON "messages_tab";
+DROP TRIGGER "users_tab_delete_bookmarks" ON "users_tab";
+DROP FUNCTION "delete_bookmarks_of_item"();
+DROP TABLE "bookmarks_tab";
+DROP TABLE "bookmark_collections_tab";
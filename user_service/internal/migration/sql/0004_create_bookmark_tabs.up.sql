@@ -0,0 +1,36 @@
+CREATE TABLE "bookmark_collections_tab" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "user_id" BIGINT NOT NULL REFERENCES "users_tab" ("id") ON DELETE CASCADE,
+    "name" VARCHAR NOT NULL,
+    "is_private" BOOLEAN NOT NULL DEFAULT TRUE,
+    "created_at" TIMESTAMP NOT NULL,
+    "updated_at" TIMESTAMP NOT NULL,
+    UNIQUE ("user_id", "name")
+);
+CREATE TABLE "bookmarks_tab" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "user_id" BIGINT NOT NULL REFERENCES "users_tab" ("id") ON DELETE CASCADE,
+    "item_type" VARCHAR NOT NULL,
+    "item_id" BIGINT NOT NULL,
+    "collection_id" BIGINT REFERENCES "bookmark_collections_tab" ("id") ON DELETE SET NULL,
+    "created_at" TIMESTAMP NOT NULL,
+    UNIQUE ("user_id", "item_type", "item_id")
+);
+CREATE INDEX "bookmarks_tab_user_id_idx" ON "bookmarks_tab" ("user_id", "id" DESC);
+CREATE INDEX "bookmarks_tab_collection_id_idx" ON "bookmarks_tab" ("collection_id", "id" DESC);
+CREATE INDEX "bookmarks_tab_item_idx" ON "bookmarks_tab" ("item_type", "item_id");
+-- Bookmarks point at rows of several tables, so they cannot use foreign keys
+-- on the item. These triggers remove them when the item is deleted, including
+-- through cascades.
+CREATE FUNCTION "delete_bookmarks_of_item"() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM "bookmarks_tab" WHERE "item_type" = TG_ARGV[0] AND "item_id" = OLD."id";
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER "users_tab_delete_bookmarks"
+    AFTER DELETE ON "users_tab"
+    FOR EACH ROW EXECUTE FUNCTION "delete_bookmarks_of_item"('user');
+CREATE TRIGGER "messages_tab_delete_bookmarks"
+    AFTER DELETE ON "messages_tab"
+    FOR EACH ROW EXECUTE FUNCTION "delete_bookmarks_of_item"('message');
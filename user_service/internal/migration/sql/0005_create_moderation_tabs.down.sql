@@ -0,0 +1,6 @@
+DROP TRIGGER "moderation_actions_tab_append_only" ON "moderation_actions_tab";
+DROP FUNCTION "reject_moderation_action_change"();
+DROP TABLE "moderation_actions_tab";
+DROP TABLE "reports_tab";
+ALTER TABLE "users_tab" DROP COLUMN "suspended_until";
+ALTER TABLE "users_tab" DROP COLUMN "role";
@@ -0,0 +1,39 @@
+ALTER TABLE "users_tab" ADD COLUMN "role" VARCHAR NOT NULL DEFAULT 'user';
+ALTER TABLE "users_tab" ADD COLUMN "suspended_until" TIMESTAMP;
+CREATE TABLE "reports_tab" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "reporter_id" BIGINT REFERENCES "users_tab" ("id") ON DELETE SET NULL,
+    "target_type" VARCHAR NOT NULL,
+    "target_id" BIGINT NOT NULL,
+    "reason_code" VARCHAR NOT NULL,
+    "details" TEXT,
+    "status" VARCHAR NOT NULL DEFAULT 'open',
+    "resolution" VARCHAR,
+    "resolved_by" BIGINT,
+    "resolved_at" TIMESTAMP,
+    "created_at" TIMESTAMP NOT NULL
+);
+CREATE INDEX "reports_tab_status_idx" ON "reports_tab" ("status", "id");
+CREATE UNIQUE INDEX "reports_tab_open_report_idx" ON "reports_tab" ("reporter_id", "target_type", "target_id") WHERE "status" = 'open';
+-- Actions keep plain IDs instead of foreign keys so the log outlives the
+-- users, reports and content it refers to.
+CREATE TABLE "moderation_actions_tab" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "report_id" BIGINT,
+    "moderator_id" BIGINT NOT NULL,
+    "action" VARCHAR NOT NULL,
+    "target_type" VARCHAR NOT NULL,
+    "target_id" BIGINT NOT NULL,
+    "target_user_id" BIGINT,
+    "note" TEXT,
+    "suspended_until" TIMESTAMP,
+    "created_at" TIMESTAMP NOT NULL
+);
+CREATE FUNCTION "reject_moderation_action_change"() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'moderation_actions_tab is append-only';
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER "moderation_actions_tab_append_only"
+    BEFORE UPDATE OR DELETE ON "moderation_actions_tab"
+    FOR EACH ROW EXECUTE FUNCTION "reject_moderation_action_change"();
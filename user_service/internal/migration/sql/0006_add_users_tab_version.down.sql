@@ -0,0 +1 @@
+ALTER TABLE "users_tab" DROP COLUMN "version";
@@ -0,0 +1,3 @@
+-- Every change to a user increments its version, so a write can be made
+-- conditional on the version the client last read.
+ALTER TABLE "users_tab" ADD COLUMN "version" BIGINT NOT NULL DEFAULT 1;
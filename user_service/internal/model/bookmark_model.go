@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+	"userservice/internal/dto/resp"
+)
+
+const (
+	BookmarkItemTypeUser    = "user"
+	BookmarkItemTypeMessage = "message"
+)
+
+type Collection struct {
+	ID            int
+	UserID        int
+	Name          string
+	IsPrivate     bool
+	BookmarkCount int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (c Collection) ToDto() *resp.CollectionDto {
+	return &resp.CollectionDto{
+		ID:            c.ID,
+		UserID:        c.UserID,
+		Name:          c.Name,
+		IsPrivate:     c.IsPrivate,
+		BookmarkCount: c.BookmarkCount,
+		CreatedAt:     c.CreatedAt.String(),
+		UpdatedAt:     c.UpdatedAt.String(),
+	}
+}
+
+type Bookmark struct {
+	ID           int
+	UserID       int
+	ItemType     string
+	ItemID       int
+	CollectionID *int
+	CreatedAt    time.Time
+}
+
+func (b Bookmark) ToDto() *resp.BookmarkDto {
+	return &resp.BookmarkDto{
+		ID:           b.ID,
+		UserID:       b.UserID,
+		ItemType:     b.ItemType,
+		ItemID:       b.ItemID,
+		CollectionID: b.CollectionID,
+		CreatedAt:    b.CreatedAt.String(),
+	}
+}
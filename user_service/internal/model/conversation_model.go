@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+	"userservice/internal/dto/resp"
+)
+
+const (
+	ConversationKindDirect = "direct"
+	ConversationKindGroup  = "group"
+)
+
+const (
+	MessagePermissionEveryone = "everyone"
+	MessagePermissionContacts = "contacts"
+	MessagePermissionNobody   = "nobody"
+)
+
+type Conversation struct {
+	ID             int
+	Kind           string
+	Title          *string
+	ParticipantIDs []int
+	UnreadCount    int
+	LastMessageID  *int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (c Conversation) ToDto() *resp.ConversationDto {
+	return &resp.ConversationDto{
+		ID:             c.ID,
+		Kind:           c.Kind,
+		Title:          c.Title,
+		ParticipantIDs: c.ParticipantIDs,
+		UnreadCount:    c.UnreadCount,
+		LastMessageID:  c.LastMessageID,
+		CreatedAt:      c.CreatedAt.String(),
+		UpdatedAt:      c.UpdatedAt.String(),
+	}
+}
+
+type Participant struct {
+	UserID            int
+	LastReadMessageID int
+}
+
+type Message struct {
+	ID             int
+	ConversationID int
+	SenderID       int
+	Body           string
+	MediaIDs       []string
+	CreatedAt      time.Time
+}
+
+// ToDto marks the message as read by every participant, other than its
+// sender, whose read marker has reached it.
+func (m Message) ToDto(participants []*Participant) *resp.MessageDto {
+	readBy := []int{}
+	for _, participant := range participants {
+		if participant.UserID != m.SenderID && participant.LastReadMessageID >= m.ID {
+			readBy = append(readBy, participant.UserID)
+		}
+	}
+	return &resp.MessageDto{
+		ID:             m.ID,
+		ConversationID: m.ConversationID,
+		SenderID:       m.SenderID,
+		Body:           m.Body,
+		MediaIDs:       m.MediaIDs,
+		CreatedAt:      m.CreatedAt.String(),
+		ReadBy:         readBy,
+	}
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"time"
+	"userservice/internal/dto/resp"
+)
+
+const (
+	ReportTargetTypeUser    = "user"
+	ReportTargetTypeMessage = "message"
+)
+
+const (
+	ReportStatusOpen     = "open"
+	ReportStatusResolved = "resolved"
+)
+
+const (
+	ModerationActionDismiss = "dismiss"
+	ModerationActionWarn    = "warn"
+	ModerationActionSuspend = "suspend"
+	ModerationActionRemove  = "remove"
+)
+
+type Report struct {
+	ID         int
+	ReporterID *int
+	TargetType string
+	TargetID   int
+	ReasonCode string
+	Details    *string
+	Status     string
+	Resolution *string
+	ResolvedBy *int
+	ResolvedAt *time.Time
+	CreatedAt  time.Time
+}
+
+func (r Report) ToDto() *resp.ReportDto {
+	result := &resp.ReportDto{
+		ID:         r.ID,
+		ReporterID: r.ReporterID,
+		TargetType: r.TargetType,
+		TargetID:   r.TargetID,
+		ReasonCode: r.ReasonCode,
+		Details:    r.Details,
+		Status:     r.Status,
+		Resolution: r.Resolution,
+		ResolvedBy: r.ResolvedBy,
+		CreatedAt:  r.CreatedAt.String(),
+	}
+	if r.ResolvedAt != nil {
+		resolvedAt := r.ResolvedAt.String()
+		result.ResolvedAt = &resolvedAt
+	}
+	return result
+}
+
+type ModerationAction struct {
+	ID             int
+	ReportID       *int
+	ModeratorID    int
+	Action         string
+	TargetType     string
+	TargetID       int
+	TargetUserID   *int
+	Note           *string
+	SuspendedUntil *time.Time
+	CreatedAt      time.Time
+}
+
+func (ma ModerationAction) ToDto() *resp.ModerationActionDto {
+	result := &resp.ModerationActionDto{
+		ID:           ma.ID,
+		ReportID:     ma.ReportID,
+		ModeratorID:  ma.ModeratorID,
+		Action:       ma.Action,
+		TargetType:   ma.TargetType,
+		TargetID:     ma.TargetID,
+		TargetUserID: ma.TargetUserID,
+		Note:         ma.Note,
+		CreatedAt:    ma.CreatedAt.String(),
+	}
+	if ma.SuspendedUntil != nil {
+		suspendedUntil := ma.SuspendedUntil.String()
+		result.SuspendedUntil = &suspendedUntil
+	}
+	return result
+}
@@ -5,15 +5,27 @@ import (
 	"userservice/internal/dto/resp"
 )
 
+const (
+	UserRoleUser      = "user"
+	UserRoleModerator = "moderator"
+)
+
 type User struct {
-	ID        int
-	Email     string
-	Password  string
-	FirstName string
-	LastName  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt *time.Time
+	ID             int
+	Email          string
+	Password       string
+	FirstName      string
+	LastName       string
+	Role           string
+	SuspendedUntil *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      *time.Time
+	Version        int
+}
+
+func (u User) IsSuspended(now time.Time) bool {
+	return u.SuspendedUntil != nil && u.SuspendedUntil.After(now)
 }
 
 func (u User) ToDto() *resp.UserDto {
@@ -24,6 +36,7 @@ func (u User) ToDto() *resp.UserDto {
 		LastName:  u.LastName,
 		CreatedAt: u.CreatedAt.String(),
 		UpdatedAt: u.UpdatedAt.String(),
+		Version:   u.Version,
 	}
 	if u.DeletedAt != nil {
 		deletedAtString := u.DeletedAt.String()
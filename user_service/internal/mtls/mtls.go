@@ -0,0 +1,168 @@
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+const watchInterval = 30 * time.Second
+
+// Files names the PEM files holding a service's own certificate and key, and
+// the CA that signs the certificates of its peers.
+type Files struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+// Reloader serves the certificate and CA from Files to every new TLS
+// handshake, so rotated files are picked up without a restart. Connections
+// that are already established keep the certificates they started with.
+type Reloader struct {
+	logger *slog.Logger
+	files  Files
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+}
+
+func NewReloader(logger *slog.Logger, files Files) (*Reloader, error) {
+	r := &Reloader{
+		logger: logger,
+		files:  files,
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.files.CertFile, r.files.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate: %w", err)
+	}
+	caPEM, err := os.ReadFile(r.files.CAFile)
+	if err != nil {
+		return fmt.Errorf("load CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("load CA: no certificates in %s", r.files.CAFile)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.pool = pool
+	return nil
+}
+
+func (r *Reloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, r.pool
+}
+
+// modTime is the latest modification time of the files. Stat follows
+// symlinks, so it also changes when Kubernetes swaps a mounted secret.
+func (r *Reloader) modTime() (time.Time, error) {
+	var latest time.Time
+	for _, path := range []string{r.files.CertFile, r.files.KeyFile, r.files.CAFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// Watch reloads the files when they change until ctx is done. A reload that
+// fails, for example because only the certificate has been replaced so far,
+// keeps the previous pair in use and is retried on the next tick.
+func (r *Reloader) Watch(ctx context.Context) {
+	const scope = "mtls#Watch"
+	loaded, _ := r.modTime()
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		modTime, err := r.modTime()
+		if err == nil && modTime.Equal(loaded) {
+			continue
+		}
+		if err == nil {
+			err = r.load()
+		}
+		if err != nil {
+			r.logger.Error("Failed to reload certificates", err, slog.String("scope", scope))
+			continue
+		}
+		r.logger.Info("Reloaded certificates", slog.String("scope", scope))
+		loaded = modTime
+	}
+}
+
+// ServerConfig requires clients to present a certificate signed by the CA.
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientCAs:    pool,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				NextProtos:   []string{"h2"},
+			}, nil
+		},
+	}
+}
+
+// ClientConfig checks that the server certificate is signed by the CA and
+// valid for serverName, or for the dialed host when serverName is empty. The
+// standard verification is turned off and redone in VerifyConnection because
+// RootCAs cannot be swapped once the config has been handed to gRPC.
+func (r *Reloader) ClientConfig(serverName string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			return cert, nil
+		},
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return errors.New("server sent no certificate")
+			}
+			_, pool := r.current()
+			intermediates := x509.NewCertPool()
+			for _, cert := range state.PeerCertificates[1:] {
+				intermediates.AddCert(cert)
+			}
+			_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+				Roots:         pool,
+				Intermediates: intermediates,
+				DNSName:       state.ServerName,
+			})
+			return err
+		},
+	}
+}
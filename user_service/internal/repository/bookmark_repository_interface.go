@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"userservice/internal/model"
+)
+
+type IBookmarkRepository interface {
+	CreateCollection(ctx context.Context, userID int, name string, isPrivate bool) (*model.Collection, error)
+	FindCollectionByID(ctx context.Context, id int) (*model.Collection, error)
+	FindCollectionsByUserID(ctx context.Context, userID int, includePrivate bool, beforeID int, limit int) ([]*model.Collection, error)
+	UpdateCollection(ctx context.Context, userID int, id int, name *string, isPrivate *bool) (*model.Collection, error)
+	DeleteCollection(ctx context.Context, userID int, id int) error
+	ItemExists(ctx context.Context, userID int, itemType string, itemID int) (bool, error)
+	UpsertBookmark(ctx context.Context, userID int, itemType string, itemID int, collectionID *int) (*model.Bookmark, error)
+	DeleteBookmark(ctx context.Context, userID int, itemType string, itemID int) (*model.Bookmark, error)
+	FindBookmarksByUserID(ctx context.Context, userID int, beforeID int, limit int) ([]*model.Bookmark, error)
+	FindBookmarksByCollectionID(ctx context.Context, collectionID int, beforeID int, limit int) ([]*model.Bookmark, error)
+}
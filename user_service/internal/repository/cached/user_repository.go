@@ -0,0 +1,378 @@
+package cached
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+	"userservice/internal/cache"
+	"userservice/internal/dto/req"
+	"userservice/internal/logging"
+	"userservice/internal/metrics"
+	"userservice/internal/model"
+	"userservice/internal/repository"
+
+	"golang.org/x/exp/slog"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	keyID    = "id"
+	keyEmail = "email"
+	// keyPrefix changes along with entry, so entries cached by an older
+	// release are not read as the new one. v2 added the user version, v3
+	// dropped the password hash.
+	keyPrefix = "user:v3:"
+	// loadTimeout bounds a load shared by several lookups, which no longer
+	// ends with the context of the lookup that started it.
+	loadTimeout = 5 * time.Second
+)
+
+var errCachedNotFound = errors.New("cached as not found")
+
+type Config struct {
+	TTL time.Duration
+	// NegativeTTL is how long a lookup that found no user is remembered.
+	NegativeTTL time.Duration
+}
+
+// entry is what the store holds for a key. ID keys hold the User and email
+// keys only its UserID. An entry with neither remembers that there was no
+// user.
+type entry struct {
+	User   *cachedUser `json:"user"`
+	UserID *int        `json:"user_id,omitempty"`
+}
+
+func (e entry) empty() bool {
+	return e.User == nil && e.UserID == nil
+}
+
+// cachedUser is a model.User without the password hash, which is never
+// written to the store.
+type cachedUser struct {
+	ID             int        `json:"id"`
+	Email          string     `json:"email"`
+	FirstName      string     `json:"first_name"`
+	LastName       string     `json:"last_name"`
+	Role           string     `json:"role"`
+	SuspendedUntil *time.Time `json:"suspended_until"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at"`
+	Version        int        `json:"version"`
+}
+
+func cachedUserOf(user *model.User) *cachedUser {
+	return &cachedUser{
+		ID:             user.ID,
+		Email:          user.Email,
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		Role:           user.Role,
+		SuspendedUntil: user.SuspendedUntil,
+		CreatedAt:      user.CreatedAt,
+		UpdatedAt:      user.UpdatedAt,
+		DeletedAt:      user.DeletedAt,
+		Version:        user.Version,
+	}
+}
+
+func (u cachedUser) toModel() *model.User {
+	return &model.User{
+		ID:             u.ID,
+		Email:          u.Email,
+		FirstName:      u.FirstName,
+		LastName:       u.LastName,
+		Role:           u.Role,
+		SuspendedUntil: u.SuspendedUntil,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
+		DeletedAt:      u.DeletedAt,
+		Version:        u.Version,
+	}
+}
+
+// userRepository reads users by ID and by email through a cache before
+// asking next. Users read through it have no password hash. It implements
+// every method itself rather than embedding next, so a method added to
+// IUserRepository does not compile here until it is decided what the method
+// invalidates.
+type userRepository struct {
+	logger  *slog.Logger
+	next    repository.IUserRepository
+	store   cache.IStore
+	metrics *metrics.Metrics
+	config  Config
+	// group collapses concurrent misses of a key into one load.
+	group *singleflight.Group
+	loads *loads
+}
+
+func NewUserRepository(logger *slog.Logger, next repository.IUserRepository, store cache.IStore, metrics *metrics.Metrics, config Config) repository.IUserRepository {
+	return &userRepository{
+		logger:  logger,
+		next:    next,
+		store:   store,
+		metrics: metrics,
+		config:  config,
+		group:   &singleflight.Group{},
+		loads:   &loads{inFlight: map[*load]bool{}},
+	}
+}
+
+func idKey(id int) string {
+	return keyPrefix + "id:" + strconv.Itoa(id)
+}
+
+func emailKey(email string) string {
+	return keyPrefix + "email:" + email
+}
+
+func (ur userRepository) FindByID(ctx context.Context, id int) (*model.User, error) {
+	const scope = "cachedUserRepository#FindByID"
+	cached, err := ur.lookup(ctx, scope, keyID, idKey(id), func(ctx context.Context) (entry, error) {
+		user, err := ur.next.FindByID(ctx, id)
+		if err != nil {
+			return entry{}, err
+		}
+		return entry{User: cachedUserOf(user)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cached.User == nil {
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(fmt.Errorf("%s holds no user", idKey(id))))
+	}
+	return cached.User.toModel(), nil
+}
+
+func (ur userRepository) FindByIDs(ctx context.Context, ids []int) ([]*model.User, error) {
+	return ur.next.FindByIDs(ctx, ids)
+}
+
+// FindByEmail caches which user has email rather than the user, and reads
+// the user by its ID. Writes know the user by its ID, so dropping the ID key
+// is enough for both lookups to see them. An email only moves to another
+// user when the first one is deleted, and then the ID lookup fails until
+// Create drops the email key.
+func (ur userRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	const scope = "cachedUserRepository#FindByEmail"
+	cached, err := ur.lookup(ctx, scope, keyEmail, emailKey(email), func(ctx context.Context) (entry, error) {
+		user, err := ur.next.FindByEmail(ctx, email)
+		if err != nil {
+			return entry{}, err
+		}
+		return entry{UserID: &user.ID}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cached.UserID == nil {
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(fmt.Errorf("%s holds no user ID", emailKey(email))))
+	}
+	return ur.FindByID(ctx, *cached.UserID)
+}
+
+func (ur userRepository) FindCredentialsByEmail(ctx context.Context, email string) (*model.User, error) {
+	return ur.next.FindCredentialsByEmail(ctx, email)
+}
+
+func (ur userRepository) Create(ctx context.Context, userDto *req.UserDto) (*model.User, error) {
+	user, err := ur.next.Create(ctx, userDto)
+	// The email may be remembered as having no user, or as the user it
+	// belonged to before that one was deleted.
+	ur.drop(ctx, emailKey(userDto.Email))
+	if err != nil {
+		return nil, err
+	}
+	// The ID may be remembered as having no user.
+	ur.drop(ctx, idKey(user.ID))
+	return user, nil
+}
+
+// The write methods below drop the cached user even when they fail, since
+// the write may have been applied before the error.
+
+func (ur userRepository) DeleteByID(ctx context.Context, id int, versions []int) (*model.User, error) {
+	user, err := ur.next.DeleteByID(ctx, id, versions)
+	ur.invalidate(ctx, id, user)
+	return user, err
+}
+
+func (ur userRepository) DeletePermanentlyByID(ctx context.Context, id int, versions []int) (*model.User, error) {
+	user, err := ur.next.DeletePermanentlyByID(ctx, id, versions)
+	ur.invalidate(ctx, id, user)
+	return user, err
+}
+
+func (ur userRepository) Suspend(ctx context.Context, id int, until time.Time) (*model.User, error) {
+	user, err := ur.next.Suspend(ctx, id, until)
+	ur.invalidate(ctx, id, user)
+	return user, err
+}
+
+// lookup returns the entry cached under key, or loads it and caches it. The
+// cache failing is logged and treated as a miss, so it can only slow lookups
+// down.
+func (ur userRepository) lookup(ctx context.Context, scope string, kind string, key string, load func(ctx context.Context) (entry, error)) (entry, error) {
+	value, ok, err := ur.store.Get(ctx, key)
+	if err != nil {
+		ur.metrics.CacheLookupsTotal.WithLabelValues(kind, metrics.CacheResultError).Inc()
+		logging.FromContext(ctx, ur.logger).Warn(
+			"Failed to read the user cache",
+			slog.String("scope", scope),
+			slog.Any("error", err),
+		)
+	} else if ok {
+		cached := entry{}
+		if err := json.Unmarshal(value, &cached); err != nil {
+			ur.metrics.CacheLookupsTotal.WithLabelValues(kind, metrics.CacheResultError).Inc()
+			logging.FromContext(ctx, ur.logger).Warn(
+				"Failed to decode a cached user",
+				slog.String("scope", scope),
+				slog.Any("error", err),
+			)
+		} else if cached.empty() {
+			ur.metrics.CacheLookupsTotal.WithLabelValues(kind, metrics.CacheResultNegative).Inc()
+			return entry{}, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(errCachedNotFound))
+		} else {
+			ur.metrics.CacheLookupsTotal.WithLabelValues(kind, metrics.CacheResultHit).Inc()
+			return cached, nil
+		}
+	} else {
+		ur.metrics.CacheLookupsTotal.WithLabelValues(kind, metrics.CacheResultMiss).Inc()
+	}
+	// The load is shared with later lookups of key, so it runs detached from
+	// ctx: a lookup that gives up must not fail the others.
+	results := ur.group.DoChan(key, func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(detach(ctx), loadTimeout)
+		defer cancel()
+		started := ur.loads.start(key)
+		loaded, err := load(loadCtx)
+		fill := ur.loads.finish(started)
+		if err != nil {
+			if fill && errors.Is(err, &repository.ErrDataNotFound) {
+				ur.set(loadCtx, scope, key, entry{}, ur.config.NegativeTTL)
+			}
+			return nil, err
+		}
+		if fill {
+			ur.set(loadCtx, scope, key, loaded, ur.config.TTL)
+		}
+		return loaded, nil
+	})
+	select {
+	case <-ctx.Done():
+		return entry{}, fmt.Errorf("%s: %w", scope, ctx.Err())
+	case result := <-results:
+		if result.Err != nil {
+			return entry{}, result.Err
+		}
+		return result.Val.(entry), nil
+	}
+}
+
+func (ur userRepository) set(ctx context.Context, scope string, key string, cached entry, ttl time.Duration) {
+	value, err := json.Marshal(cached)
+	if err == nil {
+		err = ur.store.Set(ctx, key, value, ttl)
+	}
+	if err != nil {
+		logging.FromContext(ctx, ur.logger).Warn(
+			"Failed to write the user cache",
+			slog.String("scope", scope),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// invalidate drops what is cached about the user with id after a write.
+// Email keys lead to the ID key, so the ID key is all that must go; the
+// email key of the user the write returned goes too, so that a deleted user
+// is not remembered by its email.
+func (ur userRepository) invalidate(ctx context.Context, id int, user *model.User) {
+	ur.drop(ctx, idKey(id))
+	if user != nil {
+		ur.drop(ctx, emailKey(user.Email))
+	}
+}
+
+// drop deletes key from the store. Loads of the key already in flight are
+// forgotten too, so the next lookup does not join one that may have read the
+// user before the write, and they do not fill the cache.
+func (ur userRepository) drop(ctx context.Context, key string) {
+	const scope = "cachedUserRepository#drop"
+	ur.loads.invalidate(key)
+	ur.group.Forget(key)
+	if err := ur.store.Delete(ctx, key); err != nil {
+		logging.FromContext(ctx, ur.logger).Warn(
+			"Failed to invalidate the user cache",
+			slog.String("scope", scope),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// loads tracks the loads in flight, so a write can tell the loads of the
+// keys it touches that they may have read the user from before it.
+type loads struct {
+	mu       sync.Mutex
+	inFlight map[*load]bool
+}
+
+type load struct {
+	key   string
+	stale bool
+}
+
+func (l *loads) start(key string) *load {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	started := &load{key: key}
+	l.inFlight[started] = true
+	return started
+}
+
+// finish reports whether the load may fill the cache.
+func (l *loads) finish(started *load) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.inFlight, started)
+	return !started.stale
+}
+
+func (l *loads) invalidate(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for inFlight := range l.inFlight {
+		if inFlight.key == key {
+			inFlight.stale = true
+		}
+	}
+}
+
+// detachedContext keeps the values of its parent, such as the logger and
+// the trace, but not its deadline or cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
@@ -0,0 +1,395 @@
+package cached
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"userservice/internal/cache/memory"
+	"userservice/internal/dto/req"
+	"userservice/internal/metrics"
+	"userservice/internal/model"
+	"userservice/internal/repository"
+
+	"golang.org/x/exp/slog"
+)
+
+func TestLookups(t *testing.T) {
+	type step struct {
+		op    string
+		id    int
+		email string
+		// want is the ID of the user the step finds, or 0 for none.
+		want int
+	}
+	tests := []struct {
+		name  string
+		steps []step
+		calls map[string]int
+	}{
+		{
+			name:  "ID cached",
+			steps: []step{{op: "findByID", id: 1, want: 1}, {op: "findByID", id: 1, want: 1}},
+			calls: map[string]int{"FindByID": 1},
+		},
+		{
+			name:  "missing ID remembered",
+			steps: []step{{op: "findByID", id: 9}, {op: "findByID", id: 9}},
+			calls: map[string]int{"FindByID": 1},
+		},
+		{
+			name:  "email cached",
+			steps: []step{{op: "findByEmail", email: "a@example.com", want: 1}, {op: "findByEmail", email: "a@example.com", want: 1}},
+			calls: map[string]int{"FindByEmail": 1, "FindByID": 1},
+		},
+		{
+			name:  "email shares the ID entry",
+			steps: []step{{op: "findByID", id: 1, want: 1}, {op: "findByEmail", email: "a@example.com", want: 1}},
+			calls: map[string]int{"FindByEmail": 1, "FindByID": 1},
+		},
+		{
+			name:  "missing email remembered",
+			steps: []step{{op: "findByEmail", email: "c@example.com"}, {op: "findByEmail", email: "c@example.com"}},
+			calls: map[string]int{"FindByEmail": 1},
+		},
+		{
+			name: "soft delete seen by email",
+			steps: []step{
+				{op: "findByEmail", email: "a@example.com", want: 1},
+				{op: "delete", id: 1},
+				{op: "findByEmail", email: "a@example.com"},
+				{op: "findByID", id: 1},
+			},
+			calls: map[string]int{"FindByEmail": 2, "FindByID": 2},
+		},
+		{
+			name: "create after a missing email",
+			steps: []step{
+				{op: "findByEmail", email: "c@example.com"},
+				{op: "create", email: "c@example.com"},
+				{op: "findByEmail", email: "c@example.com", want: 3},
+			},
+			calls: map[string]int{"FindByEmail": 2, "FindByID": 1},
+		},
+		{
+			name: "email of a deleted user reused",
+			steps: []step{
+				{op: "findByEmail", email: "a@example.com", want: 1},
+				{op: "deletePermanently", id: 1},
+				{op: "create", email: "a@example.com"},
+				{op: "findByEmail", email: "a@example.com", want: 3},
+				{op: "findByID", id: 1},
+			},
+			calls: map[string]int{"FindByEmail": 2, "FindByID": 3},
+		},
+		{
+			name: "suspension seen by both lookups",
+			steps: []step{
+				{op: "findByID", id: 1, want: 1},
+				{op: "suspend", id: 1},
+				{op: "findByEmail", email: "a@example.com", want: 1},
+				{op: "findByID", id: 1, want: 1},
+			},
+			calls: map[string]int{"FindByEmail": 1, "FindByID": 2},
+		},
+		{
+			name: "credentials always read",
+			steps: []step{
+				{op: "findCredentialsByEmail", email: "a@example.com", want: 1},
+				{op: "findCredentialsByEmail", email: "a@example.com", want: 1},
+			},
+			calls: map[string]int{"FindCredentialsByEmail": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeRepository()
+			ur := newTestRepository(fake)
+			ctx := context.Background()
+			for i, s := range tt.steps {
+				var user *model.User
+				var err error
+				switch s.op {
+				case "findByID":
+					user, err = ur.FindByID(ctx, s.id)
+				case "findByEmail":
+					user, err = ur.FindByEmail(ctx, s.email)
+				case "findCredentialsByEmail":
+					user, err = ur.FindCredentialsByEmail(ctx, s.email)
+				case "create":
+					_, err = ur.Create(ctx, &req.UserDto{Email: s.email, Password: "hash"})
+				case "delete":
+					_, err = ur.DeleteByID(ctx, s.id, nil)
+				case "deletePermanently":
+					_, err = ur.DeletePermanentlyByID(ctx, s.id, nil)
+				case "suspend":
+					_, err = ur.Suspend(ctx, s.id, time.Now().Add(time.Hour))
+				}
+				if !strings.HasPrefix(s.op, "find") {
+					if err != nil {
+						t.Fatalf("step %d %s: %v", i, s.op, err)
+					}
+					continue
+				}
+				if s.want == 0 {
+					if !errors.Is(err, &repository.ErrDataNotFound) {
+						t.Fatalf("step %d %s: got %v, %v, want not found", i, s.op, user, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("step %d %s: %v", i, s.op, err)
+				}
+				stored := fake.get(s.want)
+				if user.ID != s.want || user.Version != stored.Version || (user.SuspendedUntil == nil) != (stored.SuspendedUntil == nil) {
+					t.Fatalf("step %d %s: got %+v, want %+v", i, s.op, user, stored)
+				}
+				if wantPassword := s.op == "findCredentialsByEmail"; (user.Password != "") != wantPassword {
+					t.Fatalf("step %d %s: got password %q", i, s.op, user.Password)
+				}
+			}
+			for method, want := range tt.calls {
+				if got := fake.callsOf(method); got != want {
+					t.Errorf("got %d calls of %s, want %d", got, method, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLookupsShareLoad(t *testing.T) {
+	fake := newFakeRepository()
+	fake.gate = make(chan struct{})
+	ur := newTestRepository(fake)
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user, err := ur.FindByID(ctx, 1)
+			if err == nil && user.ID != 1 {
+				err = fmt.Errorf("got user %d", user.ID)
+			}
+			errs <- err
+		}()
+	}
+	<-fake.read
+	// A lookup that gives up does not fail the load it joined.
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := ur.FindByID(cancelled, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v from a cancelled lookup", err)
+	}
+	close(fake.gate)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls := fake.callsOf("FindByID"); calls != 1 {
+		t.Errorf("got %d calls of FindByID, want 1", calls)
+	}
+}
+
+// TestStaleLoad holds a load that read the user before a write until the
+// write is done, which must neither serve the lookups after the write nor
+// fill the cache.
+func TestStaleLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		find  func(ur repository.IUserRepository) (*model.User, error)
+		write func(ur repository.IUserRepository) error
+		// want reports whether a lookup after the write found what it left.
+		want func(user *model.User) bool
+	}{
+		{
+			name: "by ID",
+			find: func(ur repository.IUserRepository) (*model.User, error) { return ur.FindByID(context.Background(), 1) },
+			write: func(ur repository.IUserRepository) error {
+				_, err := ur.Suspend(context.Background(), 1, time.Now().Add(time.Hour))
+				return err
+			},
+			want: func(user *model.User) bool { return user.ID == 1 && user.SuspendedUntil != nil },
+		},
+		{
+			name: "by email",
+			find: func(ur repository.IUserRepository) (*model.User, error) {
+				return ur.FindByEmail(context.Background(), "a@example.com")
+			},
+			write: func(ur repository.IUserRepository) error {
+				if _, err := ur.DeletePermanentlyByID(context.Background(), 1, nil); err != nil {
+					return err
+				}
+				_, err := ur.Create(context.Background(), &req.UserDto{Email: "a@example.com", Password: "hash"})
+				return err
+			},
+			want: func(user *model.User) bool { return user.ID == 3 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeRepository()
+			fake.gate = make(chan struct{})
+			ur := newTestRepository(fake)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				// What the stale lookup itself returns is not checked: it
+				// may be the user before the write.
+				tt.find(ur)
+			}()
+			<-fake.read
+			if err := tt.write(ur); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			check := func(when string) {
+				t.Helper()
+				if user, err := tt.find(ur); err != nil || !tt.want(user) {
+					t.Fatalf("%s: got %+v, %v", when, user, err)
+				}
+			}
+			check("while the stale load runs")
+			close(fake.gate)
+			<-done
+			check("after the stale load")
+		})
+	}
+}
+
+func newTestRepository(fake *fakeRepository) repository.IUserRepository {
+	return NewUserRepository(
+		slog.New(slog.NewTextHandler(io.Discard)),
+		fake,
+		memory.NewStore(100),
+		metrics.New(),
+		Config{TTL: time.Minute, NegativeTTL: time.Minute},
+	)
+}
+
+// fakeRepository keeps users in memory and counts the calls that reach it.
+type fakeRepository struct {
+	mu     sync.Mutex
+	users  map[int]*model.User
+	nextID int
+	calls  map[string]int
+	// gate, when set, holds the first read by ID or email after it has read
+	// the user until it is closed. read is sent to once the user is read.
+	gate chan struct{}
+	read chan struct{}
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		users: map[int]*model.User{
+			1: {ID: 1, Email: "a@example.com", Password: "hash", Role: model.UserRoleUser, Version: 1},
+			2: {ID: 2, Email: "b@example.com", Password: "hash", Role: model.UserRoleUser, Version: 1},
+		},
+		nextID: 3,
+		calls:  map[string]int{},
+		read:   make(chan struct{}, 1),
+	}
+}
+
+func (r *fakeRepository) get(id int) *model.User {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.users[id]
+}
+
+func (r *fakeRepository) callsOf(method string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls[method]
+}
+
+// find returns a copy of the first user match accepts, and holds the first
+// read of a gated repository.
+func (r *fakeRepository) find(method string, match func(user *model.User) bool) (*model.User, error) {
+	r.mu.Lock()
+	r.calls[method]++
+	gated := r.gate != nil && r.calls["FindByID"]+r.calls["FindByEmail"] == 1
+	var found *model.User
+	for _, user := range r.users {
+		if user.DeletedAt == nil && match(user) {
+			copied := *user
+			found = &copied
+		}
+	}
+	r.mu.Unlock()
+	if gated {
+		r.read <- struct{}{}
+		<-r.gate
+	}
+	if found == nil {
+		return nil, repository.ErrDataNotFound.SetError(errors.New("no user"))
+	}
+	return found, nil
+}
+
+func (r *fakeRepository) FindByID(ctx context.Context, id int) (*model.User, error) {
+	return r.find("FindByID", func(user *model.User) bool { return user.ID == id })
+}
+
+func (r *fakeRepository) FindByIDs(ctx context.Context, ids []int) ([]*model.User, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	return r.find("FindByEmail", func(user *model.User) bool { return user.Email == email })
+}
+
+func (r *fakeRepository) FindCredentialsByEmail(ctx context.Context, email string) (*model.User, error) {
+	return r.find("FindCredentialsByEmail", func(user *model.User) bool { return user.Email == email })
+}
+
+func (r *fakeRepository) Create(ctx context.Context, userDto *req.UserDto) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user := &model.User{ID: r.nextID, Email: userDto.Email, Password: userDto.Password, Role: model.UserRoleUser, Version: 1}
+	r.users[user.ID] = user
+	r.nextID++
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeRepository) write(id int, apply func(user *model.User)) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok || user.DeletedAt != nil {
+		return nil, repository.ErrDataNotFound.SetError(errors.New("no user"))
+	}
+	apply(user)
+	user.Version++
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeRepository) DeleteByID(ctx context.Context, id int, versions []int) (*model.User, error) {
+	return r.write(id, func(user *model.User) {
+		now := time.Now()
+		user.DeletedAt = &now
+	})
+}
+
+func (r *fakeRepository) DeletePermanentlyByID(ctx context.Context, id int, versions []int) (*model.User, error) {
+	user, err := r.write(id, func(user *model.User) {})
+	if err == nil {
+		r.mu.Lock()
+		delete(r.users, id)
+		r.mu.Unlock()
+	}
+	return user, err
+}
+
+func (r *fakeRepository) Suspend(ctx context.Context, id int, until time.Time) (*model.User, error) {
+	return r.write(id, func(user *model.User) { user.SuspendedUntil = &until })
+}
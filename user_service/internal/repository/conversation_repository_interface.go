@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"userservice/internal/model"
+)
+
+type IConversationRepository interface {
+	Create(ctx context.Context, userID int, kind string, directKey *string, title *string, participantIDs []int) (*model.Conversation, error)
+	FindByID(ctx context.Context, userID int, id int) (*model.Conversation, error)
+	FindByDirectKey(ctx context.Context, userID int, directKey string) (*model.Conversation, error)
+	FindByUserID(ctx context.Context, userID int, beforeUpdatedAt *time.Time, beforeID int, limit int) ([]*model.Conversation, error)
+	FindParticipants(ctx context.Context, conversationID int) ([]*model.Participant, error)
+	CreateMessage(ctx context.Context, userID int, conversationID int, body string, mediaIDs []string) (*model.Message, error)
+	FindMessages(ctx context.Context, conversationID int, beforeID int, limit int) ([]*model.Message, error)
+	DeleteMessage(ctx context.Context, messageID int) (*model.Message, error)
+	MarkRead(ctx context.Context, userID int, conversationID int, messageID int) error
+	CountUnread(ctx context.Context, userID int) (int, error)
+	ShareConversation(ctx context.Context, userID int, otherUserID int) (bool, error)
+	FindMessagePermissions(ctx context.Context, userIDs []int) (map[int]string, error)
+	UpdateMessagePermission(ctx context.Context, userID int, messagePermission string) (string, error)
+}
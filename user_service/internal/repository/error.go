@@ -7,6 +7,7 @@ type errKind int
 var (
 	ErrUniqueViolation = Error{kind: uniqueViolation}
 	ErrDataNotFound    = Error{kind: dataNotFound}
+	ErrVersionMismatch = Error{kind: versionMismatch}
 	ErrUnknown         = Error{kind: unknown}
 )
 
@@ -14,6 +15,7 @@ const (
 	_ errKind = iota
 	uniqueViolation
 	dataNotFound
+	versionMismatch
 	unknown
 )
 
@@ -28,6 +30,8 @@ func (e *Error) Error() string {
 		return fmt.Sprintf("Unique Violation %v", e.err)
 	case dataNotFound:
 		return fmt.Sprintf("Data Not Found %v", e.err)
+	case versionMismatch:
+		return fmt.Sprintf("Version Mismatch %v", e.err)
 	default:
 		return fmt.Sprintf("Unknown Error %v", e.err)
 	}
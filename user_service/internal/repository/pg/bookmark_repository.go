@@ -0,0 +1,470 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"userservice/internal/logging"
+	"userservice/internal/model"
+	"userservice/internal/repository"
+	"userservice/internal/repository/sqltype"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"golang.org/x/exp/slog"
+)
+
+const collectionColumns = `
+	c."id", c."user_id", c."name", c."is_private", c."created_at", c."updated_at",
+	(
+		SELECT COUNT(*)
+		FROM "bookmarks_tab" b
+		WHERE b."collection_id" = c."id"
+	)
+`
+
+const bookmarkColumns = `b."id", b."user_id", b."item_type", b."item_id", b."collection_id", b."created_at"`
+
+// visibleBookmark hides bookmarks of soft deleted users. Hard deleted items
+// are removed by triggers instead.
+const visibleBookmark = `
+	NOT EXISTS (
+		SELECT 1
+		FROM "users_tab" u
+		WHERE b."item_type" = 'user' AND u."id" = b."item_id" AND u."deleted_at" IS NOT NULL
+	)
+`
+
+type bookmarkRepository struct {
+	logger *slog.Logger
+	db     *sql.DB
+}
+
+func NewBookmarkRepository(logger *slog.Logger, db *sql.DB) repository.IBookmarkRepository {
+	return &bookmarkRepository{
+		logger: logger,
+		db:     db,
+	}
+}
+
+func (br bookmarkRepository) scanCollection(row rowScanner) (*model.Collection, error) {
+	collection := &sqltype.Collection{}
+	err := row.Scan(
+		&collection.ID,
+		&collection.UserID,
+		&collection.Name,
+		&collection.IsPrivate,
+		&collection.CreatedAt,
+		&collection.UpdatedAt,
+		&collection.BookmarkCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collection.ToModel(), nil
+}
+
+func (br bookmarkRepository) scanBookmark(row rowScanner) (*model.Bookmark, error) {
+	bookmark := &sqltype.Bookmark{}
+	err := row.Scan(
+		&bookmark.ID,
+		&bookmark.UserID,
+		&bookmark.ItemType,
+		&bookmark.ItemID,
+		&bookmark.CollectionID,
+		&bookmark.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return bookmark.ToModel(), nil
+}
+
+func (br bookmarkRepository) CreateCollection(ctx context.Context, userID int, name string, isPrivate bool) (*model.Collection, error) {
+	const scope = "bookmarkRepository#CreateCollection"
+	now := time.Now()
+	collection, err := br.scanCollection(br.db.QueryRowContext(
+		ctx,
+		`
+			INSERT INTO "bookmark_collections_tab" AS c ("user_id", "name", "is_private", "created_at", "updated_at")
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING `+collectionColumns+`;
+		`,
+		userID,
+		name,
+		isPrivate,
+		now,
+		now,
+	))
+	if err != nil {
+		logging.FromContext(ctx, br.logger).Error(
+			"Failed to create a bookmark collection",
+			err,
+			slog.String("scope", scope),
+		)
+		pgError, ok := err.(*pgconn.PgError)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+		}
+		if pgError.Code == pgerrcode.UniqueViolation {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrUniqueViolation.SetError(pgError))
+		}
+		if pgError.Code == pgerrcode.ForeignKeyViolation {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(pgError))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(pgError))
+	}
+	logging.FromContext(ctx, br.logger).Info(
+		"Created a bookmark collection",
+		slog.String("scope", scope),
+	)
+	return collection, nil
+}
+
+func (br bookmarkRepository) FindCollectionByID(ctx context.Context, id int) (*model.Collection, error) {
+	const scope = "bookmarkRepository#FindCollectionByID"
+	collection, err := br.scanCollection(br.db.QueryRowContext(
+		ctx,
+		`
+			SELECT `+collectionColumns+`
+			FROM "bookmark_collections_tab" c
+			WHERE c."id" = $1;
+		`,
+		id,
+	))
+	if err != nil {
+		logging.FromContext(ctx, br.logger).Error(
+			"Failed to find a bookmark collection by its ID",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, br.logger).Info(
+		"Found a bookmark collection by its ID",
+		slog.String("scope", scope),
+	)
+	return collection, nil
+}
+
+func (br bookmarkRepository) FindCollectionsByUserID(ctx context.Context, userID int, includePrivate bool, beforeID int, limit int) ([]*model.Collection, error) {
+	const scope = "bookmarkRepository#FindCollectionsByUserID"
+	collections := []*model.Collection{}
+	err := func() error {
+		rows, err := br.db.QueryContext(
+			ctx,
+			`
+				SELECT `+collectionColumns+`
+				FROM "bookmark_collections_tab" c
+				WHERE c."user_id" = $1 AND ($2 OR NOT c."is_private") AND ($3 = 0 OR c."id" < $3)
+				ORDER BY c."id" DESC
+				LIMIT $4;
+			`,
+			userID,
+			includePrivate,
+			beforeID,
+			limit,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			collection, err := br.scanCollection(rows)
+			if err != nil {
+				return err
+			}
+			collections = append(collections, collection)
+		}
+		return rows.Err()
+	}()
+	if err != nil {
+		logging.FromContext(ctx, br.logger).Error(
+			"Failed to find bookmark collections by user ID",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, br.logger).Info(
+		"Found bookmark collections by user ID",
+		slog.String("scope", scope),
+	)
+	return collections, nil
+}
+
+func (br bookmarkRepository) UpdateCollection(ctx context.Context, userID int, id int, name *string, isPrivate *bool) (*model.Collection, error) {
+	const scope = "bookmarkRepository#UpdateCollection"
+	collection, err := br.scanCollection(br.db.QueryRowContext(
+		ctx,
+		`
+			UPDATE "bookmark_collections_tab" AS c
+			SET "name" = COALESCE($1, c."name"), "is_private" = COALESCE($2, c."is_private"), "updated_at" = $3
+			WHERE c."id" = $4 AND c."user_id" = $5
+			RETURNING `+collectionColumns+`;
+		`,
+		name,
+		isPrivate,
+		time.Now(),
+		id,
+		userID,
+	))
+	if err != nil {
+		logging.FromContext(ctx, br.logger).Error(
+			"Failed to update a bookmark collection",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+		}
+		pgError, ok := err.(*pgconn.PgError)
+		if ok && pgError.Code == pgerrcode.UniqueViolation {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrUniqueViolation.SetError(pgError))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, br.logger).Info(
+		"Updated a bookmark collection",
+		slog.String("scope", scope),
+	)
+	return collection, nil
+}
+
+// DeleteCollection keeps the bookmarks of the collection, they become
+// uncollected through ON DELETE SET NULL.
+func (br bookmarkRepository) DeleteCollection(ctx context.Context, userID int, id int) error {
+	const scope = "bookmarkRepository#DeleteCollection"
+	result, err := br.db.ExecContext(
+		ctx,
+		`
+			DELETE FROM "bookmark_collections_tab"
+			WHERE "id" = $1 AND "user_id" = $2;
+		`,
+		id,
+		userID,
+	)
+	if err == nil {
+		var affected int64
+		affected, err = result.RowsAffected()
+		if err == nil && affected == 0 {
+			err = sql.ErrNoRows
+		}
+	}
+	if err != nil {
+		logging.FromContext(ctx, br.logger).Error(
+			"Failed to delete a bookmark collection",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+		}
+		return fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, br.logger).Info(
+		"Deleted a bookmark collection",
+		slog.String("scope", scope),
+	)
+	return nil
+}
+
+// ItemExists tells whether the item exists and userID is allowed to see it.
+// Messages are only visible to participants of their conversation.
+func (br bookmarkRepository) ItemExists(ctx context.Context, userID int, itemType string, itemID int) (bool, error) {
+	const scope = "bookmarkRepository#ItemExists"
+	var row *sql.Row
+	switch itemType {
+	case model.BookmarkItemTypeUser:
+		row = br.db.QueryRowContext(
+			ctx,
+			`
+				SELECT EXISTS (
+					SELECT 1
+					FROM "users_tab"
+					WHERE "id" = $1 AND "deleted_at" IS NULL
+				);
+			`,
+			itemID,
+		)
+	case model.BookmarkItemTypeMessage:
+		row = br.db.QueryRowContext(
+			ctx,
+			`
+				SELECT EXISTS (
+					SELECT 1
+					FROM "messages_tab" m
+					JOIN "conversation_participants_tab" p ON p."conversation_id" = m."conversation_id" AND p."user_id" = $1
+					WHERE m."id" = $2
+				);
+			`,
+			userID,
+			itemID,
+		)
+	default:
+		return false, nil
+	}
+	var exists bool
+	if err := row.Scan(&exists); err != nil {
+		logging.FromContext(ctx, br.logger).Error(
+			"Failed to check a bookmarked item",
+			err,
+			slog.String("scope", scope),
+		)
+		return false, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	return exists, nil
+}
+
+// UpsertBookmark moves the bookmark to collectionID when the item is already
+// bookmarked.
+func (br bookmarkRepository) UpsertBookmark(ctx context.Context, userID int, itemType string, itemID int, collectionID *int) (*model.Bookmark, error) {
+	const scope = "bookmarkRepository#UpsertBookmark"
+	bookmark, err := br.scanBookmark(br.db.QueryRowContext(
+		ctx,
+		`
+			INSERT INTO "bookmarks_tab" AS b ("user_id", "item_type", "item_id", "collection_id", "created_at")
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT ("user_id", "item_type", "item_id") DO UPDATE SET "collection_id" = EXCLUDED."collection_id"
+			RETURNING `+bookmarkColumns+`;
+		`,
+		userID,
+		itemType,
+		itemID,
+		collectionID,
+		time.Now(),
+	))
+	if err != nil {
+		logging.FromContext(ctx, br.logger).Error(
+			"Failed to upsert a bookmark",
+			err,
+			slog.String("scope", scope),
+		)
+		pgError, ok := err.(*pgconn.PgError)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+		}
+		if pgError.Code == pgerrcode.ForeignKeyViolation {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(pgError))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(pgError))
+	}
+	logging.FromContext(ctx, br.logger).Info(
+		"Upserted a bookmark",
+		slog.String("scope", scope),
+	)
+	return bookmark, nil
+}
+
+func (br bookmarkRepository) DeleteBookmark(ctx context.Context, userID int, itemType string, itemID int) (*model.Bookmark, error) {
+	const scope = "bookmarkRepository#DeleteBookmark"
+	bookmark, err := br.scanBookmark(br.db.QueryRowContext(
+		ctx,
+		`
+			DELETE FROM "bookmarks_tab" AS b
+			WHERE b."user_id" = $1 AND b."item_type" = $2 AND b."item_id" = $3
+			RETURNING `+bookmarkColumns+`;
+		`,
+		userID,
+		itemType,
+		itemID,
+	))
+	if err != nil {
+		logging.FromContext(ctx, br.logger).Error(
+			"Failed to delete a bookmark",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, br.logger).Info(
+		"Deleted a bookmark",
+		slog.String("scope", scope),
+	)
+	return bookmark, nil
+}
+
+func (br bookmarkRepository) FindBookmarksByUserID(ctx context.Context, userID int, beforeID int, limit int) ([]*model.Bookmark, error) {
+	const scope = "bookmarkRepository#FindBookmarksByUserID"
+	bookmarks, err := br.findBookmarks(
+		ctx,
+		`
+			SELECT `+bookmarkColumns+`
+			FROM "bookmarks_tab" b
+			WHERE b."user_id" = $1 AND ($2 = 0 OR b."id" < $2) AND `+visibleBookmark+`
+			ORDER BY b."id" DESC
+			LIMIT $3;
+		`,
+		userID,
+		beforeID,
+		limit,
+	)
+	if err != nil {
+		logging.FromContext(ctx, br.logger).Error(
+			"Failed to find bookmarks by user ID",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, br.logger).Info(
+		"Found bookmarks by user ID",
+		slog.String("scope", scope),
+	)
+	return bookmarks, nil
+}
+
+func (br bookmarkRepository) FindBookmarksByCollectionID(ctx context.Context, collectionID int, beforeID int, limit int) ([]*model.Bookmark, error) {
+	const scope = "bookmarkRepository#FindBookmarksByCollectionID"
+	bookmarks, err := br.findBookmarks(
+		ctx,
+		`
+			SELECT `+bookmarkColumns+`
+			FROM "bookmarks_tab" b
+			WHERE b."collection_id" = $1 AND ($2 = 0 OR b."id" < $2) AND `+visibleBookmark+`
+			ORDER BY b."id" DESC
+			LIMIT $3;
+		`,
+		collectionID,
+		beforeID,
+		limit,
+	)
+	if err != nil {
+		logging.FromContext(ctx, br.logger).Error(
+			"Failed to find bookmarks by collection ID",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, br.logger).Info(
+		"Found bookmarks by collection ID",
+		slog.String("scope", scope),
+	)
+	return bookmarks, nil
+}
+
+func (br bookmarkRepository) findBookmarks(ctx context.Context, query string, args ...any) ([]*model.Bookmark, error) {
+	bookmarks := []*model.Bookmark{}
+	rows, err := br.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		bookmark, err := br.scanBookmark(rows)
+		if err != nil {
+			return nil, err
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+	return bookmarks, rows.Err()
+}
@@ -0,0 +1,648 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"userservice/internal/logging"
+	"userservice/internal/model"
+	"userservice/internal/repository"
+	"userservice/internal/repository/sqltype"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/exp/slog"
+)
+
+// conversationColumns selects a conversation as seen by the participant
+// joined as "p", including the unread count of that participant.
+const conversationColumns = `
+	c."id", c."kind", c."title", c."last_message_id", c."created_at", c."updated_at",
+	ARRAY(
+		SELECT cp."user_id"
+		FROM "conversation_participants_tab" cp
+		WHERE cp."conversation_id" = c."id"
+		ORDER BY cp."user_id"
+	),
+	(
+		SELECT COUNT(*)
+		FROM "messages_tab" m
+		WHERE m."conversation_id" = c."id" AND m."id" > p."last_read_message_id" AND m."sender_id" IS DISTINCT FROM p."user_id"
+	)
+`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type conversationRepository struct {
+	logger  *slog.Logger
+	db      *sql.DB
+	typeMap *pgtype.Map
+}
+
+func NewConversationRepository(logger *slog.Logger, db *sql.DB) repository.IConversationRepository {
+	return &conversationRepository{
+		logger:  logger,
+		db:      db,
+		typeMap: pgtype.NewMap(),
+	}
+}
+
+func (cr conversationRepository) scanConversation(row rowScanner) (*model.Conversation, error) {
+	conversation := &sqltype.Conversation{}
+	err := row.Scan(
+		&conversation.ID,
+		&conversation.Kind,
+		&conversation.Title,
+		&conversation.LastMessageID,
+		&conversation.CreatedAt,
+		&conversation.UpdatedAt,
+		cr.typeMap.SQLScanner(&conversation.ParticipantIDs),
+		&conversation.UnreadCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return conversation.ToModel(), nil
+}
+
+func (cr conversationRepository) scanMessage(row rowScanner) (*model.Message, error) {
+	message := &sqltype.Message{}
+	err := row.Scan(
+		&message.ID,
+		&message.ConversationID,
+		&message.SenderID,
+		&message.Body,
+		cr.typeMap.SQLScanner(&message.MediaIDs),
+		&message.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return message.ToModel(), nil
+}
+
+func (cr conversationRepository) Create(ctx context.Context, userID int, kind string, directKey *string, title *string, participantIDs []int) (*model.Conversation, error) {
+	const scope = "conversationRepository#Create"
+	var conversationID int
+	err := func() error {
+		tx, err := cr.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		now := time.Now()
+		err = tx.QueryRowContext(
+			ctx,
+			`
+				INSERT INTO "conversations_tab" ("kind", "title", "direct_key", "created_by", "created_at", "updated_at")
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING "id";
+			`,
+			kind,
+			title,
+			directKey,
+			userID,
+			now,
+			now,
+		).Scan(&conversationID)
+		if err != nil {
+			return err
+		}
+		for _, participantID := range append([]int{userID}, participantIDs...) {
+			_, err = tx.ExecContext(
+				ctx,
+				`
+					INSERT INTO "conversation_participants_tab" ("conversation_id", "user_id", "joined_at")
+					VALUES ($1, $2, $3);
+				`,
+				conversationID,
+				participantID,
+				now,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	}()
+	if err != nil {
+		logging.FromContext(ctx, cr.logger).Error(
+			"Failed to create a conversation",
+			err,
+			slog.String("scope", scope),
+		)
+		pgError, ok := err.(*pgconn.PgError)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+		}
+		if pgError.Code == pgerrcode.UniqueViolation {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrUniqueViolation.SetError(pgError))
+		}
+		if pgError.Code == pgerrcode.ForeignKeyViolation {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(pgError))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(pgError))
+	}
+	logging.FromContext(ctx, cr.logger).Info(
+		"Created a conversation",
+		slog.String("scope", scope),
+	)
+	return cr.FindByID(ctx, userID, conversationID)
+}
+
+func (cr conversationRepository) FindByID(ctx context.Context, userID int, id int) (*model.Conversation, error) {
+	const scope = "conversationRepository#FindByID"
+	conversation, err := cr.scanConversation(cr.db.QueryRowContext(
+		ctx,
+		`
+			SELECT `+conversationColumns+`
+			FROM "conversations_tab" c
+			JOIN "conversation_participants_tab" p ON p."conversation_id" = c."id" AND p."user_id" = $1
+			WHERE c."id" = $2;
+		`,
+		userID,
+		id,
+	))
+	if err != nil {
+		logging.FromContext(ctx, cr.logger).Error(
+			"Failed to find a conversation by its ID",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cr.logger).Info(
+		"Found a conversation by its ID",
+		slog.String("scope", scope),
+	)
+	return conversation, nil
+}
+
+func (cr conversationRepository) FindByDirectKey(ctx context.Context, userID int, directKey string) (*model.Conversation, error) {
+	const scope = "conversationRepository#FindByDirectKey"
+	conversation, err := cr.scanConversation(cr.db.QueryRowContext(
+		ctx,
+		`
+			SELECT `+conversationColumns+`
+			FROM "conversations_tab" c
+			JOIN "conversation_participants_tab" p ON p."conversation_id" = c."id" AND p."user_id" = $1
+			WHERE c."direct_key" = $2;
+		`,
+		userID,
+		directKey,
+	))
+	if err != nil {
+		logging.FromContext(ctx, cr.logger).Error(
+			"Failed to find a conversation by its direct key",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cr.logger).Info(
+		"Found a conversation by its direct key",
+		slog.String("scope", scope),
+	)
+	return conversation, nil
+}
+
+func (cr conversationRepository) FindByUserID(ctx context.Context, userID int, beforeUpdatedAt *time.Time, beforeID int, limit int) ([]*model.Conversation, error) {
+	const scope = "conversationRepository#FindByUserID"
+	conversations := []*model.Conversation{}
+	err := func() error {
+		rows, err := cr.db.QueryContext(
+			ctx,
+			`
+				SELECT `+conversationColumns+`
+				FROM "conversations_tab" c
+				JOIN "conversation_participants_tab" p ON p."conversation_id" = c."id" AND p."user_id" = $1
+				WHERE $2::TIMESTAMP IS NULL OR (c."updated_at", c."id") < ($2, $3)
+				ORDER BY c."updated_at" DESC, c."id" DESC
+				LIMIT $4;
+			`,
+			userID,
+			beforeUpdatedAt,
+			beforeID,
+			limit,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			conversation, err := cr.scanConversation(rows)
+			if err != nil {
+				return err
+			}
+			conversations = append(conversations, conversation)
+		}
+		return rows.Err()
+	}()
+	if err != nil {
+		logging.FromContext(ctx, cr.logger).Error(
+			"Failed to find conversations by user ID",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cr.logger).Info(
+		"Found conversations by user ID",
+		slog.String("scope", scope),
+	)
+	return conversations, nil
+}
+
+func (cr conversationRepository) FindParticipants(ctx context.Context, conversationID int) ([]*model.Participant, error) {
+	const scope = "conversationRepository#FindParticipants"
+	participants := []*model.Participant{}
+	err := func() error {
+		rows, err := cr.db.QueryContext(
+			ctx,
+			`
+				SELECT "user_id", "last_read_message_id"
+				FROM "conversation_participants_tab"
+				WHERE "conversation_id" = $1
+				ORDER BY "user_id";
+			`,
+			conversationID,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			participant := &model.Participant{}
+			if err := rows.Scan(&participant.UserID, &participant.LastReadMessageID); err != nil {
+				return err
+			}
+			participants = append(participants, participant)
+		}
+		return rows.Err()
+	}()
+	if err != nil {
+		logging.FromContext(ctx, cr.logger).Error(
+			"Failed to find participants of a conversation",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cr.logger).Info(
+		"Found participants of a conversation",
+		slog.String("scope", scope),
+	)
+	return participants, nil
+}
+
+func (cr conversationRepository) CreateMessage(ctx context.Context, userID int, conversationID int, body string, mediaIDs []string) (*model.Message, error) {
+	const scope = "conversationRepository#CreateMessage"
+	if mediaIDs == nil {
+		mediaIDs = []string{}
+	}
+	var message *model.Message
+	err := func() error {
+		tx, err := cr.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		now := time.Now()
+		message, err = cr.scanMessage(tx.QueryRowContext(
+			ctx,
+			`
+				INSERT INTO "messages_tab" ("conversation_id", "sender_id", "body", "media_ids", "created_at")
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING "id", "conversation_id", "sender_id", "body", "media_ids", "created_at";
+			`,
+			conversationID,
+			userID,
+			body,
+			mediaIDs,
+			now,
+		))
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			`
+				UPDATE "conversations_tab"
+				SET "last_message_id" = $1, "updated_at" = $2
+				WHERE "id" = $3;
+			`,
+			message.ID,
+			now,
+			conversationID,
+		)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			`
+				UPDATE "conversation_participants_tab"
+				SET "last_read_message_id" = $1
+				WHERE "conversation_id" = $2 AND "user_id" = $3;
+			`,
+			message.ID,
+			conversationID,
+			userID,
+		)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	}()
+	if err != nil {
+		logging.FromContext(ctx, cr.logger).Error(
+			"Failed to create a message",
+			err,
+			slog.String("scope", scope),
+		)
+		pgError, ok := err.(*pgconn.PgError)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+		}
+		if pgError.Code == pgerrcode.ForeignKeyViolation {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(pgError))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(pgError))
+	}
+	logging.FromContext(ctx, cr.logger).Info(
+		"Created a message",
+		slog.String("scope", scope),
+	)
+	return message, nil
+}
+
+func (cr conversationRepository) FindMessages(ctx context.Context, conversationID int, beforeID int, limit int) ([]*model.Message, error) {
+	const scope = "conversationRepository#FindMessages"
+	messages := []*model.Message{}
+	err := func() error {
+		rows, err := cr.db.QueryContext(
+			ctx,
+			`
+				SELECT "id", "conversation_id", "sender_id", "body", "media_ids", "created_at"
+				FROM "messages_tab"
+				WHERE "conversation_id" = $1 AND ($2 = 0 OR "id" < $2)
+				ORDER BY "id" DESC
+				LIMIT $3;
+			`,
+			conversationID,
+			beforeID,
+			limit,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			message, err := cr.scanMessage(rows)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, message)
+		}
+		return rows.Err()
+	}()
+	if err != nil {
+		logging.FromContext(ctx, cr.logger).Error(
+			"Failed to find messages of a conversation",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cr.logger).Info(
+		"Found messages of a conversation",
+		slog.String("scope", scope),
+	)
+	return messages, nil
+}
+
+// MarkRead moves the read marker of the user forward to messageID, or to
+// the latest message when messageID is 0. It never moves it backward.
+func (cr conversationRepository) MarkRead(ctx context.Context, userID int, conversationID int, messageID int) error {
+	const scope = "conversationRepository#MarkRead"
+	_, err := cr.db.ExecContext(
+		ctx,
+		`
+			UPDATE "conversation_participants_tab" p
+			SET "last_read_message_id" = GREATEST(p."last_read_message_id", LEAST(COALESCE(NULLIF($1, 0), c."last_message_id"), c."last_message_id"))
+			FROM "conversations_tab" c
+			WHERE c."id" = p."conversation_id" AND p."conversation_id" = $2 AND p."user_id" = $3 AND c."last_message_id" IS NOT NULL;
+		`,
+		messageID,
+		conversationID,
+		userID,
+	)
+	if err != nil {
+		logging.FromContext(ctx, cr.logger).Error(
+			"Failed to mark a conversation as read",
+			err,
+			slog.String("scope", scope),
+		)
+		return fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cr.logger).Info(
+		"Marked a conversation as read",
+		slog.String("scope", scope),
+	)
+	return nil
+}
+
+func (cr conversationRepository) CountUnread(ctx context.Context, userID int) (int, error) {
+	const scope = "conversationRepository#CountUnread"
+	var unreadCount int
+	err := cr.db.QueryRowContext(
+		ctx,
+		`
+			SELECT COUNT(*)
+			FROM "messages_tab" m
+			JOIN "conversation_participants_tab" p ON p."conversation_id" = m."conversation_id"
+			WHERE p."user_id" = $1 AND m."id" > p."last_read_message_id" AND m."sender_id" IS DISTINCT FROM p."user_id";
+		`,
+		userID,
+	).Scan(&unreadCount)
+	if err != nil {
+		logging.FromContext(ctx, cr.logger).Error(
+			"Failed to count unread messages",
+			err,
+			slog.String("scope", scope),
+		)
+		return 0, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cr.logger).Info(
+		"Counted unread messages",
+		slog.String("scope", scope),
+	)
+	return unreadCount, nil
+}
+
+func (cr conversationRepository) ShareConversation(ctx context.Context, userID int, otherUserID int) (bool, error) {
+	const scope = "conversationRepository#ShareConversation"
+	var shared bool
+	err := cr.db.QueryRowContext(
+		ctx,
+		`
+			SELECT EXISTS (
+				SELECT 1
+				FROM "conversation_participants_tab" p1
+				JOIN "conversation_participants_tab" p2 ON p2."conversation_id" = p1."conversation_id"
+				WHERE p1."user_id" = $1 AND p2."user_id" = $2
+			);
+		`,
+		userID,
+		otherUserID,
+	).Scan(&shared)
+	if err != nil {
+		logging.FromContext(ctx, cr.logger).Error(
+			"Failed to check shared conversations",
+			err,
+			slog.String("scope", scope),
+		)
+		return false, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	return shared, nil
+}
+
+func (cr conversationRepository) FindMessagePermissions(ctx context.Context, userIDs []int) (map[int]string, error) {
+	const scope = "conversationRepository#FindMessagePermissions"
+	messagePermissions := map[int]string{}
+	err := func() error {
+		rows, err := cr.db.QueryContext(
+			ctx,
+			`
+				SELECT "id", "message_permission"
+				FROM "users_tab"
+				WHERE "id" = ANY($1) AND "deleted_at" IS NULL;
+			`,
+			userIDs,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var userID int
+			var messagePermission string
+			if err := rows.Scan(&userID, &messagePermission); err != nil {
+				return err
+			}
+			messagePermissions[userID] = messagePermission
+		}
+		return rows.Err()
+	}()
+	if err != nil {
+		logging.FromContext(ctx, cr.logger).Error(
+			"Failed to find message permissions",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	return messagePermissions, nil
+}
+
+func (cr conversationRepository) UpdateMessagePermission(ctx context.Context, userID int, messagePermission string) (string, error) {
+	const scope = "conversationRepository#UpdateMessagePermission"
+	var updatedMessagePermission string
+	err := cr.db.QueryRowContext(
+		ctx,
+		`
+			UPDATE "users_tab"
+			SET "message_permission" = $1
+			WHERE "id" = $2 AND "deleted_at" IS NULL
+			RETURNING "message_permission";
+		`,
+		messagePermission,
+		userID,
+	).Scan(&updatedMessagePermission)
+	if err != nil {
+		logging.FromContext(ctx, cr.logger).Error(
+			"Failed to update message permission",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+		}
+		return "", fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cr.logger).Info(
+		"Updated message permission",
+		slog.String("scope", scope),
+	)
+	return updatedMessagePermission, nil
+}
+
+// DeleteMessage points the conversation back to its newest remaining message.
+func (cr conversationRepository) DeleteMessage(ctx context.Context, messageID int) (*model.Message, error) {
+	const scope = "conversationRepository#DeleteMessage"
+	var message *model.Message
+	err := func() error {
+		tx, err := cr.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		message, err = cr.scanMessage(tx.QueryRowContext(
+			ctx,
+			`
+				DELETE FROM "messages_tab"
+				WHERE "id" = $1
+				RETURNING "id", "conversation_id", "sender_id", "body", "media_ids", "created_at";
+			`,
+			messageID,
+		))
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			`
+				UPDATE "conversations_tab" c
+				SET "last_message_id" = (
+					SELECT MAX(m."id")
+					FROM "messages_tab" m
+					WHERE m."conversation_id" = c."id"
+				)
+				WHERE c."id" = $1 AND c."last_message_id" = $2;
+			`,
+			message.ConversationID,
+			message.ID,
+		)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	}()
+	if err != nil {
+		logging.FromContext(ctx, cr.logger).Error(
+			"Failed to delete a message",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cr.logger).Info(
+		"Deleted a message",
+		slog.String("scope", scope),
+	)
+	return message, nil
+}
@@ -0,0 +1,428 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"userservice/internal/logging"
+	"userservice/internal/model"
+	"userservice/internal/repository"
+	"userservice/internal/repository/sqltype"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"golang.org/x/exp/slog"
+)
+
+const reportColumns = `
+	"id", "reporter_id", "target_type", "target_id", "reason_code", "details",
+	"status", "resolution", "resolved_by", "resolved_at", "created_at"
+`
+
+const moderationActionColumns = `
+	"id", "report_id", "moderator_id", "action", "target_type", "target_id",
+	"target_user_id", "note", "suspended_until", "created_at"
+`
+
+type reportRepository struct {
+	logger *slog.Logger
+	db     *sql.DB
+}
+
+func NewReportRepository(logger *slog.Logger, db *sql.DB) repository.IReportRepository {
+	return &reportRepository{
+		logger: logger,
+		db:     db,
+	}
+}
+
+func (rr reportRepository) scanReport(row rowScanner) (*model.Report, error) {
+	report := &sqltype.Report{}
+	err := row.Scan(
+		&report.ID,
+		&report.ReporterID,
+		&report.TargetType,
+		&report.TargetID,
+		&report.ReasonCode,
+		&report.Details,
+		&report.Status,
+		&report.Resolution,
+		&report.ResolvedBy,
+		&report.ResolvedAt,
+		&report.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return report.ToModel(), nil
+}
+
+func (rr reportRepository) scanModerationAction(row rowScanner) (*model.ModerationAction, error) {
+	action := &sqltype.ModerationAction{}
+	err := row.Scan(
+		&action.ID,
+		&action.ReportID,
+		&action.ModeratorID,
+		&action.Action,
+		&action.TargetType,
+		&action.TargetID,
+		&action.TargetUserID,
+		&action.Note,
+		&action.SuspendedUntil,
+		&action.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return action.ToModel(), nil
+}
+
+func (rr reportRepository) CreateReport(ctx context.Context, reporterID int, targetType string, targetID int, reasonCode string, details *string) (*model.Report, error) {
+	const scope = "reportRepository#CreateReport"
+	report, err := rr.scanReport(rr.db.QueryRowContext(
+		ctx,
+		`
+			INSERT INTO "reports_tab" ("reporter_id", "target_type", "target_id", "reason_code", "details", "created_at")
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING `+reportColumns+`;
+		`,
+		reporterID,
+		targetType,
+		targetID,
+		reasonCode,
+		details,
+		time.Now(),
+	))
+	if err != nil {
+		logging.FromContext(ctx, rr.logger).Error(
+			"Failed to create a report",
+			err,
+			slog.String("scope", scope),
+		)
+		pgError, ok := err.(*pgconn.PgError)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+		}
+		if pgError.Code == pgerrcode.UniqueViolation {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrUniqueViolation.SetError(pgError))
+		}
+		if pgError.Code == pgerrcode.ForeignKeyViolation {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(pgError))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(pgError))
+	}
+	logging.FromContext(ctx, rr.logger).Info(
+		"Created a report",
+		slog.String("scope", scope),
+	)
+	return report, nil
+}
+
+func (rr reportRepository) FindReportByID(ctx context.Context, id int) (*model.Report, error) {
+	const scope = "reportRepository#FindReportByID"
+	report, err := rr.scanReport(rr.db.QueryRowContext(
+		ctx,
+		`
+			SELECT `+reportColumns+`
+			FROM "reports_tab"
+			WHERE "id" = $1;
+		`,
+		id,
+	))
+	if err != nil {
+		logging.FromContext(ctx, rr.logger).Error(
+			"Failed to find a report by its ID",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, rr.logger).Info(
+		"Found a report by its ID",
+		slog.String("scope", scope),
+	)
+	return report, nil
+}
+
+// FindReports returns the oldest reports first, so the queue is worked
+// through in the order it was filled.
+func (rr reportRepository) FindReports(ctx context.Context, status string, afterID int, limit int) ([]*model.Report, error) {
+	const scope = "reportRepository#FindReports"
+	reports := []*model.Report{}
+	err := func() error {
+		rows, err := rr.db.QueryContext(
+			ctx,
+			`
+				SELECT `+reportColumns+`
+				FROM "reports_tab"
+				WHERE "status" = $1 AND "id" > $2
+				ORDER BY "id" ASC
+				LIMIT $3;
+			`,
+			status,
+			afterID,
+			limit,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			report, err := rr.scanReport(rows)
+			if err != nil {
+				return err
+			}
+			reports = append(reports, report)
+		}
+		return rows.Err()
+	}()
+	if err != nil {
+		logging.FromContext(ctx, rr.logger).Error(
+			"Failed to find reports",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, rr.logger).Info(
+		"Found reports",
+		slog.String("scope", scope),
+	)
+	return reports, nil
+}
+
+// TargetExists tells whether the target exists and userID is allowed to see
+// it. Messages are only visible to participants of their conversation.
+func (rr reportRepository) TargetExists(ctx context.Context, userID int, targetType string, targetID int) (bool, error) {
+	const scope = "reportRepository#TargetExists"
+	var row *sql.Row
+	switch targetType {
+	case model.ReportTargetTypeUser:
+		row = rr.db.QueryRowContext(
+			ctx,
+			`
+				SELECT EXISTS (
+					SELECT 1
+					FROM "users_tab"
+					WHERE "id" = $1 AND "deleted_at" IS NULL
+				);
+			`,
+			targetID,
+		)
+	case model.ReportTargetTypeMessage:
+		row = rr.db.QueryRowContext(
+			ctx,
+			`
+				SELECT EXISTS (
+					SELECT 1
+					FROM "messages_tab" m
+					JOIN "conversation_participants_tab" p ON p."conversation_id" = m."conversation_id" AND p."user_id" = $1
+					WHERE m."id" = $2
+				);
+			`,
+			userID,
+			targetID,
+		)
+	default:
+		return false, nil
+	}
+	var exists bool
+	if err := row.Scan(&exists); err != nil {
+		logging.FromContext(ctx, rr.logger).Error(
+			"Failed to check a reported target",
+			err,
+			slog.String("scope", scope),
+		)
+		return false, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	return exists, nil
+}
+
+// TargetOwnerID returns the user responsible for the target, which is nil
+// when the sender of a message no longer exists.
+func (rr reportRepository) TargetOwnerID(ctx context.Context, targetType string, targetID int) (*int, error) {
+	const scope = "reportRepository#TargetOwnerID"
+	var ownerID sql.NullInt64
+	var err error
+	switch targetType {
+	case model.ReportTargetTypeUser:
+		err = rr.db.QueryRowContext(
+			ctx,
+			`
+				SELECT "id"
+				FROM "users_tab"
+				WHERE "id" = $1 AND "deleted_at" IS NULL;
+			`,
+			targetID,
+		).Scan(&ownerID)
+	case model.ReportTargetTypeMessage:
+		err = rr.db.QueryRowContext(
+			ctx,
+			`
+				SELECT "sender_id"
+				FROM "messages_tab"
+				WHERE "id" = $1;
+			`,
+			targetID,
+		).Scan(&ownerID)
+	default:
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		logging.FromContext(ctx, rr.logger).Error(
+			"Failed to find the owner of a reported target",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	if !ownerID.Valid {
+		return nil, nil
+	}
+	result := int(ownerID.Int64)
+	return &result, nil
+}
+
+// Resolve logs the action and closes the report together with every other
+// open report on the same target. It returns ErrDataNotFound when the
+// report is no longer open.
+func (rr reportRepository) Resolve(ctx context.Context, reportID int, action *model.ModerationAction) (*model.ModerationAction, error) {
+	const scope = "reportRepository#Resolve"
+	var result *model.ModerationAction
+	err := func() error {
+		tx, err := rr.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		now := time.Now()
+		report, err := rr.scanReport(tx.QueryRowContext(
+			ctx,
+			`
+				UPDATE "reports_tab"
+				SET "status" = $1, "resolution" = $2, "resolved_by" = $3, "resolved_at" = $4
+				WHERE "id" = $5 AND "status" = $6
+				RETURNING `+reportColumns+`;
+			`,
+			model.ReportStatusResolved,
+			action.Action,
+			action.ModeratorID,
+			now,
+			reportID,
+			model.ReportStatusOpen,
+		))
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			`
+				UPDATE "reports_tab"
+				SET "status" = $1, "resolution" = $2, "resolved_by" = $3, "resolved_at" = $4
+				WHERE "target_type" = $5 AND "target_id" = $6 AND "status" = $7;
+			`,
+			model.ReportStatusResolved,
+			action.Action,
+			action.ModeratorID,
+			now,
+			report.TargetType,
+			report.TargetID,
+			model.ReportStatusOpen,
+		)
+		if err != nil {
+			return err
+		}
+		result, err = rr.scanModerationAction(tx.QueryRowContext(
+			ctx,
+			`
+				INSERT INTO "moderation_actions_tab" (
+					"report_id", "moderator_id", "action", "target_type", "target_id",
+					"target_user_id", "note", "suspended_until", "created_at"
+				)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				RETURNING `+moderationActionColumns+`;
+			`,
+			report.ID,
+			action.ModeratorID,
+			action.Action,
+			report.TargetType,
+			report.TargetID,
+			action.TargetUserID,
+			action.Note,
+			action.SuspendedUntil,
+			now,
+		))
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	}()
+	if err != nil {
+		logging.FromContext(ctx, rr.logger).Error(
+			"Failed to resolve a report",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, rr.logger).Info(
+		"Resolved a report",
+		slog.String("scope", scope),
+	)
+	return result, nil
+}
+
+func (rr reportRepository) FindActions(ctx context.Context, beforeID int, limit int) ([]*model.ModerationAction, error) {
+	const scope = "reportRepository#FindActions"
+	actions := []*model.ModerationAction{}
+	err := func() error {
+		rows, err := rr.db.QueryContext(
+			ctx,
+			`
+				SELECT `+moderationActionColumns+`
+				FROM "moderation_actions_tab"
+				WHERE ($1 = 0 OR "id" < $1)
+				ORDER BY "id" DESC
+				LIMIT $2;
+			`,
+			beforeID,
+			limit,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			action, err := rr.scanModerationAction(rows)
+			if err != nil {
+				return err
+			}
+			actions = append(actions, action)
+		}
+		return rows.Err()
+	}()
+	if err != nil {
+		logging.FromContext(ctx, rr.logger).Error(
+			"Failed to find moderation actions",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, rr.logger).Info(
+		"Found moderation actions",
+		slog.String("scope", scope),
+	)
+	return actions, nil
+}
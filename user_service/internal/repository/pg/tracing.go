@@ -0,0 +1,38 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("userservice/internal/repository/pg")
+
+// startSpan starts a client span for a single query. operation is the SQL
+// verb, such as SELECT.
+func startSpan(ctx context.Context, name string, operation string) (context.Context, trace.Span) {
+	return tracer.Start(
+		ctx,
+		name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperation(operation),
+		),
+	)
+}
+
+// recordError marks span as failed. A missing row is an expected outcome and
+// is left unmarked.
+func recordError(span trace.Span, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
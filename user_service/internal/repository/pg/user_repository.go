@@ -7,10 +7,10 @@ import (
 	"fmt"
 	"time"
 	"userservice/internal/dto/req"
+	"userservice/internal/logging"
 	"userservice/internal/model"
 	"userservice/internal/repository"
 	"userservice/internal/repository/sqltype"
-	"userservice/internal/util"
 
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -31,10 +31,13 @@ func NewUserRepository(logger *slog.Logger, db *sql.DB) repository.IUserReposito
 
 func (ur userRepository) FindByID(ctx context.Context, id int) (*model.User, error) {
 	const scope = "userRepository#FindByID"
+	ctx, span := startSpan(ctx, scope, "SELECT")
+	defer span.End()
 	user := &sqltype.User{}
-	err := ur.db.QueryRow(
+	err := ur.db.QueryRowContext(
+		ctx,
 		`
-			SELECT "id", "email", "password", "first_name", "last_name", "created_at", "updated_at", "deleted_at"
+			SELECT "id", "email", "password", "first_name", "last_name", "role", "suspended_until", "created_at", "updated_at", "deleted_at", "version"
 			FROM "users_tab"
 			WHERE "id" = $1 AND "deleted_at" IS NULL;
 		`,
@@ -45,15 +48,18 @@ func (ur userRepository) FindByID(ctx context.Context, id int) (*model.User, err
 		&user.Password,
 		&user.FirstName,
 		&user.LastName,
+		&user.Role,
+		&user.SuspendedUntil,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.DeletedAt,
+		&user.Version,
 	)
 	if err != nil {
-		ur.logger.Error(
+		recordError(span, err)
+		logging.FromContext(ctx, ur.logger).Error(
 			"Failed to find a user by its ID",
 			err,
-			slog.String("request_id", ctx.Value(util.RequestID).(string)),
 			slog.String("scope", scope),
 		)
 		if errors.Is(err, sql.ErrNoRows) {
@@ -65,20 +71,78 @@ func (ur userRepository) FindByID(ctx context.Context, id int) (*model.User, err
 		}
 		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(pgError))
 	}
-	ur.logger.Info(
+	logging.FromContext(ctx, ur.logger).Info(
 		"Found a user by its ID",
-		slog.String("request_id", ctx.Value(util.RequestID).(string)),
 		slog.String("scope", scope),
 	)
 	return user.ToModel(), nil
 }
 
+func (ur userRepository) FindByIDs(ctx context.Context, ids []int) ([]*model.User, error) {
+	const scope = "userRepository#FindByIDs"
+	ctx, span := startSpan(ctx, scope, "SELECT")
+	defer span.End()
+	users := []*model.User{}
+	err := func() error {
+		rows, err := ur.db.QueryContext(
+			ctx,
+			`
+				SELECT "id", "email", "password", "first_name", "last_name", "role", "suspended_until", "created_at", "updated_at", "deleted_at", "version"
+				FROM "users_tab"
+				WHERE "id" = ANY($1) AND "deleted_at" IS NULL;
+			`,
+			ids,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			user := &sqltype.User{}
+			if err := rows.Scan(
+				&user.ID,
+				&user.Email,
+				&user.Password,
+				&user.FirstName,
+				&user.LastName,
+				&user.Role,
+				&user.SuspendedUntil,
+				&user.CreatedAt,
+				&user.UpdatedAt,
+				&user.DeletedAt,
+				&user.Version,
+			); err != nil {
+				return err
+			}
+			users = append(users, user.ToModel())
+		}
+		return rows.Err()
+	}()
+	if err != nil {
+		recordError(span, err)
+		logging.FromContext(ctx, ur.logger).Error(
+			"Failed to find users by their IDs",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, ur.logger).Info(
+		"Found users by their IDs",
+		slog.String("scope", scope),
+	)
+	return users, nil
+}
+
 func (ur userRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	const scope = "userRepository#FindByEmail"
+	ctx, span := startSpan(ctx, scope, "SELECT")
+	defer span.End()
 	user := &sqltype.User{}
-	err := ur.db.QueryRow(
+	err := ur.db.QueryRowContext(
+		ctx,
 		`
-			SELECT "id", "email", "password", "first_name", "last_name", "created_at", "updated_at", "deleted_at"
+			SELECT "id", "email", "password", "first_name", "last_name", "role", "suspended_until", "created_at", "updated_at", "deleted_at", "version"
 			FROM "users_tab"
 			WHERE "email" = $1 AND "deleted_at" IS NULL;
 		`,
@@ -89,15 +153,18 @@ func (ur userRepository) FindByEmail(ctx context.Context, email string) (*model.
 		&user.Password,
 		&user.FirstName,
 		&user.LastName,
+		&user.Role,
+		&user.SuspendedUntil,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.DeletedAt,
+		&user.Version,
 	)
 	if err != nil {
-		ur.logger.Error(
+		recordError(span, err)
+		logging.FromContext(ctx, ur.logger).Error(
 			"Failed to find a user by its email",
 			err,
-			slog.String("request_id", ctx.Value(util.RequestID).(string)),
 			slog.String("scope", scope),
 		)
 		if errors.Is(err, sql.ErrNoRows) {
@@ -109,22 +176,28 @@ func (ur userRepository) FindByEmail(ctx context.Context, email string) (*model.
 		}
 		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(pgError))
 	}
-	ur.logger.Info(
+	logging.FromContext(ctx, ur.logger).Info(
 		"Found a user by its email",
-		slog.String("request_id", ctx.Value(util.RequestID).(string)),
 		slog.String("scope", scope),
 	)
 	return user.ToModel(), nil
 }
 
+func (ur userRepository) FindCredentialsByEmail(ctx context.Context, email string) (*model.User, error) {
+	return ur.FindByEmail(ctx, email)
+}
+
 func (ur userRepository) Create(ctx context.Context, userDto *req.UserDto) (*model.User, error) {
 	const scope = "userRepository#Create"
+	ctx, span := startSpan(ctx, scope, "INSERT")
+	defer span.End()
 	user := &sqltype.User{}
-	err := ur.db.QueryRow(
+	err := ur.db.QueryRowContext(
+		ctx,
 		`
 			INSERT INTO "users_tab" ("email", "password", "first_name", "last_name", "created_at", "updated_at")
 			VALUES ($1, $2, $3, $4, $5, $6)
-			RETURNING "id", "email", "password", "first_name", "last_name", "created_at", "updated_at", "deleted_at";
+			RETURNING "id", "email", "password", "first_name", "last_name", "role", "suspended_until", "created_at", "updated_at", "deleted_at", "version";
 		`,
 		userDto.Email,
 		userDto.Password,
@@ -138,15 +211,18 @@ func (ur userRepository) Create(ctx context.Context, userDto *req.UserDto) (*mod
 		&user.Password,
 		&user.FirstName,
 		&user.LastName,
+		&user.Role,
+		&user.SuspendedUntil,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.DeletedAt,
+		&user.Version,
 	)
 	if err != nil {
-		ur.logger.Error(
+		recordError(span, err)
+		logging.FromContext(ctx, ur.logger).Error(
 			"Failed to create a user",
 			err,
-			slog.String("request_id", ctx.Value(util.RequestID).(string)),
 			slog.String("scope", scope),
 		)
 		pgError, ok := err.(*pgconn.PgError)
@@ -158,45 +234,51 @@ func (ur userRepository) Create(ctx context.Context, userDto *req.UserDto) (*mod
 		}
 		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(pgError))
 	}
-	ur.logger.Info(
+	logging.FromContext(ctx, ur.logger).Info(
 		"Created a user",
-		slog.String("request_id", ctx.Value(util.RequestID).(string)),
 		slog.String("scope", scope),
 	)
 	return user.ToModel(), nil
 }
 
-func (ur userRepository) DeleteByID(ctx context.Context, id int) (*model.User, error) {
+func (ur userRepository) DeleteByID(ctx context.Context, id int, versions []int) (*model.User, error) {
 	const scope = "userRepository#DeleteByID"
+	ctx, span := startSpan(ctx, scope, "UPDATE")
+	defer span.End()
 	user := &sqltype.User{}
-	err := ur.db.QueryRow(
+	err := ur.db.QueryRowContext(
+		ctx,
 		`
 			UPDATE "users_tab"
-			SET "deleted_at" = $1
-			WHERE "id" = $2 AND "deleted_at" IS NULL
-			RETURNING "id", "email", "password", "first_name", "last_name", "created_at", "updated_at", "deleted_at";
+			SET "deleted_at" = $1, "version" = "version" + 1
+			WHERE "id" = $2 AND "deleted_at" IS NULL AND (COALESCE(CARDINALITY($3::BIGINT[]), 0) = 0 OR "version" = ANY($3))
+			RETURNING "id", "email", "password", "first_name", "last_name", "role", "suspended_until", "created_at", "updated_at", "deleted_at", "version";
 		`,
 		time.Now(),
 		id,
+		versions,
 	).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Password,
 		&user.FirstName,
 		&user.LastName,
+		&user.Role,
+		&user.SuspendedUntil,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.DeletedAt,
+		&user.Version,
 	)
 	if err != nil {
-		ur.logger.Error(
+		recordError(span, err)
+		logging.FromContext(ctx, ur.logger).Error(
 			"Failed to soft delete a user by its ID",
 			err,
-			slog.String("request_id", ctx.Value(util.RequestID).(string)),
 			slog.String("scope", scope),
 		)
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+			return nil, fmt.Errorf("%s: %w", scope, ur.noRowsError(ctx, id, versions, false, err))
 		}
 		pgError, ok := err.(*pgconn.PgError)
 		if !ok {
@@ -204,43 +286,49 @@ func (ur userRepository) DeleteByID(ctx context.Context, id int) (*model.User, e
 		}
 		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(pgError))
 	}
-	ur.logger.Info(
+	logging.FromContext(ctx, ur.logger).Info(
 		"Soft deleted a user by its ID",
-		slog.String("request_id", ctx.Value(util.RequestID).(string)),
 		slog.String("scope", scope),
 	)
 	return user.ToModel(), nil
 }
 
-func (ur userRepository) DeletePermanentlyByID(ctx context.Context, id int) (*model.User, error) {
+func (ur userRepository) DeletePermanentlyByID(ctx context.Context, id int, versions []int) (*model.User, error) {
 	const scope = "userRepository#DeletePermanentlyByID"
+	ctx, span := startSpan(ctx, scope, "DELETE")
+	defer span.End()
 	user := &sqltype.User{}
-	err := ur.db.QueryRow(
+	err := ur.db.QueryRowContext(
+		ctx,
 		`
 			DELETE FROM "users_tab"
-			WHERE "id" = $1
-			RETURNING "id", "email", "password", "first_name", "last_name", "created_at", "updated_at", "deleted_at";
+			WHERE "id" = $1 AND (COALESCE(CARDINALITY($2::BIGINT[]), 0) = 0 OR "version" = ANY($2))
+			RETURNING "id", "email", "password", "first_name", "last_name", "role", "suspended_until", "created_at", "updated_at", "deleted_at", "version";
 		`,
 		id,
+		versions,
 	).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Password,
 		&user.FirstName,
 		&user.LastName,
+		&user.Role,
+		&user.SuspendedUntil,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.DeletedAt,
+		&user.Version,
 	)
 	if err != nil {
-		ur.logger.Error(
+		recordError(span, err)
+		logging.FromContext(ctx, ur.logger).Error(
 			"Failed to delete a user permanently by its ID",
 			err,
-			slog.String("request_id", ctx.Value(util.RequestID).(string)),
 			slog.String("scope", scope),
 		)
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+			return nil, fmt.Errorf("%s: %w", scope, ur.noRowsError(ctx, id, versions, true, err))
 		}
 		pgError, ok := err.(*pgconn.PgError)
 		if !ok {
@@ -248,10 +336,85 @@ func (ur userRepository) DeletePermanentlyByID(ctx context.Context, id int) (*mo
 		}
 		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(pgError))
 	}
-	ur.logger.Info(
+	logging.FromContext(ctx, ur.logger).Info(
 		"Deleted a user permanently by its ID",
-		slog.String("request_id", ctx.Value(util.RequestID).(string)),
 		slog.String("scope", scope),
 	)
 	return user.ToModel(), nil
 }
+
+// Suspend never shortens an existing suspension.
+func (ur userRepository) Suspend(ctx context.Context, id int, until time.Time) (*model.User, error) {
+	const scope = "userRepository#Suspend"
+	ctx, span := startSpan(ctx, scope, "UPDATE")
+	defer span.End()
+	user := &sqltype.User{}
+	err := ur.db.QueryRowContext(
+		ctx,
+		`
+			UPDATE "users_tab"
+			SET "suspended_until" = GREATEST("suspended_until", $1), "updated_at" = $2, "version" = "version" + 1
+			WHERE "id" = $3 AND "deleted_at" IS NULL
+			RETURNING "id", "email", "password", "first_name", "last_name", "role", "suspended_until", "created_at", "updated_at", "deleted_at", "version";
+		`,
+		until,
+		time.Now(),
+		id,
+	).Scan(
+		&user.ID,
+		&user.Email,
+		&user.Password,
+		&user.FirstName,
+		&user.LastName,
+		&user.Role,
+		&user.SuspendedUntil,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.DeletedAt,
+		&user.Version,
+	)
+	if err != nil {
+		recordError(span, err)
+		logging.FromContext(ctx, ur.logger).Error(
+			"Failed to suspend a user by its ID",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", scope, repository.ErrDataNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, repository.ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, ur.logger).Info(
+		"Suspended a user by its ID",
+		slog.String("scope", scope),
+	)
+	return user.ToModel(), nil
+}
+
+// noRowsError tells apart why a write conditioned on versions matched no row:
+// the user is missing, or it is at none of them. Soft deleted users only
+// count as present when withDeleted is set.
+func (ur userRepository) noRowsError(ctx context.Context, id int, versions []int, withDeleted bool, err error) error {
+	if len(versions) == 0 {
+		return repository.ErrDataNotFound.SetError(err)
+	}
+	var current int
+	lookupErr := ur.db.QueryRowContext(
+		ctx,
+		`
+			SELECT "version"
+			FROM "users_tab"
+			WHERE "id" = $1 AND ($2 OR "deleted_at" IS NULL);
+		`,
+		id,
+		withDeleted,
+	).Scan(&current)
+	if errors.Is(lookupErr, sql.ErrNoRows) {
+		return repository.ErrDataNotFound.SetError(err)
+	}
+	if lookupErr != nil {
+		return repository.ErrUnknown.SetError(lookupErr)
+	}
+	return repository.ErrVersionMismatch.SetError(fmt.Errorf("at version %d, not %v", current, versions))
+}
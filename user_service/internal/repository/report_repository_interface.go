@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"userservice/internal/model"
+)
+
+type IReportRepository interface {
+	CreateReport(ctx context.Context, reporterID int, targetType string, targetID int, reasonCode string, details *string) (*model.Report, error)
+	FindReportByID(ctx context.Context, id int) (*model.Report, error)
+	FindReports(ctx context.Context, status string, afterID int, limit int) ([]*model.Report, error)
+	TargetExists(ctx context.Context, userID int, targetType string, targetID int) (bool, error)
+	TargetOwnerID(ctx context.Context, targetType string, targetID int) (*int, error)
+	Resolve(ctx context.Context, reportID int, action *model.ModerationAction) (*model.ModerationAction, error)
+	FindActions(ctx context.Context, beforeID int, limit int) ([]*model.ModerationAction, error)
+}
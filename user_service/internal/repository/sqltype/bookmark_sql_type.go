@@ -0,0 +1,58 @@
+package sqltype
+
+import (
+	"database/sql"
+	"userservice/internal/model"
+)
+
+type Collection struct {
+	ID            sql.NullInt64
+	UserID        sql.NullInt64
+	Name          sql.NullString
+	IsPrivate     sql.NullBool
+	BookmarkCount sql.NullInt64
+	CreatedAt     sql.NullTime
+	UpdatedAt     sql.NullTime
+}
+
+func (c Collection) ToModel() *model.Collection {
+	if !c.ID.Valid {
+		return nil
+	}
+	return &model.Collection{
+		ID:            int(c.ID.Int64),
+		UserID:        int(c.UserID.Int64),
+		Name:          c.Name.String,
+		IsPrivate:     c.IsPrivate.Bool,
+		BookmarkCount: int(c.BookmarkCount.Int64),
+		CreatedAt:     c.CreatedAt.Time,
+		UpdatedAt:     c.UpdatedAt.Time,
+	}
+}
+
+type Bookmark struct {
+	ID           sql.NullInt64
+	UserID       sql.NullInt64
+	ItemType     sql.NullString
+	ItemID       sql.NullInt64
+	CollectionID sql.NullInt64
+	CreatedAt    sql.NullTime
+}
+
+func (b Bookmark) ToModel() *model.Bookmark {
+	if !b.ID.Valid {
+		return nil
+	}
+	result := &model.Bookmark{
+		ID:        int(b.ID.Int64),
+		UserID:    int(b.UserID.Int64),
+		ItemType:  b.ItemType.String,
+		ItemID:    int(b.ItemID.Int64),
+		CreatedAt: b.CreatedAt.Time,
+	}
+	if b.CollectionID.Valid {
+		collectionID := int(b.CollectionID.Int64)
+		result.CollectionID = &collectionID
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package sqltype
+
+import (
+	"database/sql"
+	"userservice/internal/model"
+)
+
+type Conversation struct {
+	ID             sql.NullInt64
+	Kind           sql.NullString
+	Title          sql.NullString
+	LastMessageID  sql.NullInt64
+	CreatedAt      sql.NullTime
+	UpdatedAt      sql.NullTime
+	ParticipantIDs []int64
+	UnreadCount    sql.NullInt64
+}
+
+func (c Conversation) ToModel() *model.Conversation {
+	if !c.ID.Valid {
+		return nil
+	}
+	result := &model.Conversation{
+		ID:             int(c.ID.Int64),
+		Kind:           c.Kind.String,
+		ParticipantIDs: make([]int, 0, len(c.ParticipantIDs)),
+		UnreadCount:    int(c.UnreadCount.Int64),
+		CreatedAt:      c.CreatedAt.Time,
+		UpdatedAt:      c.UpdatedAt.Time,
+	}
+	for _, participantID := range c.ParticipantIDs {
+		result.ParticipantIDs = append(result.ParticipantIDs, int(participantID))
+	}
+	if c.Title.Valid {
+		result.Title = &c.Title.String
+	}
+	if c.LastMessageID.Valid {
+		lastMessageID := int(c.LastMessageID.Int64)
+		result.LastMessageID = &lastMessageID
+	}
+	return result
+}
+
+type Message struct {
+	ID             sql.NullInt64
+	ConversationID sql.NullInt64
+	SenderID       sql.NullInt64
+	Body           sql.NullString
+	MediaIDs       []string
+	CreatedAt      sql.NullTime
+}
+
+func (m Message) ToModel() *model.Message {
+	if !m.ID.Valid {
+		return nil
+	}
+	return &model.Message{
+		ID:             int(m.ID.Int64),
+		ConversationID: int(m.ConversationID.Int64),
+		SenderID:       int(m.SenderID.Int64),
+		Body:           m.Body.String,
+		MediaIDs:       m.MediaIDs,
+		CreatedAt:      m.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,94 @@
+package sqltype
+
+import (
+	"database/sql"
+	"userservice/internal/model"
+)
+
+type Report struct {
+	ID         sql.NullInt64
+	ReporterID sql.NullInt64
+	TargetType sql.NullString
+	TargetID   sql.NullInt64
+	ReasonCode sql.NullString
+	Details    sql.NullString
+	Status     sql.NullString
+	Resolution sql.NullString
+	ResolvedBy sql.NullInt64
+	ResolvedAt sql.NullTime
+	CreatedAt  sql.NullTime
+}
+
+func (r Report) ToModel() *model.Report {
+	if !r.ID.Valid {
+		return nil
+	}
+	result := &model.Report{
+		ID:         int(r.ID.Int64),
+		TargetType: r.TargetType.String,
+		TargetID:   int(r.TargetID.Int64),
+		ReasonCode: r.ReasonCode.String,
+		Status:     r.Status.String,
+		CreatedAt:  r.CreatedAt.Time,
+	}
+	if r.ReporterID.Valid {
+		reporterID := int(r.ReporterID.Int64)
+		result.ReporterID = &reporterID
+	}
+	if r.Details.Valid {
+		result.Details = &r.Details.String
+	}
+	if r.Resolution.Valid {
+		result.Resolution = &r.Resolution.String
+	}
+	if r.ResolvedBy.Valid {
+		resolvedBy := int(r.ResolvedBy.Int64)
+		result.ResolvedBy = &resolvedBy
+	}
+	if r.ResolvedAt.Valid {
+		result.ResolvedAt = &r.ResolvedAt.Time
+	}
+	return result
+}
+
+type ModerationAction struct {
+	ID             sql.NullInt64
+	ReportID       sql.NullInt64
+	ModeratorID    sql.NullInt64
+	Action         sql.NullString
+	TargetType     sql.NullString
+	TargetID       sql.NullInt64
+	TargetUserID   sql.NullInt64
+	Note           sql.NullString
+	SuspendedUntil sql.NullTime
+	CreatedAt      sql.NullTime
+}
+
+func (ma ModerationAction) ToModel() *model.ModerationAction {
+	if !ma.ID.Valid {
+		return nil
+	}
+	result := &model.ModerationAction{
+		ID:          int(ma.ID.Int64),
+		ModeratorID: int(ma.ModeratorID.Int64),
+		Action:      ma.Action.String,
+		TargetType:  ma.TargetType.String,
+		TargetID:    int(ma.TargetID.Int64),
+		CreatedAt:   ma.CreatedAt.Time,
+	}
+	if ma.ReportID.Valid {
+		reportID := int(ma.ReportID.Int64)
+		result.ReportID = &reportID
+	}
+	if ma.TargetUserID.Valid {
+		targetUserID := int(ma.TargetUserID.Int64)
+		result.TargetUserID = &targetUserID
+	}
+	if ma.Note.Valid {
+		result.Note = &ma.Note.String
+	}
+	if ma.SuspendedUntil.Valid {
+		result.SuspendedUntil = &ma.SuspendedUntil.Time
+	}
+	return result
+}
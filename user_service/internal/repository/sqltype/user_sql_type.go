@@ -6,14 +6,17 @@ import (
 )
 
 type User struct {
-	ID        sql.NullInt64
-	Email     sql.NullString
-	Password  sql.NullString
-	FirstName sql.NullString
-	LastName  sql.NullString
-	CreatedAt sql.NullTime
-	UpdatedAt sql.NullTime
-	DeletedAt sql.NullTime
+	ID             sql.NullInt64
+	Email          sql.NullString
+	Password       sql.NullString
+	FirstName      sql.NullString
+	LastName       sql.NullString
+	Role           sql.NullString
+	SuspendedUntil sql.NullTime
+	CreatedAt      sql.NullTime
+	UpdatedAt      sql.NullTime
+	DeletedAt      sql.NullTime
+	Version        sql.NullInt64
 }
 
 func (u User) ToModel() *model.User {
@@ -26,8 +29,13 @@ func (u User) ToModel() *model.User {
 		Password:  u.Password.String,
 		FirstName: u.FirstName.String,
 		LastName:  u.LastName.String,
+		Role:      u.Role.String,
 		CreatedAt: u.CreatedAt.Time,
 		UpdatedAt: u.UpdatedAt.Time,
+		Version:   int(u.Version.Int64),
+	}
+	if u.SuspendedUntil.Valid {
+		result.SuspendedUntil = &u.SuspendedUntil.Time
 	}
 	if u.DeletedAt.Valid {
 		result.DeletedAt = &u.DeletedAt.Time
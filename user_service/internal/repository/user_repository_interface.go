@@ -2,14 +2,23 @@ package repository
 
 import (
 	"context"
+	"time"
 	"userservice/internal/dto/req"
 	"userservice/internal/model"
 )
 
 type IUserRepository interface {
 	FindByID(ctx context.Context, id int) (*model.User, error)
+	FindByIDs(ctx context.Context, ids []int) ([]*model.User, error)
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	// FindCredentialsByEmail is FindByEmail for logins. The user always
+	// comes from the database with its password hash, which users read from
+	// a cache do not have.
+	FindCredentialsByEmail(ctx context.Context, email string) (*model.User, error)
 	Create(ctx context.Context, userDto *req.UserDto) (*model.User, error)
-	DeleteByID(ctx context.Context, id int) (*model.User, error)
-	DeletePermanentlyByID(ctx context.Context, id int) (*model.User, error)
+	// DeleteByID and DeletePermanentlyByID only delete the user at one of
+	// versions when there are any, and fail with ErrVersionMismatch otherwise.
+	DeleteByID(ctx context.Context, id int, versions []int) (*model.User, error)
+	DeletePermanentlyByID(ctx context.Context, id int, versions []int) (*model.User, error)
+	Suspend(ctx context.Context, id int, until time.Time) (*model.User, error)
 }
@@ -0,0 +1,328 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"userservice/internal/dto/req"
+	"userservice/internal/dto/resp"
+	"userservice/internal/logging"
+	"userservice/internal/model"
+	"userservice/internal/repository"
+	"userservice/internal/util"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/exp/slog"
+)
+
+type bookmarkUsecase struct {
+	logger             *slog.Logger
+	validate           *validator.Validate
+	bookmarkRepository repository.IBookmarkRepository
+}
+
+func NewBookmarkUsecase(logger *slog.Logger, validate *validator.Validate, bookmarkRepository repository.IBookmarkRepository) IBookmarkUsecase {
+	return &bookmarkUsecase{
+		logger:             logger,
+		validate:           validate,
+		bookmarkRepository: bookmarkRepository,
+	}
+}
+
+func beforeIDFromCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	keys, err := util.DecodeCursor(cursor, 1)
+	if err != nil {
+		return 0, err
+	}
+	return int(keys[0]), nil
+}
+
+// findVisibleCollection hides private collections from everyone but their
+// owner, without revealing that they exist.
+func (bu bookmarkUsecase) findVisibleCollection(ctx context.Context, viewerID int, id int) (*model.Collection, error) {
+	collection, err := bu.bookmarkRepository.FindCollectionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, ErrCollectionNotFound.SetError(err)
+		}
+		return nil, ErrUnknown.SetError(err)
+	}
+	if collection.IsPrivate && collection.UserID != viewerID {
+		return nil, ErrCollectionNotFound.SetError(fmt.Errorf("collection %d is private", id))
+	}
+	return collection, nil
+}
+
+func (bu bookmarkUsecase) findOwnedCollection(ctx context.Context, userID int, id int) (*model.Collection, error) {
+	collection, err := bu.findVisibleCollection(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if collection.UserID != userID {
+		return nil, ErrCollectionNotFound.SetError(fmt.Errorf("collection %d is not owned by user %d", id, userID))
+	}
+	return collection, nil
+}
+
+func (bu bookmarkUsecase) CreateCollection(ctx context.Context, userID int, collectionDto *req.CollectionDto) (*resp.CollectionDto, error) {
+	const scope = "bookmarkUsecase#CreateCollection"
+	if err := validateDto(bu.validate, collectionDto); err != nil {
+		logging.FromContext(ctx, bu.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	isPrivate := true
+	if collectionDto.IsPrivate != nil {
+		isPrivate = *collectionDto.IsPrivate
+	}
+	collection, err := bu.bookmarkRepository.CreateCollection(ctx, userID, collectionDto.Name, isPrivate)
+	if err != nil {
+		logging.FromContext(ctx, bu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, &repository.ErrUniqueViolation) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrCollectionExists.SetError(err))
+		}
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrUserNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, bu.logger).Info(
+		"Created a bookmark collection",
+		slog.String("scope", scope),
+	)
+	return collection.ToDto(), nil
+}
+
+func (bu bookmarkUsecase) UpdateCollection(ctx context.Context, userID int, id int, updateCollectionDto *req.UpdateCollectionDto) (*resp.CollectionDto, error) {
+	const scope = "bookmarkUsecase#UpdateCollection"
+	if err := validateDto(bu.validate, updateCollectionDto); err != nil {
+		logging.FromContext(ctx, bu.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	collection, err := bu.bookmarkRepository.UpdateCollection(ctx, userID, id, updateCollectionDto.Name, updateCollectionDto.IsPrivate)
+	if err != nil {
+		logging.FromContext(ctx, bu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrCollectionNotFound.SetError(err))
+		}
+		if errors.Is(err, &repository.ErrUniqueViolation) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrCollectionExists.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, bu.logger).Info(
+		"Updated a bookmark collection",
+		slog.String("scope", scope),
+	)
+	return collection.ToDto(), nil
+}
+
+func (bu bookmarkUsecase) DeleteCollection(ctx context.Context, userID int, id int) (*resp.CollectionDto, error) {
+	const scope = "bookmarkUsecase#DeleteCollection"
+	collection, err := bu.findOwnedCollection(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	if err := bu.bookmarkRepository.DeleteCollection(ctx, userID, id); err != nil {
+		logging.FromContext(ctx, bu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrCollectionNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, bu.logger).Info(
+		"Deleted a bookmark collection",
+		slog.String("scope", scope),
+	)
+	return collection.ToDto(), nil
+}
+
+func (bu bookmarkUsecase) FindCollections(ctx context.Context, viewerID int, userID int, pageDto *req.PageDto) (*resp.CollectionPageDto, error) {
+	const scope = "bookmarkUsecase#FindCollections"
+	if err := validateDto(bu.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	beforeID, err := beforeIDFromCursor(pageDto.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrBadCursor.SetError(err))
+	}
+	limit := pageLimit(pageDto)
+	collections, err := bu.bookmarkRepository.FindCollectionsByUserID(ctx, userID, viewerID == userID, beforeID, limit+1)
+	if err != nil {
+		logging.FromContext(ctx, bu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	result := &resp.CollectionPageDto{
+		Collections: []*resp.CollectionDto{},
+	}
+	if len(collections) > limit {
+		collections = collections[:limit]
+		result.NextCursor = util.EncodeCursor(int64(collections[limit-1].ID))
+	}
+	for _, collection := range collections {
+		result.Collections = append(result.Collections, collection.ToDto())
+	}
+	logging.FromContext(ctx, bu.logger).Info(
+		"Found bookmark collections of a user",
+		slog.String("scope", scope),
+	)
+	return result, nil
+}
+
+func (bu bookmarkUsecase) AddBookmark(ctx context.Context, userID int, bookmarkDto *req.BookmarkDto) (*resp.BookmarkDto, error) {
+	const scope = "bookmarkUsecase#AddBookmark"
+	if err := validateDto(bu.validate, bookmarkDto); err != nil {
+		logging.FromContext(ctx, bu.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	if bookmarkDto.CollectionID != nil {
+		if _, err := bu.findOwnedCollection(ctx, userID, *bookmarkDto.CollectionID); err != nil {
+			return nil, fmt.Errorf("%s: %w", scope, err)
+		}
+	}
+	exists, err := bu.bookmarkRepository.ItemExists(ctx, userID, bookmarkDto.ItemType, bookmarkDto.ItemID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", scope, ErrBookmarkItemNotFound.SetError(fmt.Errorf("%s %d", bookmarkDto.ItemType, bookmarkDto.ItemID)))
+	}
+	bookmark, err := bu.bookmarkRepository.UpsertBookmark(ctx, userID, bookmarkDto.ItemType, bookmarkDto.ItemID, bookmarkDto.CollectionID)
+	if err != nil {
+		logging.FromContext(ctx, bu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrCollectionNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, bu.logger).Info(
+		"Added a bookmark",
+		slog.String("scope", scope),
+	)
+	return bookmark.ToDto(), nil
+}
+
+func (bu bookmarkUsecase) RemoveBookmark(ctx context.Context, userID int, itemType string, itemID int) (*resp.BookmarkDto, error) {
+	const scope = "bookmarkUsecase#RemoveBookmark"
+	bookmark, err := bu.bookmarkRepository.DeleteBookmark(ctx, userID, itemType, itemID)
+	if err != nil {
+		logging.FromContext(ctx, bu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrBookmarkNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, bu.logger).Info(
+		"Removed a bookmark",
+		slog.String("scope", scope),
+	)
+	return bookmark.ToDto(), nil
+}
+
+func (bu bookmarkUsecase) FindBookmarks(ctx context.Context, userID int, pageDto *req.PageDto) (*resp.BookmarkPageDto, error) {
+	const scope = "bookmarkUsecase#FindBookmarks"
+	if err := validateDto(bu.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	beforeID, err := beforeIDFromCursor(pageDto.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrBadCursor.SetError(err))
+	}
+	limit := pageLimit(pageDto)
+	bookmarks, err := bu.bookmarkRepository.FindBookmarksByUserID(ctx, userID, beforeID, limit+1)
+	if err != nil {
+		logging.FromContext(ctx, bu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, bu.logger).Info(
+		"Found bookmarks of a user",
+		slog.String("scope", scope),
+	)
+	return bookmarkPage(bookmarks, limit), nil
+}
+
+func (bu bookmarkUsecase) FindCollectionBookmarks(ctx context.Context, viewerID int, collectionID int, pageDto *req.PageDto) (*resp.BookmarkPageDto, error) {
+	const scope = "bookmarkUsecase#FindCollectionBookmarks"
+	if err := validateDto(bu.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	beforeID, err := beforeIDFromCursor(pageDto.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrBadCursor.SetError(err))
+	}
+	if _, err := bu.findVisibleCollection(ctx, viewerID, collectionID); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	limit := pageLimit(pageDto)
+	bookmarks, err := bu.bookmarkRepository.FindBookmarksByCollectionID(ctx, collectionID, beforeID, limit+1)
+	if err != nil {
+		logging.FromContext(ctx, bu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, bu.logger).Info(
+		"Found bookmarks of a collection",
+		slog.String("scope", scope),
+	)
+	return bookmarkPage(bookmarks, limit), nil
+}
+
+// bookmarkPage trims the extra bookmark fetched to detect a next page.
+func bookmarkPage(bookmarks []*model.Bookmark, limit int) *resp.BookmarkPageDto {
+	result := &resp.BookmarkPageDto{
+		Bookmarks: []*resp.BookmarkDto{},
+	}
+	if len(bookmarks) > limit {
+		bookmarks = bookmarks[:limit]
+		result.NextCursor = util.EncodeCursor(int64(bookmarks[limit-1].ID))
+	}
+	for _, bookmark := range bookmarks {
+		result.Bookmarks = append(result.Bookmarks, bookmark.ToDto())
+	}
+	return result
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+	"userservice/internal/dto/req"
+	"userservice/internal/dto/resp"
+)
+
+type IBookmarkUsecase interface {
+	CreateCollection(ctx context.Context, userID int, collectionDto *req.CollectionDto) (*resp.CollectionDto, error)
+	UpdateCollection(ctx context.Context, userID int, id int, updateCollectionDto *req.UpdateCollectionDto) (*resp.CollectionDto, error)
+	DeleteCollection(ctx context.Context, userID int, id int) (*resp.CollectionDto, error)
+	FindCollections(ctx context.Context, viewerID int, userID int, pageDto *req.PageDto) (*resp.CollectionPageDto, error)
+	AddBookmark(ctx context.Context, userID int, bookmarkDto *req.BookmarkDto) (*resp.BookmarkDto, error)
+	RemoveBookmark(ctx context.Context, userID int, itemType string, itemID int) (*resp.BookmarkDto, error)
+	FindBookmarks(ctx context.Context, userID int, pageDto *req.PageDto) (*resp.BookmarkPageDto, error)
+	FindCollectionBookmarks(ctx context.Context, viewerID int, collectionID int, pageDto *req.PageDto) (*resp.BookmarkPageDto, error)
+}
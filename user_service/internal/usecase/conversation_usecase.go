@@ -0,0 +1,397 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"userservice/internal/dto/req"
+	"userservice/internal/dto/resp"
+	"userservice/internal/logging"
+	"userservice/internal/model"
+	"userservice/internal/repository"
+	"userservice/internal/util"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/exp/slog"
+)
+
+const defaultPageLimit = 20
+
+type conversationUsecase struct {
+	logger                 *slog.Logger
+	validate               *validator.Validate
+	conversationRepository repository.IConversationRepository
+}
+
+func NewConversationUsecase(logger *slog.Logger, validate *validator.Validate, conversationRepository repository.IConversationRepository) IConversationUsecase {
+	return &conversationUsecase{
+		logger:                 logger,
+		validate:               validate,
+		conversationRepository: conversationRepository,
+	}
+}
+
+func pageLimit(pageDto *req.PageDto) int {
+	if pageDto.Limit == 0 {
+		return defaultPageLimit
+	}
+	return pageDto.Limit
+}
+
+func directKey(userID int, otherUserID int) string {
+	if userID > otherUserID {
+		userID, otherUserID = otherUserID, userID
+	}
+	return fmt.Sprintf("%d:%d", userID, otherUserID)
+}
+
+// canMessage tells whether the recipient's message permission lets userID
+// start a conversation with them.
+func (cu conversationUsecase) canMessage(ctx context.Context, userID int, recipientID int, messagePermission string) (bool, error) {
+	switch messagePermission {
+	case model.MessagePermissionEveryone:
+		return true, nil
+	case model.MessagePermissionContacts:
+		return cu.conversationRepository.ShareConversation(ctx, recipientID, userID)
+	default:
+		return false, nil
+	}
+}
+
+func (cu conversationUsecase) Create(ctx context.Context, userID int, conversationDto *req.ConversationDto) (*resp.ConversationDto, error) {
+	const scope = "conversationUsecase#Create"
+	if err := validateDto(cu.validate, conversationDto); err != nil {
+		logging.FromContext(ctx, cu.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	participantIDs := []int{}
+	for _, participantID := range conversationDto.ParticipantIDs {
+		if participantID != userID {
+			participantIDs = append(participantIDs, participantID)
+		}
+	}
+	if len(participantIDs) == 0 {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(errors.New("participant_ids must include someone other than yourself")))
+	}
+	messagePermissions, err := cu.conversationRepository.FindMessagePermissions(ctx, participantIDs)
+	if err != nil {
+		logging.FromContext(ctx, cu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	for _, participantID := range participantIDs {
+		messagePermission, ok := messagePermissions[participantID]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", scope, ErrUserNotFound.SetError(fmt.Errorf("user %d", participantID)))
+		}
+		allowed, err := cu.canMessage(ctx, userID, participantID, messagePermission)
+		if err != nil {
+			logging.FromContext(ctx, cu.logger).Error(
+				"Got error from repository",
+				err,
+				slog.String("scope", scope),
+			)
+			return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%s: %w", scope, ErrMessagingRestricted.SetError(fmt.Errorf("user %d", participantID)))
+		}
+	}
+	if len(participantIDs) == 1 && conversationDto.Title == nil {
+		return cu.createDirect(ctx, userID, participantIDs[0])
+	}
+	conversation, err := cu.conversationRepository.Create(ctx, userID, model.ConversationKindGroup, nil, conversationDto.Title, participantIDs)
+	if err != nil {
+		logging.FromContext(ctx, cu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrUserNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cu.logger).Info(
+		"Created a group conversation",
+		slog.String("scope", scope),
+	)
+	return conversation.ToDto(), nil
+}
+
+// createDirect returns the existing direct conversation between the two
+// users, creating it if needed.
+func (cu conversationUsecase) createDirect(ctx context.Context, userID int, otherUserID int) (*resp.ConversationDto, error) {
+	const scope = "conversationUsecase#createDirect"
+	key := directKey(userID, otherUserID)
+	conversation, err := cu.conversationRepository.FindByDirectKey(ctx, userID, key)
+	if err == nil {
+		return conversation.ToDto(), nil
+	}
+	if !errors.Is(err, &repository.ErrDataNotFound) {
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	conversation, err = cu.conversationRepository.Create(ctx, userID, model.ConversationKindDirect, &key, nil, []int{otherUserID})
+	if errors.Is(err, &repository.ErrUniqueViolation) {
+		conversation, err = cu.conversationRepository.FindByDirectKey(ctx, userID, key)
+	}
+	if err != nil {
+		logging.FromContext(ctx, cu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrUserNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cu.logger).Info(
+		"Created a direct conversation",
+		slog.String("scope", scope),
+	)
+	return conversation.ToDto(), nil
+}
+
+func (cu conversationUsecase) FindByUserID(ctx context.Context, userID int, pageDto *req.PageDto) (*resp.ConversationPageDto, error) {
+	const scope = "conversationUsecase#FindByUserID"
+	if err := validateDto(cu.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	var beforeUpdatedAt *time.Time
+	beforeID := 0
+	if pageDto.Cursor != "" {
+		keys, err := util.DecodeCursor(pageDto.Cursor, 2)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", scope, ErrBadCursor.SetError(err))
+		}
+		updatedAt := time.Unix(0, keys[0]).UTC()
+		beforeUpdatedAt = &updatedAt
+		beforeID = int(keys[1])
+	}
+	limit := pageLimit(pageDto)
+	conversations, err := cu.conversationRepository.FindByUserID(ctx, userID, beforeUpdatedAt, beforeID, limit+1)
+	if err != nil {
+		logging.FromContext(ctx, cu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	result := &resp.ConversationPageDto{
+		Conversations: []*resp.ConversationDto{},
+	}
+	if len(conversations) > limit {
+		conversations = conversations[:limit]
+		last := conversations[limit-1]
+		result.NextCursor = util.EncodeCursor(last.UpdatedAt.UnixNano(), int64(last.ID))
+	}
+	for _, conversation := range conversations {
+		result.Conversations = append(result.Conversations, conversation.ToDto())
+	}
+	logging.FromContext(ctx, cu.logger).Info(
+		"Found conversations of a user",
+		slog.String("scope", scope),
+	)
+	return result, nil
+}
+
+func (cu conversationUsecase) FindByID(ctx context.Context, userID int, id int) (*resp.ConversationDto, error) {
+	const scope = "conversationUsecase#FindByID"
+	conversation, err := cu.conversationRepository.FindByID(ctx, userID, id)
+	if err != nil {
+		logging.FromContext(ctx, cu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrConversationNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cu.logger).Info(
+		"Found a conversation by its ID",
+		slog.String("scope", scope),
+	)
+	return conversation.ToDto(), nil
+}
+
+func (cu conversationUsecase) SendMessage(ctx context.Context, userID int, conversationID int, messageDto *req.MessageDto) (*resp.SentMessageDto, error) {
+	const scope = "conversationUsecase#SendMessage"
+	if err := validateDto(cu.validate, messageDto); err != nil {
+		logging.FromContext(ctx, cu.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	conversation, err := cu.FindByID(ctx, userID, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	recipientIDs := []int{}
+	for _, participantID := range conversation.ParticipantIDs {
+		if participantID != userID {
+			recipientIDs = append(recipientIDs, participantID)
+		}
+	}
+	if conversation.Kind == model.ConversationKindDirect {
+		// The other participant is gone once their user is deleted
+		// permanently.
+		if len(recipientIDs) == 0 {
+			return nil, fmt.Errorf("%s: %w", scope, ErrMessagingRestricted.SetError(errors.New("no recipient")))
+		}
+		messagePermissions, err := cu.conversationRepository.FindMessagePermissions(ctx, recipientIDs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+		}
+		if messagePermission, ok := messagePermissions[recipientIDs[0]]; !ok || messagePermission == model.MessagePermissionNobody {
+			return nil, fmt.Errorf("%s: %w", scope, ErrMessagingRestricted.SetError(fmt.Errorf("user %d", recipientIDs[0])))
+		}
+	}
+	message, err := cu.conversationRepository.CreateMessage(ctx, userID, conversationID, messageDto.Body, messageDto.MediaIDs)
+	if err != nil {
+		logging.FromContext(ctx, cu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrConversationNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	participants, err := cu.conversationRepository.FindParticipants(ctx, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cu.logger).Info(
+		"Sent a message",
+		slog.String("scope", scope),
+	)
+	return &resp.SentMessageDto{
+		Message:      message.ToDto(participants),
+		RecipientIDs: recipientIDs,
+	}, nil
+}
+
+func (cu conversationUsecase) FindMessages(ctx context.Context, userID int, conversationID int, pageDto *req.PageDto) (*resp.MessagePageDto, error) {
+	const scope = "conversationUsecase#FindMessages"
+	if err := validateDto(cu.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	beforeID := 0
+	if pageDto.Cursor != "" {
+		keys, err := util.DecodeCursor(pageDto.Cursor, 1)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", scope, ErrBadCursor.SetError(err))
+		}
+		beforeID = int(keys[0])
+	}
+	if _, err := cu.FindByID(ctx, userID, conversationID); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	limit := pageLimit(pageDto)
+	messages, err := cu.conversationRepository.FindMessages(ctx, conversationID, beforeID, limit+1)
+	if err != nil {
+		logging.FromContext(ctx, cu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	participants, err := cu.conversationRepository.FindParticipants(ctx, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	result := &resp.MessagePageDto{
+		Messages: []*resp.MessageDto{},
+	}
+	if len(messages) > limit {
+		messages = messages[:limit]
+		result.NextCursor = util.EncodeCursor(int64(messages[limit-1].ID))
+	}
+	for _, message := range messages {
+		result.Messages = append(result.Messages, message.ToDto(participants))
+	}
+	logging.FromContext(ctx, cu.logger).Info(
+		"Found messages of a conversation",
+		slog.String("scope", scope),
+	)
+	return result, nil
+}
+
+func (cu conversationUsecase) MarkRead(ctx context.Context, userID int, conversationID int, messageID int) (*resp.ConversationDto, error) {
+	const scope = "conversationUsecase#MarkRead"
+	if _, err := cu.FindByID(ctx, userID, conversationID); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	if err := cu.conversationRepository.MarkRead(ctx, userID, conversationID, messageID); err != nil {
+		logging.FromContext(ctx, cu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cu.logger).Info(
+		"Marked a conversation as read",
+		slog.String("scope", scope),
+	)
+	return cu.FindByID(ctx, userID, conversationID)
+}
+
+func (cu conversationUsecase) CountUnread(ctx context.Context, userID int) (int, error) {
+	const scope = "conversationUsecase#CountUnread"
+	unreadCount, err := cu.conversationRepository.CountUnread(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx, cu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		return 0, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	return unreadCount, nil
+}
+
+func (cu conversationUsecase) UpdateMessagePermission(ctx context.Context, userID int, messagePermissionDto *req.MessagePermissionDto) (string, error) {
+	const scope = "conversationUsecase#UpdateMessagePermission"
+	if err := validateDto(cu.validate, messagePermissionDto); err != nil {
+		logging.FromContext(ctx, cu.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return "", fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	messagePermission, err := cu.conversationRepository.UpdateMessagePermission(ctx, userID, messagePermissionDto.MessagePermission)
+	if err != nil {
+		logging.FromContext(ctx, cu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return "", fmt.Errorf("%s: %w", scope, ErrUserNotFound.SetError(err))
+		}
+		return "", fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, cu.logger).Info(
+		"Updated message permission",
+		slog.String("scope", scope),
+	)
+	return messagePermission, nil
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+	"userservice/internal/dto/req"
+	"userservice/internal/dto/resp"
+)
+
+type IConversationUsecase interface {
+	Create(ctx context.Context, userID int, conversationDto *req.ConversationDto) (*resp.ConversationDto, error)
+	FindByUserID(ctx context.Context, userID int, pageDto *req.PageDto) (*resp.ConversationPageDto, error)
+	FindByID(ctx context.Context, userID int, id int) (*resp.ConversationDto, error)
+	SendMessage(ctx context.Context, userID int, conversationID int, messageDto *req.MessageDto) (*resp.SentMessageDto, error)
+	FindMessages(ctx context.Context, userID int, conversationID int, pageDto *req.PageDto) (*resp.MessagePageDto, error)
+	MarkRead(ctx context.Context, userID int, conversationID int, messageID int) (*resp.ConversationDto, error)
+	CountUnread(ctx context.Context, userID int) (int, error)
+	UpdateMessagePermission(ctx context.Context, userID int, messagePermissionDto *req.MessagePermissionDto) (string, error)
+}
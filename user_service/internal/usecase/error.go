@@ -9,7 +9,22 @@ var (
 	ErrFailHashingPassword  = Error{kind: failHashingPassword}
 	ErrFailToValidate       = Error{kind: failToValidate}
 	ErrUserNotFound         = Error{kind: userNotFound}
+	ErrUserVersionMismatch  = Error{kind: userVersionMismatch}
 	ErrWrongEmailOrPassword = Error{kind: wrongEmailOrPassword}
+	ErrConversationNotFound = Error{kind: conversationNotFound}
+	ErrMessagingRestricted  = Error{kind: messagingRestricted}
+	ErrBadCursor            = Error{kind: badCursor}
+	ErrCollectionNotFound   = Error{kind: collectionNotFound}
+	ErrCollectionExists     = Error{kind: collectionExists}
+	ErrBookmarkNotFound     = Error{kind: bookmarkNotFound}
+	ErrBookmarkItemNotFound = Error{kind: bookmarkItemNotFound}
+	ErrUserSuspended        = Error{kind: userSuspended}
+	ErrNotModerator         = Error{kind: notModerator}
+	ErrNotUserOrModerator   = Error{kind: notUserOrModerator}
+	ErrReportNotFound       = Error{kind: reportNotFound}
+	ErrReportExists         = Error{kind: reportExists}
+	ErrReportResolved       = Error{kind: reportResolved}
+	ErrReportTargetNotFound = Error{kind: reportTargetNotFound}
 	ErrUnknown              = Error{kind: unknown}
 )
 
@@ -19,7 +34,22 @@ const (
 	failHashingPassword
 	failToValidate
 	userNotFound
+	userVersionMismatch
 	wrongEmailOrPassword
+	conversationNotFound
+	messagingRestricted
+	badCursor
+	collectionNotFound
+	collectionExists
+	bookmarkNotFound
+	bookmarkItemNotFound
+	userSuspended
+	notModerator
+	notUserOrModerator
+	reportNotFound
+	reportExists
+	reportResolved
+	reportTargetNotFound
 	unknown
 )
 
@@ -38,8 +68,38 @@ func (e *Error) Error() string {
 		return fmt.Sprintf("%v", e.err)
 	case userNotFound:
 		return fmt.Sprintf("User not found %v", e.err)
+	case userVersionMismatch:
+		return fmt.Sprintf("User version mismatch %v", e.err)
 	case wrongEmailOrPassword:
 		return fmt.Sprintf("Wrong email or password %v", e.err)
+	case conversationNotFound:
+		return fmt.Sprintf("Conversation not found %v", e.err)
+	case messagingRestricted:
+		return fmt.Sprintf("Messaging restricted %v", e.err)
+	case badCursor:
+		return fmt.Sprintf("Bad cursor %v", e.err)
+	case collectionNotFound:
+		return fmt.Sprintf("Collection not found %v", e.err)
+	case collectionExists:
+		return fmt.Sprintf("Collection already exists %v", e.err)
+	case bookmarkNotFound:
+		return fmt.Sprintf("Bookmark not found %v", e.err)
+	case bookmarkItemNotFound:
+		return fmt.Sprintf("Bookmarked item not found %v", e.err)
+	case userSuspended:
+		return fmt.Sprintf("User suspended %v", e.err)
+	case notModerator:
+		return fmt.Sprintf("Not a moderator %v", e.err)
+	case notUserOrModerator:
+		return fmt.Sprintf("Neither the user nor a moderator %v", e.err)
+	case reportNotFound:
+		return fmt.Sprintf("Report not found %v", e.err)
+	case reportExists:
+		return fmt.Sprintf("Report already exists %v", e.err)
+	case reportResolved:
+		return fmt.Sprintf("Report already resolved %v", e.err)
+	case reportTargetNotFound:
+		return fmt.Sprintf("Reported content not found %v", e.err)
 	default:
 		return fmt.Sprintf("Unknown error %v", e.err)
 	}
@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"userservice/internal/dto/req"
+	"userservice/internal/dto/resp"
+	"userservice/internal/logging"
+	"userservice/internal/model"
+	"userservice/internal/repository"
+	"userservice/internal/util"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/exp/slog"
+)
+
+type moderationUsecase struct {
+	logger                 *slog.Logger
+	validate               *validator.Validate
+	reportRepository       repository.IReportRepository
+	userRepository         repository.IUserRepository
+	conversationRepository repository.IConversationRepository
+}
+
+func NewModerationUsecase(logger *slog.Logger, validate *validator.Validate, reportRepository repository.IReportRepository, userRepository repository.IUserRepository, conversationRepository repository.IConversationRepository) IModerationUsecase {
+	return &moderationUsecase{
+		logger:                 logger,
+		validate:               validate,
+		reportRepository:       reportRepository,
+		userRepository:         userRepository,
+		conversationRepository: conversationRepository,
+	}
+}
+
+// requireModerator reads the role from the repository instead of trusting
+// the caller, so a demoted moderator loses access before their token
+// expires, at the latest once the user cache TTL has passed.
+func (mu moderationUsecase) requireModerator(ctx context.Context, userID int) error {
+	user, err := mu.userRepository.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return ErrNotModerator.SetError(err)
+		}
+		return ErrUnknown.SetError(err)
+	}
+	if user.Role != model.UserRoleModerator || user.IsSuspended(time.Now()) {
+		return ErrNotModerator.SetError(fmt.Errorf("user %d", userID))
+	}
+	return nil
+}
+
+func (mu moderationUsecase) CreateReport(ctx context.Context, reporterID int, reportDto *req.ReportDto) (*resp.ReportDto, error) {
+	const scope = "moderationUsecase#CreateReport"
+	if err := validateDto(mu.validate, reportDto); err != nil {
+		logging.FromContext(ctx, mu.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	exists, err := mu.reportRepository.TargetExists(ctx, reporterID, reportDto.TargetType, reportDto.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", scope, ErrReportTargetNotFound.SetError(fmt.Errorf("%s %d", reportDto.TargetType, reportDto.TargetID)))
+	}
+	report, err := mu.reportRepository.CreateReport(ctx, reporterID, reportDto.TargetType, reportDto.TargetID, reportDto.ReasonCode, reportDto.Details)
+	if err != nil {
+		logging.FromContext(ctx, mu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, &repository.ErrUniqueViolation) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrReportExists.SetError(err))
+		}
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrUserNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, mu.logger).Info(
+		"Created a report",
+		slog.String("scope", scope),
+	)
+	return report.ToDto(), nil
+}
+
+func (mu moderationUsecase) FindReports(ctx context.Context, moderatorID int, reportFilterDto *req.ReportFilterDto, pageDto *req.PageDto) (*resp.ReportPageDto, error) {
+	const scope = "moderationUsecase#FindReports"
+	if err := mu.requireModerator(ctx, moderatorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	if err := validateDto(mu.validate, reportFilterDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	if err := validateDto(mu.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	afterID, err := beforeIDFromCursor(pageDto.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrBadCursor.SetError(err))
+	}
+	status := reportFilterDto.Status
+	if status == "" {
+		status = model.ReportStatusOpen
+	}
+	limit := pageLimit(pageDto)
+	reports, err := mu.reportRepository.FindReports(ctx, status, afterID, limit+1)
+	if err != nil {
+		logging.FromContext(ctx, mu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	result := &resp.ReportPageDto{
+		Reports: []*resp.ReportDto{},
+	}
+	if len(reports) > limit {
+		reports = reports[:limit]
+		result.NextCursor = util.EncodeCursor(int64(reports[limit-1].ID))
+	}
+	for _, report := range reports {
+		result.Reports = append(result.Reports, report.ToDto())
+	}
+	logging.FromContext(ctx, mu.logger).Info(
+		"Found reports",
+		slog.String("scope", scope),
+	)
+	return result, nil
+}
+
+func (mu moderationUsecase) FindReportByID(ctx context.Context, moderatorID int, id int) (*resp.ReportDto, error) {
+	const scope = "moderationUsecase#FindReportByID"
+	if err := mu.requireModerator(ctx, moderatorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	report, err := mu.reportRepository.FindReportByID(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx, mu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrReportNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, mu.logger).Info(
+		"Found a report by its ID",
+		slog.String("scope", scope),
+	)
+	return report.ToDto(), nil
+}
+
+// TakeAction applies the action before resolving the report. Both effects
+// are idempotent, so a moderator racing another one at worst repeats them.
+func (mu moderationUsecase) TakeAction(ctx context.Context, moderatorID int, reportID int, moderationActionDto *req.ModerationActionDto) (*resp.ModerationActionDto, error) {
+	const scope = "moderationUsecase#TakeAction"
+	if err := mu.requireModerator(ctx, moderatorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	if err := validateDto(mu.validate, moderationActionDto); err != nil {
+		logging.FromContext(ctx, mu.logger).Error(
+			"Failed to validate input",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	report, err := mu.reportRepository.FindReportByID(ctx, reportID)
+	if err != nil {
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrReportNotFound.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	if report.Status != model.ReportStatusOpen {
+		return nil, fmt.Errorf("%s: %w", scope, ErrReportResolved.SetError(fmt.Errorf("report %d", reportID)))
+	}
+	// The owner is gone when the target was already removed, which only
+	// matters when there is someone left to suspend.
+	targetUserID, err := mu.reportRepository.TargetOwnerID(ctx, report.TargetType, report.TargetID)
+	if err != nil && !errors.Is(err, &repository.ErrDataNotFound) {
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	action := &model.ModerationAction{
+		ModeratorID:  moderatorID,
+		Action:       moderationActionDto.Action,
+		TargetUserID: targetUserID,
+		Note:         moderationActionDto.Note,
+	}
+	switch moderationActionDto.Action {
+	case model.ModerationActionSuspend:
+		if targetUserID == nil {
+			return nil, fmt.Errorf("%s: %w", scope, ErrReportTargetNotFound.SetError(fmt.Errorf("%s %d has no owner", report.TargetType, report.TargetID)))
+		}
+		suspendedUntil := time.Now().Add(time.Duration(*moderationActionDto.SuspendHours) * time.Hour)
+		user, err := mu.userRepository.Suspend(ctx, *targetUserID, suspendedUntil)
+		if err != nil {
+			if errors.Is(err, &repository.ErrDataNotFound) {
+				return nil, fmt.Errorf("%s: %w", scope, ErrReportTargetNotFound.SetError(err))
+			}
+			return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+		}
+		action.SuspendedUntil = user.SuspendedUntil
+	case model.ModerationActionRemove:
+		if report.TargetType == model.ReportTargetTypeMessage {
+			_, err = mu.conversationRepository.DeleteMessage(ctx, report.TargetID)
+		} else {
+			_, err = mu.userRepository.DeleteByID(ctx, report.TargetID, nil)
+		}
+		if err != nil && !errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+		}
+	}
+	result, err := mu.reportRepository.Resolve(ctx, reportID, action)
+	if err != nil {
+		logging.FromContext(ctx, mu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrReportResolved.SetError(err))
+		}
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	logging.FromContext(ctx, mu.logger).Info(
+		"Took a moderation action",
+		slog.String("scope", scope),
+		slog.String("action", result.Action),
+	)
+	return result.ToDto(), nil
+}
+
+func (mu moderationUsecase) FindActions(ctx context.Context, moderatorID int, pageDto *req.PageDto) (*resp.ModerationActionPageDto, error) {
+	const scope = "moderationUsecase#FindActions"
+	if err := mu.requireModerator(ctx, moderatorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	if err := validateDto(mu.validate, pageDto); err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	beforeID, err := beforeIDFromCursor(pageDto.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scope, ErrBadCursor.SetError(err))
+	}
+	limit := pageLimit(pageDto)
+	actions, err := mu.reportRepository.FindActions(ctx, beforeID, limit+1)
+	if err != nil {
+		logging.FromContext(ctx, mu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	result := &resp.ModerationActionPageDto{
+		Actions: []*resp.ModerationActionDto{},
+	}
+	if len(actions) > limit {
+		actions = actions[:limit]
+		result.NextCursor = util.EncodeCursor(int64(actions[limit-1].ID))
+	}
+	for _, action := range actions {
+		result.Actions = append(result.Actions, action.ToDto())
+	}
+	logging.FromContext(ctx, mu.logger).Info(
+		"Found moderation actions",
+		slog.String("scope", scope),
+	)
+	return result, nil
+}
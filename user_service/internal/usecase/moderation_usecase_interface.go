@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+	"userservice/internal/dto/req"
+	"userservice/internal/dto/resp"
+)
+
+type IModerationUsecase interface {
+	CreateReport(ctx context.Context, reporterID int, reportDto *req.ReportDto) (*resp.ReportDto, error)
+	FindReports(ctx context.Context, moderatorID int, reportFilterDto *req.ReportFilterDto, pageDto *req.PageDto) (*resp.ReportPageDto, error)
+	FindReportByID(ctx context.Context, moderatorID int, id int) (*resp.ReportDto, error)
+	TakeAction(ctx context.Context, moderatorID int, reportID int, moderationActionDto *req.ModerationActionDto) (*resp.ModerationActionDto, error)
+	FindActions(ctx context.Context, moderatorID int, pageDto *req.PageDto) (*resp.ModerationActionPageDto, error)
+}
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 	"userservice/internal/dto/req"
 	"userservice/internal/dto/resp"
+	"userservice/internal/logging"
+	"userservice/internal/metrics"
+	"userservice/internal/model"
 	"userservice/internal/repository"
-	"userservice/internal/util"
 
 	"github.com/go-playground/validator/v10"
 	"golang.org/x/crypto/bcrypt"
@@ -19,13 +22,15 @@ type userUsecase struct {
 	logger         *slog.Logger
 	validate       *validator.Validate
 	userRepository repository.IUserRepository
+	metrics        *metrics.Metrics
 }
 
-func NewUserUsecase(logger *slog.Logger, validate *validator.Validate, userRepository repository.IUserRepository) IUserUsecase {
+func NewUserUsecase(logger *slog.Logger, validate *validator.Validate, userRepository repository.IUserRepository, metrics *metrics.Metrics) IUserUsecase {
 	return &userUsecase{
 		logger:         logger,
 		validate:       validate,
 		userRepository: userRepository,
+		metrics:        metrics,
 	}
 }
 
@@ -33,10 +38,9 @@ func (uu userUsecase) FindByID(ctx context.Context, id int) (*resp.UserDto, erro
 	const scope = "userUsecase#FindByID"
 	user, err := uu.userRepository.FindByID(ctx, id)
 	if err != nil {
-		uu.logger.Error(
+		logging.FromContext(ctx, uu.logger).Error(
 			"Got error from repository",
 			err,
-			slog.String("request_id", ctx.Value(util.RequestID).(string)),
 			slog.String("scope", scope),
 		)
 		if errors.Is(err, &repository.ErrDataNotFound) {
@@ -44,55 +48,149 @@ func (uu userUsecase) FindByID(ctx context.Context, id int) (*resp.UserDto, erro
 		}
 		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
 	}
-	uu.logger.Info(
+	logging.FromContext(ctx, uu.logger).Info(
 		"Found a user by its ID",
-		slog.String("request_id", ctx.Value(util.RequestID).(string)),
 		slog.String("scope", scope),
 	)
 	return user.ToDto(), err
 }
 
-func (uu userUsecase) DeleteByID(ctx context.Context, id int) (*resp.UserDto, error) {
+// FindByIDs returns the users in the order their IDs were first requested,
+// along with the requested IDs that have no user.
+func (uu userUsecase) FindByIDs(ctx context.Context, userIDsDto *req.UserIDsDto) (*resp.UsersDto, error) {
+	const scope = "userUsecase#FindByIDs"
+	if err := validateDto(uu.validate, userIDsDto); err != nil {
+		logging.FromContext(ctx, uu.logger).Error(
+			"Failed to validate user IDs",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(err))
+	}
+	ids := []int{}
+	seen := map[int]bool{}
+	for _, id := range userIDsDto.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	users, err := uu.userRepository.FindByIDs(ctx, ids)
+	if err != nil {
+		logging.FromContext(ctx, uu.logger).Error(
+			"Got error from repository",
+			err,
+			slog.String("scope", scope),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
+	}
+	usersByID := map[int]*resp.UserDto{}
+	for _, user := range users {
+		usersByID[user.ID] = user.ToDto()
+	}
+	usersDto := &resp.UsersDto{
+		Users:      []*resp.UserDto{},
+		MissingIDs: []int{},
+	}
+	for _, id := range ids {
+		if user, ok := usersByID[id]; ok {
+			usersDto.Users = append(usersDto.Users, user)
+		} else {
+			usersDto.MissingIDs = append(usersDto.MissingIDs, id)
+		}
+	}
+	logging.FromContext(ctx, uu.logger).Info(
+		"Found users by their IDs",
+		slog.String("scope", scope),
+		slog.Int("found", len(usersDto.Users)),
+		slog.Int("missing", len(usersDto.MissingIDs)),
+	)
+	return usersDto, nil
+}
+
+// authorizeDelete lets users delete themselves and moderators delete anyone.
+// The role is read from the repository like requireModerator does.
+func (uu userUsecase) authorizeDelete(ctx context.Context, callerID int, id int) error {
+	if callerID == 0 {
+		return ErrNotUserOrModerator.SetError(errors.New("no caller"))
+	}
+	if callerID == id {
+		return nil
+	}
+	caller, err := uu.userRepository.FindByID(ctx, callerID)
+	if err != nil {
+		if errors.Is(err, &repository.ErrDataNotFound) {
+			return ErrNotUserOrModerator.SetError(err)
+		}
+		return ErrUnknown.SetError(err)
+	}
+	if caller.Role != model.UserRoleModerator || caller.IsSuspended(time.Now()) {
+		return ErrNotUserOrModerator.SetError(fmt.Errorf("user %d", callerID))
+	}
+	return nil
+}
+
+func (uu userUsecase) DeleteByID(ctx context.Context, callerID int, id int, versions []int) (*resp.UserDto, error) {
 	const scope = "userUsecase#DeleteByID"
-	user, err := uu.userRepository.DeleteByID(ctx, id)
+	if err := uu.authorizeDelete(ctx, callerID, id); err != nil {
+		logging.FromContext(ctx, uu.logger).Warn(
+			"Rejected the deletion",
+			slog.String("scope", scope),
+			slog.Int("caller_id", callerID),
+			slog.Int("user_id", id),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	user, err := uu.userRepository.DeleteByID(ctx, id, versions)
 	if err != nil {
-		uu.logger.Error(
+		logging.FromContext(ctx, uu.logger).Error(
 			"Got error from repository",
 			err,
-			slog.String("request_id", ctx.Value(util.RequestID).(string)),
 			slog.String("scope", scope),
 		)
 		if errors.Is(err, &repository.ErrDataNotFound) {
 			return nil, fmt.Errorf("%s: %w", scope, ErrUserNotFound.SetError(err))
 		}
+		if errors.Is(err, &repository.ErrVersionMismatch) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrUserVersionMismatch.SetError(err))
+		}
 		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
 	}
-	uu.logger.Info(
+	logging.FromContext(ctx, uu.logger).Info(
 		"Soft deleted a user by its ID",
-		slog.String("request_id", ctx.Value(util.RequestID).(string)),
 		slog.String("scope", scope),
 	)
 	return user.ToDto(), err
 }
 
-func (uu userUsecase) DeletePermanentlyByID(ctx context.Context, id int) (*resp.UserDto, error) {
+func (uu userUsecase) DeletePermanentlyByID(ctx context.Context, callerID int, id int, versions []int) (*resp.UserDto, error) {
 	const scope = "userUsecase#DeletePermanentlyByID"
-	user, err := uu.userRepository.DeletePermanentlyByID(ctx, id)
+	if err := uu.authorizeDelete(ctx, callerID, id); err != nil {
+		logging.FromContext(ctx, uu.logger).Warn(
+			"Rejected the deletion",
+			slog.String("scope", scope),
+			slog.Int("caller_id", callerID),
+			slog.Int("user_id", id),
+		)
+		return nil, fmt.Errorf("%s: %w", scope, err)
+	}
+	user, err := uu.userRepository.DeletePermanentlyByID(ctx, id, versions)
 	if err != nil {
-		uu.logger.Error(
+		logging.FromContext(ctx, uu.logger).Error(
 			"Got error from repository",
 			err,
-			slog.String("request_id", ctx.Value(util.RequestID).(string)),
 			slog.String("scope", scope),
 		)
 		if errors.Is(err, &repository.ErrDataNotFound) {
 			return nil, fmt.Errorf("%s: %w", scope, ErrUserNotFound.SetError(err))
 		}
+		if errors.Is(err, &repository.ErrVersionMismatch) {
+			return nil, fmt.Errorf("%s: %w", scope, ErrUserVersionMismatch.SetError(err))
+		}
 		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
 	}
-	uu.logger.Info(
+	logging.FromContext(ctx, uu.logger).Info(
 		"Deleted a user permanently by its ID",
-		slog.String("request_id", ctx.Value(util.RequestID).(string)),
 		slog.String("scope", scope),
 	)
 	return user.ToDto(), err
@@ -102,10 +200,9 @@ func (uu userUsecase) Register(ctx context.Context, userDto *req.UserDto) (*resp
 	const scope = "userUsecase#Register"
 	err := uu.validate.Struct(userDto)
 	if err != nil {
-		uu.logger.Error(
+		logging.FromContext(ctx, uu.logger).Error(
 			"Failed to validate input",
 			err,
-			slog.String("request_id", ctx.Value(util.RequestID).(string)),
 			slog.String("scope", scope),
 		)
 		validatorErrors := err.(validator.ValidationErrors)
@@ -115,12 +212,13 @@ func (uu userUsecase) Register(ctx context.Context, userDto *req.UserDto) (*resp
 		}
 		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(errors.New(strings.Join(errorMessages, ", "))))
 	}
+	start := time.Now()
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(userDto.Password), bcrypt.DefaultCost)
+	uu.metrics.BcryptDuration.WithLabelValues(metrics.BcryptOperationHash).Observe(time.Since(start).Seconds())
 	if err != nil {
-		uu.logger.Error(
+		logging.FromContext(ctx, uu.logger).Error(
 			"Failed to hash password",
 			err,
-			slog.String("request_id", ctx.Value(util.RequestID).(string)),
 			slog.String("scope", scope),
 		)
 		return nil, fmt.Errorf("%s: %w", scope, ErrFailHashingPassword.SetError(err))
@@ -128,10 +226,9 @@ func (uu userUsecase) Register(ctx context.Context, userDto *req.UserDto) (*resp
 	userDto.Password = string(hashedPassword)
 	user, err := uu.userRepository.Create(ctx, userDto)
 	if err != nil {
-		uu.logger.Error(
+		logging.FromContext(ctx, uu.logger).Error(
 			"Got error from repository",
 			err,
-			slog.String("request_id", ctx.Value(util.RequestID).(string)),
 			slog.String("scope", scope),
 		)
 		if errors.Is(err, &repository.ErrUniqueViolation) {
@@ -139,9 +236,8 @@ func (uu userUsecase) Register(ctx context.Context, userDto *req.UserDto) (*resp
 		}
 		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
 	}
-	uu.logger.Info(
+	logging.FromContext(ctx, uu.logger).Info(
 		"Created a user",
-		slog.String("request_id", ctx.Value(util.RequestID).(string)),
 		slog.String("scope", scope),
 	)
 	return user.ToDto(), err
@@ -151,10 +247,9 @@ func (uu userUsecase) Login(ctx context.Context, loginDto *req.LoginDto) (*resp.
 	const scope = "userUsecase#Login"
 	err := uu.validate.Struct(loginDto)
 	if err != nil {
-		uu.logger.Error(
+		logging.FromContext(ctx, uu.logger).Error(
 			"Failed to validate input",
 			err,
-			slog.String("request_id", ctx.Value(util.RequestID).(string)),
 			slog.String("scope", scope),
 		)
 		validatorErrors := err.(validator.ValidationErrors)
@@ -164,25 +259,37 @@ func (uu userUsecase) Login(ctx context.Context, loginDto *req.LoginDto) (*resp.
 		}
 		return nil, fmt.Errorf("%s: %w", scope, ErrFailToValidate.SetError(errors.New(strings.Join(errorMessages, ", "))))
 	}
-	user, err := uu.userRepository.FindByEmail(ctx, loginDto.Email)
+	user, err := uu.userRepository.FindCredentialsByEmail(ctx, loginDto.Email)
 	if err != nil {
-		uu.logger.Error(
+		logging.FromContext(ctx, uu.logger).Error(
 			"Got error from repository",
 			err,
-			slog.String("request_id", ctx.Value(util.RequestID).(string)),
 			slog.String("scope", scope),
 		)
 		if errors.Is(err, &repository.ErrDataNotFound) {
+			uu.metrics.LoginsTotal.WithLabelValues(metrics.LoginResultWrongCredentials).Inc()
 			return nil, fmt.Errorf("%s: %w", scope, ErrWrongEmailOrPassword.SetError(err))
 		}
+		uu.metrics.LoginsTotal.WithLabelValues(metrics.LoginResultError).Inc()
 		return nil, fmt.Errorf("%s: %w", scope, ErrUnknown.SetError(err))
 	}
+	start := time.Now()
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginDto.Password))
+	uu.metrics.BcryptDuration.WithLabelValues(metrics.BcryptOperationCompare).Observe(time.Since(start).Seconds())
 	if err != nil {
+		uu.metrics.LoginsTotal.WithLabelValues(metrics.LoginResultWrongCredentials).Inc()
 		return nil, fmt.Errorf("%s: %w", scope, ErrWrongEmailOrPassword.SetError(err))
 	}
+	// The password is checked first so suspensions are not disclosed to
+	// someone who does not own the account.
+	if user.IsSuspended(time.Now()) {
+		uu.metrics.LoginsTotal.WithLabelValues(metrics.LoginResultSuspended).Inc()
+		return nil, fmt.Errorf("%s: %w", scope, ErrUserSuspended.SetError(fmt.Errorf("until %s", user.SuspendedUntil.UTC().Format(time.RFC3339))))
+	}
+	uu.metrics.LoginsTotal.WithLabelValues(metrics.LoginResultSuccess).Inc()
 	return &resp.LoginDto{
 		ID:    user.ID,
 		Email: user.Email,
+		Role:  user.Role,
 	}, nil
 }
@@ -8,8 +8,9 @@ import (
 
 type IUserUsecase interface {
 	FindByID(ctx context.Context, id int) (*resp.UserDto, error)
-	DeleteByID(ctx context.Context, id int) (*resp.UserDto, error)
-	DeletePermanentlyByID(ctx context.Context, id int) (*resp.UserDto, error)
+	FindByIDs(ctx context.Context, userIDsDto *req.UserIDsDto) (*resp.UsersDto, error)
+	DeleteByID(ctx context.Context, callerID int, id int, versions []int) (*resp.UserDto, error)
+	DeletePermanentlyByID(ctx context.Context, callerID int, id int, versions []int) (*resp.UserDto, error)
 	Register(ctx context.Context, userDto *req.UserDto) (*resp.UserDto, error)
 	Login(ctx context.Context, loginDto *req.LoginDto) (*resp.LoginDto, error)
 }